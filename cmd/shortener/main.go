@@ -3,11 +3,12 @@ package main
 import (
 	"context"
 	"errors"
+	"flag"
 	"fmt"
 	"net/http"
 
 	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/config"
-	http_server "github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/server/http"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/server"
 )
 
 var (
@@ -17,19 +18,22 @@ var (
 )
 
 func main() {
-	if err := runHTTP(); err != nil {
+	if err := run(); err != nil {
 		panic(err)
 	}
 }
 
-func runHTTP() error {
+func run() error {
 	fmt.Printf("Build version: %s\nBuild date: %s\nBuild commit: %s\n", buildVersion, buildDate, buildCommit)
 	ctx := context.Background()
 	config, err := config.New()
 	if err != nil {
 		return err
 	}
-	if err := http_server.StartServer(ctx, config); err != nil {
+	if args := flag.Args(); len(args) > 0 && args[0] == "migrate" {
+		return runMigrate(ctx, config, args[1:])
+	}
+	if err := server.StartServer(ctx, config); err != nil {
 		if errors.Is(err, http.ErrServerClosed) {
 			return nil
 		}
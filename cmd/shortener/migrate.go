@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/config"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/logger"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/storage/postgres"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const migrateUsage = "usage: shortener [flags] migrate up | down [steps] | status"
+
+// runMigrate выполняет подкоманду migrate над базой данных из конфигурации:
+// up применяет все непримененные миграции, down откатывает steps последних (по умолчанию одну),
+// status выводит состояние миграций.
+func runMigrate(ctx context.Context, config config.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+	if config.DatabaseURL == "" {
+		return errors.New("database URL isn't set")
+	}
+	if err := logger.Init(slog.LevelInfo); err != nil {
+		return err
+	}
+	pool, err := pgxpool.New(ctx, config.DatabaseURL)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+	migrator, err := postgres.NewMigrator(pool)
+	if err != nil {
+		return err
+	}
+	switch {
+	case args[0] == "up" && len(args) == 1:
+		return migrator.Up(ctx)
+	case args[0] == "down" && len(args) <= 2:
+		steps := 1
+		if len(args) == 2 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil {
+				return fmt.Errorf("invalid number of steps %q: %w", args[1], err)
+			}
+		}
+		return migrator.Down(ctx, steps)
+	case args[0] == "status" && len(args) == 1:
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "VERSION\tNAME\tAPPLIED AT")
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if !status.AppliedAt.IsZero() {
+				appliedAt = status.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(writer, "%d\t%s\t%s\n", status.Version, status.Name, appliedAt)
+		}
+		return writer.Flush()
+	default:
+		return errors.New(migrateUsage)
+	}
+}
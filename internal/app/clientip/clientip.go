@@ -0,0 +1,98 @@
+// Package clientip определяет IP-адрес клиента с учетом доверенных прокси.
+//
+// Адрес клиента берется из адреса соединения. Заголовкам X-Real-IP и X-Forwarded-For доверяется,
+// только если соединение пришло от доверенного прокси, иначе клиент мог бы подставлять новый адрес
+// в каждом запросе. Определение общее для HTTP и gRPC серверов: его используют ограничение частоты запросов
+// и статистика переходов.
+package clientip
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// Resolver определяет IP-адрес клиента. Resolver, равный nil, не доверяет ни одному прокси.
+type Resolver struct {
+	proxies []*net.IPNet
+}
+
+// New создает Resolver по списку CIDR-масок доверенных прокси через запятую.
+func New(trustedProxies string) (*Resolver, error) {
+	resolver := &Resolver{}
+	for _, cidr := range strings.Split(trustedProxies, ",") {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+		_, proxy, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("trusted proxies: %w", err)
+		}
+		resolver.proxies = append(resolver.proxies, proxy)
+	}
+	return resolver, nil
+}
+
+// ClientIP возвращает IP-адрес клиента для адреса соединения remoteAddr и значения заголовка X-Real-IP realIP.
+// Значение realIP используется, только если соединение пришло от доверенного прокси.
+func (resolver *Resolver) ClientIP(remoteAddr, realIP string) string {
+	host := hostOf(remoteAddr)
+	if realIP == "" || !resolver.trusted(host) {
+		return host
+	}
+	if ip := net.ParseIP(strings.TrimSpace(realIP)); ip != nil {
+		return ip.String()
+	}
+	return host
+}
+
+// FromRequest возвращает IP-адрес клиента HTTP-запроса r. Если запрос пришел от доверенного прокси,
+// адрес берется из заголовка X-Real-IP, а без него — из X-Forwarded-For: последний адрес цепочки,
+// который не принадлежит доверенному прокси.
+func (resolver *Resolver) FromRequest(r *http.Request) string {
+	host := hostOf(r.RemoteAddr)
+	if !resolver.trusted(host) {
+		return host
+	}
+	if realIP := r.Header.Get("X-Real-IP"); realIP != "" {
+		return resolver.ClientIP(r.RemoteAddr, realIP)
+	}
+	forwarded := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		ip := net.ParseIP(strings.TrimSpace(forwarded[i]))
+		if ip == nil {
+			return host
+		}
+		if !resolver.trusted(ip.String()) {
+			return ip.String()
+		}
+	}
+	return host
+}
+
+// trusted проверяет, что host является адресом доверенного прокси.
+func (resolver *Resolver) trusted(host string) bool {
+	if resolver == nil {
+		return false
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	for _, proxy := range resolver.proxies {
+		if proxy.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func hostOf(remoteAddr string) string {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return remoteAddr
+	}
+	return host
+}
@@ -0,0 +1,73 @@
+package clientip
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientIP(t *testing.T) {
+	resolver, err := New("10.0.0.0/8, 192.168.1.1/32")
+	require.NoError(t, err)
+
+	assert.Equal(t, "203.0.113.7", resolver.ClientIP("10.1.2.3:5000", "203.0.113.7"))
+	assert.Equal(t, "203.0.113.7", resolver.ClientIP("192.168.1.1:5000", "203.0.113.7"))
+	// Заголовок клиента не от доверенного прокси игнорируется
+	assert.Equal(t, "198.51.100.1", resolver.ClientIP("198.51.100.1:5000", "203.0.113.7"))
+	assert.Equal(t, "10.1.2.3", resolver.ClientIP("10.1.2.3:5000", "not an ip"))
+	assert.Equal(t, "10.1.2.3", resolver.ClientIP("10.1.2.3:5000", ""))
+
+	var nilResolver *Resolver
+	assert.Equal(t, "198.51.100.1", nilResolver.ClientIP("198.51.100.1:5000", "203.0.113.7"))
+
+	_, err = New("10.0.0.0/8,proxy")
+	assert.Error(t, err)
+}
+
+func TestFromRequest(t *testing.T) {
+	resolver, err := New("10.0.0.0/8")
+	require.NoError(t, err)
+	tests := []struct {
+		name       string
+		remoteAddr string
+		headers    map[string]string
+		expectedIP string
+	}{
+		{
+			name:       "x-real-ip from trusted proxy",
+			remoteAddr: "10.0.0.1:5000",
+			headers:    map[string]string{"X-Real-IP": "203.0.113.7", "X-Forwarded-For": "203.0.113.8"},
+			expectedIP: "203.0.113.7",
+		},
+		{
+			name:       "last untrusted x-forwarded-for address",
+			remoteAddr: "10.0.0.1:5000",
+			headers:    map[string]string{"X-Forwarded-For": "1.1.1.1, 203.0.113.8, 10.0.0.2"},
+			expectedIP: "203.0.113.8",
+		},
+		{
+			name:       "headers from untrusted client",
+			remoteAddr: "198.51.100.1:5000",
+			headers:    map[string]string{"X-Real-IP": "203.0.113.7", "X-Forwarded-For": "203.0.113.8"},
+			expectedIP: "198.51.100.1",
+		},
+		{
+			name:       "trusted proxy without headers",
+			remoteAddr: "10.0.0.1:5000",
+			expectedIP: "10.0.0.1",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "/abc", nil)
+			request.RemoteAddr = test.remoteAddr
+			for name, value := range test.headers {
+				request.Header.Set(name, value)
+			}
+			assert.Equal(t, test.expectedIP, resolver.FromRequest(request))
+		})
+	}
+}
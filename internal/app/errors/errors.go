@@ -2,13 +2,32 @@
 //
 // Структура CustomError представляет пользовательскую ошибку. Она содержит оригинальную ошибку Err, статус HTTP, тело ответа, тип содержимого и короткий URL.
 // Метод Error() позволяет структуре CustomError удовлетворять интерфейсу error.
-// Функции NewCustomError, NewCustomErrorInternal и NewCustomErrorBadRequest создают новые экземпляры CustomError с различными статусами HTTP.
+// Функции NewCustomError, NewCustomErrorInternal, NewCustomErrorBadRequest и NewCustomErrorConflict создают новые экземпляры CustomError с различными статусами HTTP.
+// NewCustomErrorValidation создает ошибку 400 с телом ValidationError в формате JSON.
+// NewCustomErrorRejected создает ошибку 422 с телом ValidationError для URL, отклоненного проверкой.
 package errors
 
 import (
+	"encoding/json"
+	"errors"
 	"net/http"
 )
 
+// ErrShortURLAlreadyExists возвращается хранилищем, если сокращенный URL уже занят.
+var ErrShortURLAlreadyExists = errors.New("short url already exists")
+
+// ErrURLNotFound возвращается хранилищем, если URL не найден.
+var ErrURLNotFound = errors.New("original url isn't found")
+
+// ErrOriginalURLAlreadyExists возвращается хранилищем, если пользователь уже сокращал исходный URL, а запрос требует дедупликации.
+var ErrOriginalURLAlreadyExists = errors.New("original url already exists")
+
+// ErrDeletionJobNotFound возвращается хранилищем, если задание на удаление не найдено.
+var ErrDeletionJobNotFound = errors.New("deletion job isn't found")
+
+// ErrUserNotFound возвращается хранилищем, если пользователь не зарегистрирован.
+var ErrUserNotFound = errors.New("user isn't found")
+
 // CustomError представляет пользовательскую ошибку.
 type CustomError struct {
 	Err         error
@@ -23,6 +42,11 @@ func (customErr CustomError) Error() string {
 	return customErr.Err.Error()
 }
 
+// Unwrap возвращает оригинальную ошибку.
+func (customErr CustomError) Unwrap() error {
+	return customErr.Err
+}
+
 // NewCustomError создает новый экземпляр CustomError с заданной оригинальной ошибкой.
 func NewCustomError(err error) *CustomError {
 	return &CustomError{
@@ -45,3 +69,57 @@ func NewCustomErrorBadRequest(err error) *CustomError {
 		Status: http.StatusBadRequest,
 	}
 }
+
+// NewCustomErrorOriginalURLConflict создает новый экземпляр CustomError со статусом HTTP 409 (конфликт)
+// и сокращенным URL, который уже создан для исходного URL.
+func NewCustomErrorOriginalURLConflict(shortURL string) *CustomError {
+	return &CustomError{
+		Err:      ErrOriginalURLAlreadyExists,
+		Status:   http.StatusConflict,
+		ShortURL: shortURL,
+	}
+}
+
+// ValidationError описывает ошибку проверки поля запроса. Возвращается клиенту в теле ответа 400 в формате JSON.
+type ValidationError struct {
+	Field   string `json:"field"`   // Field поле запроса, не прошедшее проверку.
+	Code    string `json:"code"`    // Code машиночитаемый код ошибки.
+	Message string `json:"message"` // Message описание ошибки.
+}
+
+// Error возвращает описание ошибки проверки.
+func (validationErr ValidationError) Error() string {
+	return validationErr.Message
+}
+
+// NewCustomErrorValidation создает новый экземпляр CustomError со статусом HTTP 400 (неверный запрос)
+// и телом в формате JSON, описывающим ошибку проверки поля field.
+func NewCustomErrorValidation(field, code, message string) *CustomError {
+	validationErr := ValidationError{Field: field, Code: code, Message: message}
+	body, _ := json.Marshal(validationErr)
+	return &CustomError{
+		Err:         validationErr,
+		Status:      http.StatusBadRequest,
+		Body:        body,
+		ContentType: "application/json",
+	}
+}
+
+// RejectedURLCode код ошибки проверки для URL, отклоненного проверкой на вредоносность.
+const RejectedURLCode = "url_rejected"
+
+// NewCustomErrorRejected создает новый экземпляр CustomError со статусом HTTP 422 (необрабатываемый запрос)
+// и телом в формате JSON с причиной, по которой исходный URL поля field отклонен проверкой.
+func NewCustomErrorRejected(field, reason string) *CustomError {
+	customErr := NewCustomErrorValidation(field, RejectedURLCode, reason)
+	customErr.Status = http.StatusUnprocessableEntity
+	return customErr
+}
+
+// NewCustomErrorConflict создает новый экземпляр CustomError с оригинальной ошибкой и статусом HTTP 409 (конфликт).
+func NewCustomErrorConflict(err error) *CustomError {
+	return &CustomError{
+		Err:    err,
+		Status: http.StatusConflict,
+	}
+}
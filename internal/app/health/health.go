@@ -0,0 +1,163 @@
+// Package health проверяет живость и готовность сервиса.
+//
+// Живость означает только то, что процесс отвечает на запросы. Готовность проверяет каждый компонент,
+// от которого зависит обработка запросов, — хранилище, кеш, фоновые workers — с отдельным временем ожидания
+// и возвращает подробное состояние компонентов. Во время остановки сервис неготов независимо от компонентов,
+// чтобы балансировщик перестал направлять ему запросы.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/logger"
+)
+
+// Состояния сервиса.
+const (
+	StatusReady        = "ready"
+	StatusUnready      = "unready"
+	StatusShuttingDown = "shutting_down"
+)
+
+// Состояния компонента.
+const (
+	ComponentUp   = "up"
+	ComponentDown = "down"
+)
+
+// DefaultTimeout время ожидания проверки компонента, если оно не задано.
+const DefaultTimeout = 2 * time.Second
+
+// CheckFunc проверяет компонент и возвращает ошибку, если он недоступен.
+type CheckFunc func(ctx context.Context) error
+
+// Component компонент, от которого зависит готовность сервиса.
+type Component struct {
+	// Name название компонента в отчете.
+	Name string
+	// Check проверка компонента.
+	Check CheckFunc
+}
+
+// ComponentStatus состояние компонента.
+type ComponentStatus struct {
+	Status     string  `json:"status"`
+	Error      string  `json:"error,omitempty"`
+	DurationMS float64 `json:"duration_ms"`
+}
+
+// Report отчет о готовности сервиса.
+type Report struct {
+	Status     string                     `json:"status"`
+	Components map[string]ComponentStatus `json:"components"`
+}
+
+// Ready возвращает true, если сервис готов обрабатывать запросы.
+func (report Report) Ready() bool {
+	return report.Status == StatusReady
+}
+
+// Checker проверяет готовность сервиса по его компонентам.
+type Checker struct {
+	components   []Component
+	timeout      time.Duration
+	shuttingDown atomic.Bool
+}
+
+// NewChecker создает проверку готовности компонентов components, каждый из которых проверяется не дольше timeout.
+// Если timeout не положителен, используется DefaultTimeout.
+func NewChecker(timeout time.Duration, components ...Component) *Checker {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Checker{components: components, timeout: timeout}
+}
+
+// SetShuttingDown переводит сервис в состояние остановки, в котором он неготов.
+func (checker *Checker) SetShuttingDown() {
+	checker.shuttingDown.Store(true)
+}
+
+// Ready проверяет компоненты параллельно и возвращает отчет о готовности.
+// Компонент, проверка которого не завершилась за время ожидания, считается недоступным.
+func (checker *Checker) Ready(ctx context.Context) Report {
+	statuses := make([]ComponentStatus, len(checker.components))
+	var wg sync.WaitGroup
+	for i, component := range checker.components {
+		wg.Add(1)
+		go func(i int, component Component) {
+			defer wg.Done()
+			statuses[i] = checker.check(ctx, component)
+		}(i, component)
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusReady, Components: make(map[string]ComponentStatus, len(checker.components))}
+	for i, component := range checker.components {
+		report.Components[component.Name] = statuses[i]
+		if statuses[i].Status != ComponentUp {
+			report.Status = StatusUnready
+		}
+	}
+	if checker.shuttingDown.Load() {
+		report.Status = StatusShuttingDown
+	}
+	return report
+}
+
+// check проверяет компонент с временем ожидания. Результат зависшей проверки не ожидается.
+func (checker *Checker) check(ctx context.Context, component Component) ComponentStatus {
+	ctx, cancel := context.WithTimeout(ctx, checker.timeout)
+	defer cancel()
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- component.Check(ctx)
+	}()
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	status := ComponentStatus{Status: ComponentUp, DurationMS: float64(time.Since(start).Microseconds()) / 1000}
+	if err != nil {
+		status.Status = ComponentDown
+		status.Error = err.Error()
+	}
+	return status
+}
+
+// LivenessHandler возвращает обработчик HTTP проверки живости, который всегда отвечает 200 OK.
+func (checker *Checker) LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+}
+
+// ReadinessHandler возвращает обработчик HTTP проверки готовности, который отвечает отчетом о готовности
+// с кодом 200 OK, если сервис готов, и 503 Service Unavailable, если нет.
+func (checker *Checker) ReadinessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := checker.Ready(r.Context())
+		code := http.StatusOK
+		if !report.Ready() {
+			code = http.StatusServiceUnavailable
+		}
+		writeJSON(w, code, report)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, code int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		logger.Logger.Warn("failed to write health response", "error", err)
+	}
+}
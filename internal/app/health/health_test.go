@@ -0,0 +1,81 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func up(context.Context) error {
+	return nil
+}
+
+func TestReady(t *testing.T) {
+	hang := make(chan struct{})
+	defer close(hang)
+	checker := NewChecker(50*time.Millisecond,
+		Component{Name: "storage", Check: up},
+		Component{Name: "cache", Check: func(context.Context) error { return errors.New("connection refused") }},
+		Component{Name: "worker", Check: func(context.Context) error { <-hang; return nil }},
+	)
+
+	start := time.Now()
+	report := checker.Ready(context.Background())
+	assert.Less(t, time.Since(start), time.Second, "hanging check must be abandoned after timeout")
+	assert.False(t, report.Ready())
+	assert.Equal(t, StatusUnready, report.Status)
+	assert.Equal(t, ComponentUp, report.Components["storage"].Status)
+	assert.Equal(t, ComponentDown, report.Components["cache"].Status)
+	assert.Equal(t, "connection refused", report.Components["cache"].Error)
+	assert.Equal(t, ComponentDown, report.Components["worker"].Status)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Components["worker"].Error)
+
+	checker = NewChecker(0, Component{Name: "storage", Check: up})
+	assert.True(t, checker.Ready(context.Background()).Ready())
+	checker.SetShuttingDown()
+	report = checker.Ready(context.Background())
+	assert.Equal(t, StatusShuttingDown, report.Status)
+	assert.Equal(t, ComponentUp, report.Components["storage"].Status)
+}
+
+func TestHandlers(t *testing.T) {
+	require.NoError(t, logger.Init(slog.LevelInfo))
+	healthy := true
+	checker := NewChecker(time.Second, Component{Name: "storage", Check: func(context.Context) error {
+		if !healthy {
+			return errors.New("storage is unavailable")
+		}
+		return nil
+	}})
+
+	w := httptest.NewRecorder()
+	checker.ReadinessHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	var report Report
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, StatusReady, report.Status)
+	assert.Equal(t, ComponentUp, report.Components["storage"].Status)
+
+	healthy = false
+	w = httptest.NewRecorder()
+	checker.ReadinessHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, StatusUnready, report.Status)
+	assert.Equal(t, "storage is unavailable", report.Components["storage"].Error)
+
+	w = httptest.NewRecorder()
+	checker.LivenessHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, w.Code, "liveness must not depend on components")
+	assert.JSONEq(t, `{"status":"ok"}`, w.Body.String())
+}
@@ -1,11 +1,13 @@
 package logger
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"time"
 
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/tracing"
 	slogzap "github.com/samber/slog-zap/v2"
 	"go.uber.org/zap"
 )
@@ -14,16 +16,40 @@ import (
 var Logger = slog.New(&slogzap.ZapHandler{})
 
 // Init инициализирует логгер с указанным уровнем логирования.
+// Записи, сделанные с контекстом текущего спана, содержат идентификаторы trace_id и span_id.
 // Возвращает ошибку, если инициализация не удалась.
 func Init(level slog.Level) error {
 	zapLogger, err := zap.NewProduction()
 	if err != nil {
 		return err
 	}
-	Logger = slog.New(slogzap.Option{Level: level, Logger: zapLogger}.NewZapHandler())
+	Logger = slog.New(traceHandler{slogzap.Option{Level: level, Logger: zapLogger}.NewZapHandler()})
 	return nil
 }
 
+// traceHandler добавляет к записям идентификаторы трассировки и спана из контекста записи.
+type traceHandler struct {
+	slog.Handler
+}
+
+// Handle добавляет идентификаторы трассировки к записи и передает ее обработчику.
+func (h traceHandler) Handle(ctx context.Context, record slog.Record) error {
+	if sc := tracing.SpanContextFromContext(ctx); sc.IsValid() {
+		record.AddAttrs(slog.String("trace_id", sc.TraceID.String()), slog.String("span_id", sc.SpanID.String()))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+// WithAttrs возвращает обработчик с дополнительными атрибутами.
+func (h traceHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return traceHandler{h.Handler.WithAttrs(attrs)}
+}
+
+// WithGroup возвращает обработчик с группой атрибутов.
+func (h traceHandler) WithGroup(name string) slog.Handler {
+	return traceHandler{h.Handler.WithGroup(name)}
+}
+
 // RequestLogger возвращает middleware для логирования информации о HTTP-запросах.
 func RequestLogger(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -38,7 +64,7 @@ func RequestLogger(h http.Handler) http.Handler {
 		}
 		h.ServeHTTP(&lw, r)
 		duration := time.Since(start).Milliseconds()
-		Logger.Info("request info",
+		Logger.InfoContext(r.Context(), "request info",
 			slog.Group("request",
 				slog.String("uri", r.RequestURI),
 				slog.String("method", r.Method),
@@ -0,0 +1,302 @@
+package metrics
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// DefaultDurationBuckets границы гистограмм длительности в секундах по умолчанию.
+var DefaultDurationBuckets = []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// DefaultSizeBuckets границы гистограмм размеров пакетов по умолчанию.
+var DefaultSizeBuckets = []float64{1, 5, 10, 25, 50, 100, 250, 500, 1000}
+
+// Counter счетчик, значение которого только растет.
+type Counter struct {
+	desc
+	value atomicFloat
+}
+
+// NewCounter создает счетчик без меток.
+func NewCounter(name, help string) *Counter {
+	return &Counter{desc: desc{name: name, help: help, typ: TypeCounter}}
+}
+
+// Inc увеличивает счетчик на единицу.
+func (counter *Counter) Inc() {
+	counter.value.add(1)
+}
+
+// Add увеличивает счетчик на delta. Отрицательные значения игнорируются.
+func (counter *Counter) Add(delta float64) {
+	if delta > 0 {
+		counter.value.add(delta)
+	}
+}
+
+// Value возвращает значение счетчика.
+func (counter *Counter) Value() float64 {
+	return counter.value.load()
+}
+
+// Collect записывает счетчик в текстовом формате Prometheus.
+func (counter *Counter) Collect(b *strings.Builder) {
+	counter.writeHeader(b)
+	writeSample(b, counter.name, nil, nil, "", "", counter.Value())
+}
+
+// Gauge показатель, значение которого может как расти, так и уменьшаться.
+type Gauge struct {
+	desc
+	value atomicFloat
+}
+
+// NewGauge создает показатель без меток.
+func NewGauge(name, help string) *Gauge {
+	return &Gauge{desc: desc{name: name, help: help, typ: TypeGauge}}
+}
+
+// Set устанавливает значение показателя.
+func (gauge *Gauge) Set(value float64) {
+	gauge.value.set(value)
+}
+
+// Add изменяет значение показателя на delta.
+func (gauge *Gauge) Add(delta float64) {
+	gauge.value.add(delta)
+}
+
+// Value возвращает значение показателя.
+func (gauge *Gauge) Value() float64 {
+	return gauge.value.load()
+}
+
+// Collect записывает показатель в текстовом формате Prometheus.
+func (gauge *Gauge) Collect(b *strings.Builder) {
+	gauge.writeHeader(b)
+	writeSample(b, gauge.name, nil, nil, "", "", gauge.Value())
+}
+
+// Histogram гистограмма распределения наблюдаемых значений.
+type Histogram struct {
+	desc
+	buckets []float64
+	counts  []atomicFloat
+	sum     atomicFloat
+	count   atomicFloat
+}
+
+// NewHistogram создает гистограмму без меток с верхними границами корзин buckets.
+func NewHistogram(name, help string, buckets []float64) *Histogram {
+	return newHistogram(desc{name: name, help: help, typ: TypeHistogram}, buckets)
+}
+
+func newHistogram(d desc, buckets []float64) *Histogram {
+	buckets = slices.Clone(buckets)
+	slices.Sort(buckets)
+	return &Histogram{desc: d, buckets: buckets, counts: make([]atomicFloat, len(buckets))}
+}
+
+// Observe добавляет наблюдаемое значение.
+func (histogram *Histogram) Observe(value float64) {
+	for i, bound := range histogram.buckets {
+		if value <= bound {
+			histogram.counts[i].add(1)
+			break
+		}
+	}
+	histogram.sum.add(value)
+	histogram.count.add(1)
+}
+
+// Count возвращает количество наблюдений.
+func (histogram *Histogram) Count() uint64 {
+	return uint64(histogram.count.load())
+}
+
+// Collect записывает гистограмму в текстовом формате Prometheus.
+func (histogram *Histogram) Collect(b *strings.Builder) {
+	histogram.writeHeader(b)
+	histogram.writeSamples(b, nil)
+}
+
+// writeSamples записывает корзины, сумму и количество наблюдений с метками со значениями values.
+func (histogram *Histogram) writeSamples(b *strings.Builder, values []string) {
+	var cumulative float64
+	for i, bound := range histogram.buckets {
+		cumulative += histogram.counts[i].load()
+		writeSample(b, histogram.name+"_bucket", histogram.labels, values, "le", formatFloat(bound), cumulative)
+	}
+	count := histogram.count.load()
+	writeSample(b, histogram.name+"_bucket", histogram.labels, values, "le", formatFloat(math.Inf(1)), count)
+	writeSample(b, histogram.name+"_sum", histogram.labels, values, "", "", histogram.sum.load())
+	writeSample(b, histogram.name+"_count", histogram.labels, values, "", "", count)
+}
+
+// vec набор метрик одного имени, различающихся значениями меток.
+type vec[T any] struct {
+	desc
+	mu       sync.RWMutex
+	children map[string]*vecChild[T]
+	create   func() *T
+}
+
+type vecChild[T any] struct {
+	values []string
+	metric *T
+}
+
+func newVec[T any](d desc, create func() *T) *vec[T] {
+	return &vec[T]{desc: d, children: make(map[string]*vecChild[T]), create: create}
+}
+
+// with возвращает метрику со значениями меток values, создавая ее при первом обращении.
+func (v *vec[T]) with(values []string) *T {
+	if len(values) != len(v.labels) {
+		panic("metrics: " + v.name + " expects labels " + strings.Join(v.labels, ","))
+	}
+	key := strings.Join(values, "\xff")
+	v.mu.RLock()
+	child, ok := v.children[key]
+	v.mu.RUnlock()
+	if ok {
+		return child.metric
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if child, ok := v.children[key]; ok {
+		return child.metric
+	}
+	child = &vecChild[T]{values: slices.Clone(values), metric: v.create()}
+	v.children[key] = child
+	return child.metric
+}
+
+// sorted возвращает метрики набора, упорядоченные по значениям меток.
+func (v *vec[T]) sorted() []*vecChild[T] {
+	v.mu.RLock()
+	keys := make([]string, 0, len(v.children))
+	for key := range v.children {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	children := make([]*vecChild[T], len(keys))
+	for i, key := range keys {
+		children[i] = v.children[key]
+	}
+	v.mu.RUnlock()
+	return children
+}
+
+// CounterVec набор счетчиков с метками.
+type CounterVec struct {
+	*vec[Counter]
+}
+
+// NewCounterVec создает набор счетчиков с метками labels.
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	return &CounterVec{newVec(desc{name: name, help: help, typ: TypeCounter, labels: labels}, func() *Counter {
+		return &Counter{}
+	})}
+}
+
+// With возвращает счетчик со значениями меток values в порядке объявления меток.
+func (v *CounterVec) With(values ...string) *Counter {
+	return v.with(values)
+}
+
+// Collect записывает счетчики в текстовом формате Prometheus.
+func (v *CounterVec) Collect(b *strings.Builder) {
+	v.writeHeader(b)
+	for _, child := range v.sorted() {
+		writeSample(b, v.name, v.labels, child.values, "", "", child.metric.Value())
+	}
+}
+
+// GaugeVec набор показателей с метками.
+type GaugeVec struct {
+	*vec[Gauge]
+}
+
+// NewGaugeVec создает набор показателей с метками labels.
+func NewGaugeVec(name, help string, labels ...string) *GaugeVec {
+	return &GaugeVec{newVec(desc{name: name, help: help, typ: TypeGauge, labels: labels}, func() *Gauge {
+		return &Gauge{}
+	})}
+}
+
+// With возвращает показатель со значениями меток values в порядке объявления меток.
+func (v *GaugeVec) With(values ...string) *Gauge {
+	return v.with(values)
+}
+
+// Collect записывает показатели в текстовом формате Prometheus.
+func (v *GaugeVec) Collect(b *strings.Builder) {
+	v.writeHeader(b)
+	for _, child := range v.sorted() {
+		writeSample(b, v.name, v.labels, child.values, "", "", child.metric.Value())
+	}
+}
+
+// HistogramVec набор гистограмм с метками.
+type HistogramVec struct {
+	*vec[Histogram]
+}
+
+// NewHistogramVec создает набор гистограмм с верхними границами корзин buckets и метками labels.
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	d := desc{name: name, help: help, typ: TypeHistogram, labels: labels}
+	return &HistogramVec{newVec(d, func() *Histogram {
+		return newHistogram(d, buckets)
+	})}
+}
+
+// With возвращает гистограмму со значениями меток values в порядке объявления меток.
+func (v *HistogramVec) With(values ...string) *Histogram {
+	return v.with(values)
+}
+
+// Collect записывает гистограммы в текстовом формате Prometheus.
+func (v *HistogramVec) Collect(b *strings.Builder) {
+	v.writeHeader(b)
+	for _, child := range v.sorted() {
+		child.metric.writeSamples(b, child.values)
+	}
+}
+
+// Sample значение метрики с указанными значениями меток.
+type Sample struct {
+	LabelValues []string
+	Value       float64
+}
+
+// Func метрика, значения которой вычисляет функция при каждом сборе.
+// Подходит для значений, которые уже подсчитываются в другом месте, например счетчиков кеша.
+type Func struct {
+	desc
+	fn func() []Sample
+}
+
+// NewCounterFunc создает счетчик с метками labels, значения которого возвращает fn.
+func NewCounterFunc(name, help string, fn func() []Sample, labels ...string) *Func {
+	return &Func{desc: desc{name: name, help: help, typ: TypeCounter, labels: labels}, fn: fn}
+}
+
+// NewGaugeFunc создает показатель с метками labels, значения которого возвращает fn.
+func NewGaugeFunc(name, help string, fn func() []Sample, labels ...string) *Func {
+	return &Func{desc: desc{name: name, help: help, typ: TypeGauge, labels: labels}, fn: fn}
+}
+
+// Collect записывает значения, которые вернула функция, в текстовом формате Prometheus.
+// Значения с неверным количеством меток пропускаются.
+func (f *Func) Collect(b *strings.Builder) {
+	f.writeHeader(b)
+	for _, sample := range f.fn() {
+		if len(sample.LabelValues) != len(f.labels) {
+			continue
+		}
+		writeSample(b, f.name, f.labels, sample.LabelValues, "", "", sample.Value)
+	}
+}
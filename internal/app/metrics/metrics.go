@@ -0,0 +1,177 @@
+// Package metrics предоставляет метрики сервиса в текстовом формате Prometheus.
+//
+// Метрики регистрируются в реестре Registry, который отдает их HTTP-обработчиком Handler.
+// Поддерживаются счетчики, показатели и гистограммы, в том числе с метками, а также метрики,
+// значения которых вычисляются функцией при каждом сборе. Метрики самого сервиса объявлены
+// в этом пакете и зарегистрированы в реестре Default.
+package metrics
+
+import (
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Типы метрик в текстовом формате Prometheus.
+const (
+	TypeCounter   = "counter"
+	TypeGauge     = "gauge"
+	TypeHistogram = "histogram"
+)
+
+// contentType тип содержимого текстового формата Prometheus.
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Collector метрика, которую можно зарегистрировать в реестре.
+type Collector interface {
+	// Name возвращает имя метрики.
+	Name() string
+	// Collect записывает метрику в текстовом формате Prometheus.
+	Collect(b *strings.Builder)
+}
+
+// Registry реестр метрик.
+type Registry struct {
+	mu         sync.RWMutex
+	collectors map[string]Collector
+}
+
+// NewRegistry создает пустой реестр метрик.
+func NewRegistry() *Registry {
+	return &Registry{collectors: make(map[string]Collector)}
+}
+
+// Register регистрирует метрики. Метрика с уже зарегистрированным именем заменяет прежнюю.
+func (registry *Registry) Register(collectors ...Collector) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	for _, collector := range collectors {
+		registry.collectors[collector.Name()] = collector
+	}
+}
+
+// Text возвращает все метрики реестра в текстовом формате Prometheus, упорядоченные по имени.
+func (registry *Registry) Text() string {
+	registry.mu.RLock()
+	names := make([]string, 0, len(registry.collectors))
+	for name := range registry.collectors {
+		names = append(names, name)
+	}
+	collectors := make([]Collector, 0, len(names))
+	slices.Sort(names)
+	for _, name := range names {
+		collectors = append(collectors, registry.collectors[name])
+	}
+	registry.mu.RUnlock()
+	var b strings.Builder
+	for _, collector := range collectors {
+		collector.Collect(&b)
+	}
+	return b.String()
+}
+
+// Handler возвращает HTTP-обработчик, который отдает метрики реестра.
+func (registry *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(registry.Text()))
+	})
+}
+
+// desc описание метрики: имя, справка, тип и имена меток.
+type desc struct {
+	name   string
+	help   string
+	typ    string
+	labels []string
+}
+
+// Name возвращает имя метрики.
+func (d desc) Name() string {
+	return d.name
+}
+
+// writeHeader записывает строки HELP и TYPE метрики.
+func (d desc) writeHeader(b *strings.Builder) {
+	b.WriteString("# HELP ")
+	b.WriteString(d.name)
+	b.WriteByte(' ')
+	b.WriteString(strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(d.help))
+	b.WriteString("\n# TYPE ")
+	b.WriteString(d.name)
+	b.WriteByte(' ')
+	b.WriteString(d.typ)
+	b.WriteByte('\n')
+}
+
+// writeSample записывает значение метрики name с метками labels и значениями values,
+// а также дополнительной меткой extraName, если она задана.
+func writeSample(b *strings.Builder, name string, labels, values []string, extraName, extraValue string, value float64) {
+	b.WriteString(name)
+	if len(labels) > 0 || extraName != "" {
+		b.WriteByte('{')
+		for i, label := range labels {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			writeLabel(b, label, values[i])
+		}
+		if extraName != "" {
+			if len(labels) > 0 {
+				b.WriteByte(',')
+			}
+			writeLabel(b, extraName, extraValue)
+		}
+		b.WriteByte('}')
+	}
+	b.WriteByte(' ')
+	b.WriteString(formatFloat(value))
+	b.WriteByte('\n')
+}
+
+func writeLabel(b *strings.Builder, name, value string) {
+	b.WriteString(name)
+	b.WriteString(`="`)
+	b.WriteString(strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value))
+	b.WriteByte('"')
+}
+
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+}
+
+// atomicFloat число с плавающей точкой, которое можно изменять из разных горутин.
+type atomicFloat struct {
+	bits atomic.Uint64
+}
+
+func (f *atomicFloat) add(delta float64) {
+	for {
+		old := f.bits.Load()
+		if f.bits.CompareAndSwap(old, math.Float64bits(math.Float64frombits(old)+delta)) {
+			return
+		}
+	}
+}
+
+func (f *atomicFloat) set(value float64) {
+	f.bits.Store(math.Float64bits(value))
+}
+
+func (f *atomicFloat) load() float64 {
+	return math.Float64frombits(f.bits.Load())
+}
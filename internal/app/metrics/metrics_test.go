@@ -0,0 +1,78 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryText(t *testing.T) {
+	registry := NewRegistry()
+	counter := NewCounter("test_created_total", "Created.")
+	counter.Inc()
+	counter.Add(2)
+	counter.Add(-1)
+	requests := NewCounterVec("test_requests_total", "Requests\nby route.", "route", "code")
+	requests.With("/{id}", "200").Inc()
+	requests.With(`/a"b\c`, "500").Inc()
+	gauge := NewGauge("test_depth", "Depth.")
+	gauge.Set(5)
+	gauge.Add(-2)
+	histogram := NewHistogramVec("test_duration_seconds", "Duration.", []float64{1, 0.1}, "route")
+	histogram.With("/").Observe(0.05)
+	histogram.With("/").Observe(0.5)
+	histogram.With("/").Observe(3)
+	hits := NewCounterFunc("test_hits_total", "Hits.", func() []Sample {
+		return []Sample{{LabelValues: []string{"local"}, Value: 7}, {LabelValues: []string{"a", "b"}, Value: 1}}
+	}, "layer")
+	registry.Register(requests, gauge, histogram, counter, hits)
+
+	expected := `# HELP test_created_total Created.
+# TYPE test_created_total counter
+test_created_total 3
+# HELP test_depth Depth.
+# TYPE test_depth gauge
+test_depth 3
+# HELP test_duration_seconds Duration.
+# TYPE test_duration_seconds histogram
+test_duration_seconds_bucket{route="/",le="0.1"} 1
+test_duration_seconds_bucket{route="/",le="1"} 2
+test_duration_seconds_bucket{route="/",le="+Inf"} 3
+test_duration_seconds_sum{route="/"} 3.55
+test_duration_seconds_count{route="/"} 3
+# HELP test_hits_total Hits.
+# TYPE test_hits_total counter
+test_hits_total{layer="local"} 7
+# HELP test_requests_total Requests\nby route.
+# TYPE test_requests_total counter
+test_requests_total{route="/a\"b\\c",code="500"} 1
+test_requests_total{route="/{id}",code="200"} 1
+`
+	assert.Equal(t, expected, registry.Text())
+
+	registry.Register(NewCounter("test_created_total", "Replaced."))
+	assert.Contains(t, registry.Text(), "test_created_total 0\n")
+}
+
+func TestRegistryHandler(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(NewGauge("test_up", "Up."))
+	w := httptest.NewRecorder()
+	registry.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	res := w.Result()
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, contentType, res.Header.Get("Content-Type"))
+	assert.Contains(t, string(body), "test_up 0\n")
+}
+
+func TestVecLabelsMismatch(t *testing.T) {
+	requests := NewCounterVec("test_requests_total", "Requests.", "route", "code")
+	assert.Panics(t, func() { requests.With("/") })
+}
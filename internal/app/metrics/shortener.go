@@ -0,0 +1,58 @@
+package metrics
+
+// Default реестр метрик сервиса.
+var Default = NewRegistry()
+
+// Метрики HTTP- и gRPC-серверов.
+var (
+	// HTTPRequests количество HTTP-запросов по методу, шаблону маршрута chi и коду ответа.
+	HTTPRequests = NewCounterVec("shortener_http_requests_total", "Total number of HTTP requests.", "method", "route", "code")
+	// HTTPRequestDuration длительность обработки HTTP-запросов по методу и шаблону маршрута chi.
+	HTTPRequestDuration = NewHistogramVec("shortener_http_request_duration_seconds", "HTTP request latency in seconds.", DefaultDurationBuckets, "method", "route")
+	// GRPCRequests количество вызовов gRPC по методу и коду статуса.
+	GRPCRequests = NewCounterVec("shortener_grpc_requests_total", "Total number of gRPC calls.", "method", "code")
+	// GRPCRequestDuration длительность обработки вызовов gRPC по методу.
+	GRPCRequestDuration = NewHistogramVec("shortener_grpc_request_duration_seconds", "gRPC call latency in seconds.", DefaultDurationBuckets, "method")
+)
+
+// Метрики хранилища.
+var (
+	// StorageOperationDuration длительность вызовов хранилища по типу хранилища и операции.
+	StorageOperationDuration = NewHistogramVec("shortener_storage_operation_duration_seconds", "Storage call latency in seconds.", DefaultDurationBuckets, "backend", "operation")
+	// StorageOperationErrors количество ошибок хранилища по типу хранилища и операции.
+	// Ожидаемые результаты, например ненайденный или уже занятый сокращенный URL, ошибками не считаются.
+	StorageOperationErrors = NewCounterVec("shortener_storage_operation_errors_total", "Total number of failed storage calls.", "backend", "operation")
+)
+
+// Метрики сервиса и фоновых workers.
+var (
+	// LinksCreated количество созданных сокращенных URL.
+	LinksCreated = NewCounter("shortener_links_created_total", "Total number of created short URLs.")
+	// Redirects количество выполненных редиректов по сокращенным URL.
+	Redirects = NewCounter("shortener_redirects_total", "Total number of redirects served.")
+	// DeletionQueueDepth количество незавершенных заданий на удаление в хранилище после последнего опроса.
+	DeletionQueueDepth = NewGauge("shortener_deletion_queue_depth", "Number of unfinished deletion jobs.")
+	// DeletionBatchSize количество URL в выполненных заданиях на удаление.
+	DeletionBatchSize = NewHistogram("shortener_deletion_batch_size", "Number of URLs per executed deletion job.", DefaultSizeBuckets)
+	// ClickQueueDepth количество событий перехода в очереди на сохранение.
+	ClickQueueDepth = NewGauge("shortener_click_queue_depth", "Number of click events waiting to be saved.")
+	// ClickBatchSize количество событий перехода в сохраняемых пакетах.
+	ClickBatchSize = NewHistogram("shortener_click_batch_size", "Number of click events per flushed batch.", DefaultSizeBuckets)
+)
+
+func init() {
+	Default.Register(
+		HTTPRequests,
+		HTTPRequestDuration,
+		GRPCRequests,
+		GRPCRequestDuration,
+		StorageOperationDuration,
+		StorageOperationErrors,
+		LinksCreated,
+		Redirects,
+		DeletionQueueDepth,
+		DeletionBatchSize,
+		ClickQueueDepth,
+		ClickBatchSize,
+	)
+}
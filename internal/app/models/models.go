@@ -1,11 +1,19 @@
 // Package models предоставляет структуры данных для работы с URL.
 package models
 
-import "time"
+import (
+	neturl "net/url"
+	"strings"
+	"time"
+)
 
 // Request представляет модель запроса на сокращение URL.
 type Request struct {
-	URL string `json:"url"` // URL URL для сокращения.
+	URL         string     `json:"url"`                    // URL URL для сокращения.
+	CustomAlias string     `json:"custom_alias,omitempty"` // CustomAlias пользовательский сокращенный URL.
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`   // ExpiresAt время, после которого сокращенный URL перестает работать.
+	TTL         int64      `json:"ttl,omitempty"`          // TTL время жизни сокращенного URL в секундах.
+	Dedupe      bool       `json:"dedupe,omitempty"`       // Dedupe флаг, при котором вместо новой ссылки возвращается уже созданная пользователем для этого URL.
 }
 
 // Response представляет модель ответа с сокращенным URL.
@@ -20,16 +28,29 @@ type URLInfo struct {
 	OriginalURL string `json:"original_url"` // OriginalURL исходный URL.
 }
 
-// ShortURLInfoBatch представляет информацию о сокращенном URL для пакетной обработки.
+// ShortURLInfoBatch представляет результат сокращения одного URL при пакетной обработке.
 type ShortURLInfoBatch struct {
-	CorrelationID string `json:"correlation_id"` // CorrelationID идентификатор корреляции.
-	ShortURL      string `json:"short_url"`      // ShortURL сокращенный URL.
+	CorrelationID string `json:"correlation_id"`      // CorrelationID идентификатор корреляции.
+	ShortURL      string `json:"short_url,omitempty"` // ShortURL созданный или существующий сокращенный URL.
+	Status        string `json:"status"`              // Status результат: created, existing или invalid.
+	Error         string `json:"error,omitempty"`     // Error причина, по которой URL не сокращен.
 }
 
+// Результаты сокращения URL при пакетной обработке.
+const (
+	BatchItemCreated  = "created"  // BatchItemCreated создан новый сокращенный URL.
+	BatchItemExisting = "existing" // BatchItemExisting пользователь уже сокращал URL, возвращен существующий сокращенный URL.
+	BatchItemInvalid  = "invalid"  // BatchItemInvalid URL не сокращен, причина в Error.
+)
+
 // OriginalURLInfoBatch представляет информацию об исходном URL для пакетной отдачи.
 type OriginalURLInfoBatch struct {
-	CorrelationID string `json:"correlation_id"` // CorrelationID идентификатор корреляции.
-	OriginalURL   string `json:"original_url"`   // OriginalURL исходный URL.
+	CorrelationID string     `json:"correlation_id"`         // CorrelationID идентификатор корреляции.
+	OriginalURL   string     `json:"original_url"`           // OriginalURL исходный URL.
+	CustomAlias   string     `json:"custom_alias,omitempty"` // CustomAlias пользовательский сокращенный URL.
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"`   // ExpiresAt время, после которого сокращенный URL перестает работать.
+	TTL           int64      `json:"ttl,omitempty"`          // TTL время жизни сокращенного URL в секундах.
+	Dedupe        bool       `json:"dedupe,omitempty"`       // Dedupe флаг, при котором вместо новой ссылки возвращается уже созданная пользователем для этого URL.
 }
 
 // URLByUser представляет информацию о URL, созданных пользователем.
@@ -38,18 +59,102 @@ type URLByUser struct {
 	OriginalURL string `json:"original_url"` // OriginalURL исходный URL.
 }
 
+// URLQuery представляет параметры выборки URL, созданных пользователем.
+type URLQuery struct {
+	Limit               int        // Limit максимальное количество URL в выборке.
+	Cursor              *URLCursor // Cursor позиция, после которой начинается выборка, nil — с начала.
+	CreatedFrom         time.Time  // CreatedFrom нижняя граница времени создания URL включительно.
+	CreatedTo           time.Time  // CreatedTo верхняя граница времени создания URL не включительно.
+	OriginalURLContains string     // OriginalURLContains подстрока, которую должен содержать исходный URL.
+	Deleted             *bool      // Deleted фильтр по признаку удаления, nil — без фильтра.
+	SortBy              string     // SortBy поле сортировки: created_ts, short_url или original_url.
+	SortDesc            bool       // SortDesc флаг сортировки по убыванию.
+}
+
+// URLCursor представляет позицию в выборке URL: значение поля сортировки и сокращенный URL последнего элемента.
+type URLCursor struct {
+	Value    string `json:"v"` // Value значение поля сортировки.
+	ShortURL string `json:"s"` // ShortURL сокращенный URL, разрешающий равенство значений поля сортировки.
+}
+
+// URLPage представляет страницу URL, созданных пользователем.
+type URLPage struct {
+	URLs       []URLByUser // URLs URL на странице.
+	NextCursor string      // NextCursor курсор следующей страницы, пустая строка — страница последняя.
+}
+
+// Поля сортировки URL пользователя.
+const (
+	SortByCreatedTS   = "created_ts"
+	SortByShortURL    = "short_url"
+	SortByOriginalURL = "original_url"
+)
+
 // URLToDelete представляет информацию о URL, которые нужно удалить.
 type URLToDelete struct {
 	UserID   int    `json:"user_id"`   // UserID идентификатор пользователя.
 	ShortURL string `json:"short_url"` // ShortURL сокращенный URL.
 }
 
+// DeletionJob представляет задание на удаление URL, созданных пользователем.
+type DeletionJob struct {
+	ID            string           `json:"id"`              // ID идентификатор задания.
+	UserID        int              `json:"-"`               // UserID идентификатор пользователя, создавшего задание.
+	Status        string           `json:"status"`          // Status состояние задания: pending, done или failed.
+	URLs          []DeletionJobURL `json:"urls"`            // URLs результаты удаления по каждому URL.
+	Attempts      int              `json:"attempts"`        // Attempts количество выполненных попыток удаления.
+	LastError     string           `json:"error,omitempty"` // LastError ошибка последней неудачной попытки.
+	CreatedAt     time.Time        `json:"created_at"`      // CreatedAt время создания задания.
+	UpdatedAt     time.Time        `json:"updated_at"`      // UpdatedAt время последнего изменения задания.
+	NextAttemptAt time.Time        `json:"-"`               // NextAttemptAt время, не раньше которого выполняется следующая попытка.
+}
+
+// DeletionJobURL представляет результат удаления одного URL в задании.
+type DeletionJobURL struct {
+	ShortURL string `json:"short_url"` // ShortURL сокращенный URL.
+	Status   string `json:"status"`    // Status результат: pending, deleted, not_found или failed.
+}
+
+// Состояния задания на удаление.
+const (
+	DeletionJobPending = "pending"
+	DeletionJobDone    = "done"
+	DeletionJobFailed  = "failed"
+)
+
+// Результаты удаления URL в задании.
+const (
+	DeletionURLPending  = "pending"
+	DeletionURLDeleted  = "deleted"
+	DeletionURLNotFound = "not_found"
+	DeletionURLFailed   = "failed"
+)
+
+// RestoreResult представляет результат восстановления одного удаленного URL.
+type RestoreResult struct {
+	ShortURL string `json:"short_url"` // ShortURL сокращенный URL.
+	Status   string `json:"status"`    // Status результат: restored или not_restored.
+}
+
+// Результаты восстановления URL.
+// URL не восстанавливается, если он не найден, создан другим пользователем, не удален или удален раньше срока восстановления.
+const (
+	RestoreURLRestored    = "restored"
+	RestoreURLNotRestored = "not_restored"
+)
+
 // Stats представляет статистику по сокращенным URL.
 type Stats struct {
 	URLS  int `json:"urls"`  // URLS количество сокращенных URL.
 	Users int `json:"users"` // USERS количество пользователей.
 }
 
+// User представляет зарегистрированного пользователя.
+type User struct {
+	ID        int       // ID идентификатор пользователя.
+	CreatedAt time.Time // CreatedAt время регистрации пользователя.
+}
+
 // UserInfo представляет информацию о пользователе.
 type UserInfo struct {
 	UserID int // UserID идентификатор пользователя.
@@ -63,6 +168,64 @@ type URL struct {
 	CreatedBy   int       // CreatedBy идентификатор пользователя, который создал URL.
 	CreatedTS   time.Time // CreatedTS время создания URL.
 	IsDeleted   bool      // IsDeleted флаг, указывающий, был ли URL удален.
+	DeletedAt   time.Time // DeletedAt время удаления URL, нулевое значение для URL, удаленных до появления этого поля.
+	ExpiresAt   time.Time // ExpiresAt время истечения срока действия URL, нулевое значение означает бессрочный URL.
+	IsDisabled  bool      // IsDisabled флаг, указывающий, что URL отключен администратором и не открывается.
+	// Dedupe флаг сохранения: если у пользователя CreatedBy уже есть действующий URL с тем же исходным URL,
+	// новый URL не сохраняется, а хранилище возвращает конфликт с существующим сокращенным URL. Не хранится.
+	Dedupe bool
+}
+
+// IsDuplicateOf проверяет, является ли существующий URL other дубликатом url при дедупликации на момент now:
+// other создан тем же пользователем для того же исходного URL, не удален, не отключен и не истек.
+func (url URL) IsDuplicateOf(other URL, now time.Time) bool {
+	return other.CreatedBy == url.CreatedBy && other.OriginalURL == url.OriginalURL && !other.IsDeleted && !other.IsDisabled && !other.IsExpired(now)
+}
+
+// HasDomain проверяет, ведет ли исходный URL на домен domain или его поддомен. domain должен быть в нижнем регистре.
+func (url URL) HasDomain(domain string) bool {
+	parsed, err := neturl.Parse(url.OriginalURL)
+	if err != nil {
+		return false
+	}
+	host := strings.TrimSuffix(strings.ToLower(parsed.Hostname()), ".")
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
+
+// IsExpired проверяет, истек ли срок действия URL на момент now.
+func (url URL) IsExpired(now time.Time) bool {
+	return !url.ExpiresAt.IsZero() && !now.Before(url.ExpiresAt)
+}
+
+// Click представляет событие перехода по сокращенному URL.
+type Click struct {
+	ShortURL  string    `json:"short_url"`  // ShortURL сокращенный URL.
+	Timestamp time.Time `json:"timestamp"`  // Timestamp время перехода.
+	Referrer  string    `json:"referrer"`   // Referrer адрес страницы, с которой был выполнен переход.
+	UserAgent string    `json:"user_agent"` // UserAgent клиент, выполнивший переход.
+	ClientIP  string    `json:"client_ip"`  // ClientIP IP-адрес клиента.
+}
+
+// LinkStats представляет статистику переходов по сокращенному URL.
+type LinkStats struct {
+	ShortURL       string         `json:"short_url"`       // ShortURL сокращенный URL.
+	TotalClicks    int            `json:"total_clicks"`    // TotalClicks общее количество переходов.
+	UniqueVisitors int            `json:"unique_visitors"` // UniqueVisitors количество уникальных посетителей по IP-адресу.
+	ClicksPerDay   []ClicksPerDay `json:"clicks_per_day"`  // ClicksPerDay количество переходов по дням.
+	TopReferrers   []CountItem    `json:"top_referrers"`   // TopReferrers самые частые источники переходов.
+	TopUserAgents  []CountItem    `json:"top_user_agents"` // TopUserAgents самые частые клиенты.
+}
+
+// ClicksPerDay представляет количество переходов за день.
+type ClicksPerDay struct {
+	Date   string `json:"date"`   // Date дата в формате YYYY-MM-DD (UTC).
+	Clicks int    `json:"clicks"` // Clicks количество переходов.
+}
+
+// CountItem представляет значение и количество его повторений.
+type CountItem struct {
+	Value string `json:"value"` // Value значение.
+	Count int    `json:"count"` // Count количество повторений.
 }
 
 // UserInfo определяет тип для передачи информации о пользователе.
@@ -72,3 +235,33 @@ type USER string
 const (
 	UserID USER = "UserID"
 )
+
+// AdminURL представляет сокращенный URL с владельцем и служебными данными для администратора.
+type AdminURL struct {
+	ShortURL    string     `json:"short_url"`              // ShortURL сокращенный URL.
+	OriginalURL string     `json:"original_url"`           // OriginalURL исходный URL.
+	UserID      int        `json:"user_id"`                // UserID идентификатор пользователя, владеющего URL, 0 — URL без владельца.
+	CreatedAt   time.Time  `json:"created_at"`             // CreatedAt время создания URL.
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`   // ExpiresAt время истечения срока действия URL.
+	IsDeleted   bool       `json:"is_deleted"`             // IsDeleted флаг удаления URL.
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`   // DeletedAt время удаления URL.
+	IsDisabled  bool       `json:"is_disabled"`            // IsDisabled флаг отключения URL администратором.
+	TotalClicks int        `json:"total_clicks,omitempty"` // TotalClicks общее количество переходов по URL, заполняется только при поиске одного URL.
+}
+
+// AdminOwnerRequest представляет запрос администратора на передачу URL другому пользователю.
+type AdminOwnerRequest struct {
+	UserID int `json:"user_id"` // UserID идентификатор пользователя, которому передается URL.
+}
+
+// AdminURLPage представляет страницу URL пользователя для администратора.
+type AdminURLPage struct {
+	URLs       []AdminURL // URLs URL на странице.
+	NextCursor string     // NextCursor курсор следующей страницы, пустая строка — страница последняя.
+}
+
+// AdminDeleteResult представляет результат массового удаления URL администратором.
+type AdminDeleteResult struct {
+	Deleted   int      `json:"deleted"`    // Deleted количество URL, помеченных удаленными этим запросом.
+	ShortURLs []string `json:"short_urls"` // ShortURLs сокращенные URL, помеченные удаленными этим запросом.
+}
@@ -0,0 +1,226 @@
+// Package ratelimit предоставляет ограничение частоты запросов по алгоритму token bucket.
+//
+// Лимиты задаются отдельно для групп маршрутов: создание сокращенных URL, переход по сокращенному URL
+// и API пользователя. Запросы одного клиента учитываются по идентификатору пользователя из JWT,
+// а запросы без действительного JWT — по IP-адресу клиента. Ограничители общие для HTTP и gRPC серверов.
+package ratelimit
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/config"
+)
+
+// Группы маршрутов с отдельными лимитами.
+const (
+	GroupCreate = "create" // GroupCreate создание сокращенных URL.
+	GroupExpand = "expand" // GroupExpand переход по сокращенному URL.
+	GroupUser   = "user"   // GroupUser API пользователя.
+)
+
+// sweepInterval интервал удаления полностью восполненных корзин неактивных клиентов.
+const sweepInterval = time.Minute
+
+// DefaultMaxBuckets наибольшее количество корзин клиентов одного ограничителя.
+const DefaultMaxBuckets = 100_000
+
+// Rule лимит группы: не более Requests запросов за Period.
+// Корзина клиента вмещает Requests запросов и полностью восполняется за Period.
+type Rule struct {
+	Requests int
+	Period   time.Duration
+}
+
+// ParseRule разбирает лимит в формате "<запросов>/<период>", например "100/1m" или "10/s".
+func ParseRule(spec string) (Rule, error) {
+	requests, period, ok := strings.Cut(spec, "/")
+	if !ok {
+		return Rule{}, fmt.Errorf("rate limit %q must be in format <requests>/<period>", spec)
+	}
+	rule := Rule{}
+	var err error
+	rule.Requests, err = strconv.Atoi(requests)
+	if err != nil || rule.Requests <= 0 {
+		return Rule{}, fmt.Errorf("rate limit %q: requests must be a positive integer", spec)
+	}
+	if period != "" && (period[0] < '0' || period[0] > '9') {
+		period = "1" + period
+	}
+	rule.Period, err = time.ParseDuration(period)
+	if err != nil || rule.Period <= 0 {
+		return Rule{}, fmt.Errorf("rate limit %q: period must be a positive duration", spec)
+	}
+	return rule, nil
+}
+
+// Result результат проверки лимита.
+type Result struct {
+	Allowed    bool          // Allowed признак того, что запрос разрешен.
+	Limit      int           // Limit количество запросов, разрешенных за период.
+	Period     time.Duration // Period период лимита.
+	Remaining  int           // Remaining количество запросов, которые можно выполнить сразу.
+	Reset      time.Duration // Reset время до полного восполнения корзины.
+	RetryAfter time.Duration // RetryAfter время, через которое можно повторить отклоненный запрос.
+}
+
+// bucket корзина токенов клиента.
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// Limiter ограничивает частоту запросов клиентов одной группы.
+type Limiter struct {
+	rule Rule
+	// rate скорость восполнения корзины в токенах за наносекунду.
+	rate float64
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	// maxBuckets наибольшее количество корзин. Если предел достигнут, запросы новых клиентов отклоняются.
+	maxBuckets int
+}
+
+// NewLimiter создает ограничитель с лимитом rule.
+func NewLimiter(rule Rule) *Limiter {
+	return &Limiter{
+		rule:       rule,
+		rate:       float64(rule.Requests) / float64(rule.Period),
+		buckets:    make(map[string]*bucket),
+		maxBuckets: DefaultMaxBuckets,
+	}
+}
+
+// Allow расходует токен из корзины клиента key, если он есть, и возвращает состояние корзины.
+// Если количество корзин достигло предела и после удаления восполненных корзин места не осталось,
+// запрос нового клиента отклоняется, чтобы поток запросов с разными ключами не исчерпал память.
+func (limiter *Limiter) Allow(key string, now time.Time) Result {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+	capacity := float64(limiter.rule.Requests)
+	if now.Sub(limiter.lastSweep) >= sweepInterval {
+		limiter.sweep(now)
+	}
+	b, ok := limiter.buckets[key]
+	if !ok {
+		if len(limiter.buckets) >= limiter.maxBuckets {
+			limiter.sweep(now)
+		}
+		if len(limiter.buckets) >= limiter.maxBuckets {
+			return Result{Limit: limiter.rule.Requests, Period: limiter.rule.Period, RetryAfter: limiter.duration(1)}
+		}
+		b = &bucket{tokens: capacity, updated: now}
+		limiter.buckets[key] = b
+	}
+	if elapsed := now.Sub(b.updated); elapsed > 0 {
+		b.tokens = math.Min(capacity, b.tokens+float64(elapsed)*limiter.rate)
+		b.updated = now
+	}
+	result := Result{Limit: limiter.rule.Requests, Period: limiter.rule.Period}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = limiter.duration(1 - b.tokens)
+	}
+	result.Remaining = int(b.tokens)
+	result.Reset = limiter.duration(capacity - b.tokens)
+	return result
+}
+
+// sweep удаляет корзины, которые к моменту now полностью восполнились.
+func (limiter *Limiter) sweep(now time.Time) {
+	capacity := float64(limiter.rule.Requests)
+	for key, b := range limiter.buckets {
+		if b.tokens+float64(now.Sub(b.updated))*limiter.rate >= capacity {
+			delete(limiter.buckets, key)
+		}
+	}
+	limiter.lastSweep = now
+}
+
+// duration возвращает время восполнения tokens токенов.
+func (limiter *Limiter) duration(tokens float64) time.Duration {
+	return time.Duration(math.Ceil(tokens / limiter.rate))
+}
+
+// Limiters содержит ограничители групп маршрутов. Группа без ограничителя не ограничивается.
+type Limiters struct {
+	groups map[string]*Limiter
+}
+
+// New создает ограничители групп маршрутов по конфигурации. Группа с пустым лимитом не ограничивается.
+func New(config config.Config) (*Limiters, error) {
+	specs := map[string]string{
+		GroupCreate: config.RateLimitCreate,
+		GroupExpand: config.RateLimitExpand,
+		GroupUser:   config.RateLimitUser,
+	}
+	limiters := &Limiters{groups: make(map[string]*Limiter)}
+	var errs []error
+	for group, spec := range specs {
+		if spec == "" {
+			continue
+		}
+		rule, err := ParseRule(spec)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", group, err))
+			continue
+		}
+		limiters.groups[group] = NewLimiter(rule)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return limiters, nil
+}
+
+// Allow проверяет лимит группы group для клиента key.
+// Если группа не ограничивается, возвращается false вторым значением.
+func (limiters *Limiters) Allow(group, key string) (Result, bool) {
+	if limiters == nil {
+		return Result{}, false
+	}
+	limiter, ok := limiters.groups[group]
+	if !ok {
+		return Result{}, false
+	}
+	return limiter.Allow(key, time.Now()), true
+}
+
+// UserKey возвращает ключ клиента для пользователя с идентификатором userID.
+func UserKey(userID int) string {
+	return "user:" + strconv.Itoa(userID)
+}
+
+// IPKey возвращает ключ клиента для IP-адреса ip.
+func IPKey(ip string) string {
+	return "ip:" + ip
+}
+
+// seconds округляет длительность вверх до целых секунд для заголовков ответа.
+func seconds(d time.Duration) int64 {
+	return int64(math.Ceil(d.Seconds()))
+}
+
+// Headers возвращает заголовки ответа с состоянием лимита:
+// RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset, RateLimit-Policy и, если запрос отклонен, Retry-After.
+func (result Result) Headers() map[string]string {
+	headers := map[string]string{
+		"RateLimit-Limit":     strconv.Itoa(result.Limit),
+		"RateLimit-Remaining": strconv.Itoa(result.Remaining),
+		"RateLimit-Reset":     strconv.FormatInt(seconds(result.Reset), 10),
+		"RateLimit-Policy":    fmt.Sprintf("%d;w=%d", result.Limit, seconds(result.Period)),
+	}
+	if !result.Allowed {
+		headers["Retry-After"] = strconv.FormatInt(seconds(result.RetryAfter), 10)
+	}
+	return headers
+}
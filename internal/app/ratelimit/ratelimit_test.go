@@ -0,0 +1,116 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRule(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    Rule
+		wantErr bool
+	}{
+		{spec: "100/1m", want: Rule{Requests: 100, Period: time.Minute}},
+		{spec: "10/s", want: Rule{Requests: 10, Period: time.Second}},
+		{spec: "5/h", want: Rule{Requests: 5, Period: time.Hour}},
+		{spec: "100", wantErr: true},
+		{spec: "0/1m", wantErr: true},
+		{spec: "x/1m", wantErr: true},
+		{spec: "10/", wantErr: true},
+		{spec: "10/-1m", wantErr: true},
+	}
+	for _, test := range tests {
+		rule, err := ParseRule(test.spec)
+		if test.wantErr {
+			assert.Error(t, err, test.spec)
+			continue
+		}
+		require.NoError(t, err, test.spec)
+		assert.Equal(t, test.want, rule)
+	}
+}
+
+func TestLimiterAllow(t *testing.T) {
+	limiter := NewLimiter(Rule{Requests: 2, Period: 10 * time.Second})
+	now := time.Now()
+
+	result := limiter.Allow("a", now)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 1, result.Remaining)
+	assert.Equal(t, 5*time.Second, result.Reset)
+	assert.True(t, limiter.Allow("a", now).Allowed)
+
+	result = limiter.Allow("a", now)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+	assert.Equal(t, 5*time.Second, result.RetryAfter)
+	assert.Equal(t, 10*time.Second, result.Reset)
+
+	// Другой клиент учитывается отдельно
+	assert.True(t, limiter.Allow("b", now).Allowed)
+
+	// Через половину периода восполняется один токен
+	assert.True(t, limiter.Allow("a", now.Add(5*time.Second)).Allowed)
+	assert.False(t, limiter.Allow("a", now.Add(5*time.Second)).Allowed)
+
+	// Восполненные корзины неактивных клиентов удаляются
+	limiter.Allow("c", now.Add(time.Hour))
+	assert.Len(t, limiter.buckets, 1)
+}
+
+func TestLimiterMaxBuckets(t *testing.T) {
+	limiter := NewLimiter(Rule{Requests: 2, Period: 10 * time.Second})
+	limiter.maxBuckets = 2
+	now := time.Now()
+
+	assert.True(t, limiter.Allow("a", now).Allowed)
+	assert.True(t, limiter.Allow("b", now).Allowed)
+	// Новый клиент отклоняется, пока корзины известных клиентов не восполнятся
+	result := limiter.Allow("c", now)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 5*time.Second, result.RetryAfter)
+	assert.Len(t, limiter.buckets, 2)
+	assert.True(t, limiter.Allow("a", now).Allowed, "known client keeps its bucket")
+
+	assert.True(t, limiter.Allow("c", now.Add(10*time.Second)).Allowed)
+}
+
+func TestResultHeaders(t *testing.T) {
+	headers := Result{Allowed: false, Limit: 10, Period: time.Minute, Remaining: 0, Reset: 1500 * time.Millisecond, RetryAfter: 200 * time.Millisecond}.Headers()
+	assert.Equal(t, map[string]string{
+		"RateLimit-Limit":     "10",
+		"RateLimit-Remaining": "0",
+		"RateLimit-Reset":     "2",
+		"RateLimit-Policy":    "10;w=60",
+		"Retry-After":         "1",
+	}, headers)
+}
+
+func TestNew(t *testing.T) {
+	config := config.GetDefault()
+	config.RateLimitCreate = "1/1m"
+	limiters, err := New(config)
+	require.NoError(t, err)
+
+	result, limited := limiters.Allow(GroupCreate, IPKey("10.0.0.1"))
+	assert.True(t, limited)
+	assert.True(t, result.Allowed)
+	result, _ = limiters.Allow(GroupCreate, IPKey("10.0.0.1"))
+	assert.False(t, result.Allowed)
+
+	_, limited = limiters.Allow(GroupExpand, IPKey("10.0.0.1"))
+	assert.False(t, limited)
+
+	var nilLimiters *Limiters
+	_, limited = nilLimiters.Allow(GroupCreate, IPKey("10.0.0.1"))
+	assert.False(t, limited)
+
+	config.RateLimitUser = "many/1m"
+	_, err = New(config)
+	assert.Error(t, err)
+}
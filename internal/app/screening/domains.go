@@ -0,0 +1,211 @@
+package screening
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/logger"
+	"golang.org/x/net/idna"
+)
+
+// Действия правил списка доменов.
+const (
+	actionAllow = "allow"
+	actionDeny  = "deny"
+)
+
+// domainRule правило списка доменов.
+// Пустой domain с wildcard соответствует любому домену.
+type domainRule struct {
+	action   string
+	domain   string
+	wildcard bool
+}
+
+// matches проверяет, соответствует ли домен правилу.
+func (rule domainRule) matches(host string) bool {
+	if !rule.wildcard {
+		return host == rule.domain
+	}
+	return rule.domain == "" || host == rule.domain || strings.HasSuffix(host, "."+rule.domain)
+}
+
+// specificity возвращает точность правила: чем длиннее домен, тем точнее правило, точный домен точнее шаблона.
+func (rule domainRule) specificity() int {
+	score := 2 * len(rule.domain)
+	if !rule.wildcard {
+		score++
+	}
+	return score
+}
+
+// String возвращает правило в формате файла.
+func (rule domainRule) String() string {
+	switch {
+	case rule.wildcard && rule.domain == "":
+		return rule.action + " *"
+	case rule.wildcard:
+		return rule.action + " *." + rule.domain
+	default:
+		return rule.action + " " + rule.domain
+	}
+}
+
+// DomainList проверяет домен URL по списку запрещенных и разрешенных доменов из файла.
+//
+// Каждая строка файла содержит правило "deny <шаблон>" или "allow <шаблон>", строка из одного шаблона считается правилом deny.
+// Пустые строки и строки, начинающиеся с "#", пропускаются. Шаблон задается точным доменом (example.com),
+// доменом со всеми поддоменами (*.example.com) или "*" для любого домена.
+// Из подходящих правил применяется самое точное, при равной точности запрет важнее разрешения.
+// Так правило "deny *" вместе с правилами allow превращает список в список разрешенных доменов.
+type DomainList struct {
+	path string
+
+	mu      sync.RWMutex
+	rules   []domainRule
+	modTime time.Time
+	size    int64
+}
+
+// NewDomainList загружает список доменов из файла path.
+func NewDomainList(path string) (*DomainList, error) {
+	list := &DomainList{path: path}
+	if _, err := list.Reload(); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// Screen отклоняет URL, домен которого запрещен списком.
+func (list *DomainList) Screen(_ context.Context, rawURL string) (Verdict, error) {
+	host, err := hostOf(rawURL)
+	if err != nil {
+		return Verdict{}, err
+	}
+	list.mu.RLock()
+	defer list.mu.RUnlock()
+	var best *domainRule
+	for i := range list.rules {
+		rule := &list.rules[i]
+		if !rule.matches(host) {
+			continue
+		}
+		if best == nil || rule.specificity() > best.specificity() ||
+			rule.specificity() == best.specificity() && rule.action == actionDeny {
+			best = rule
+		}
+	}
+	if best == nil || best.action == actionAllow {
+		return Verdict{}, nil
+	}
+	return Verdict{Blocked: true, Reason: fmt.Sprintf("domain %s is blocked by rule %q", host, best.String())}, nil
+}
+
+// Watch с интервалом interval проверяет, изменился ли файл списка, и перечитывает его до отмены ctx.
+// Если новый файл не удалось прочитать или разобрать, продолжают действовать прежние правила.
+// Неположительный interval отключает перечитывание.
+func (list *DomainList) Watch(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reloaded, err := list.Reload()
+			if err != nil {
+				logger.Logger.Error("failed to reload domain list", "path", list.path, "error", err)
+				continue
+			}
+			if reloaded {
+				logger.Logger.Info("domain list is reloaded", "path", list.path)
+			}
+		}
+	}
+}
+
+// Reload перечитывает файл списка, если с прошлой загрузки изменились время его изменения или размер,
+// и сообщает, были ли правила обновлены.
+func (list *DomainList) Reload() (bool, error) {
+	info, err := os.Stat(list.path)
+	if err != nil {
+		return false, err
+	}
+	list.mu.RLock()
+	unchanged := info.ModTime().Equal(list.modTime) && info.Size() == list.size && list.rules != nil
+	list.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+	data, err := os.ReadFile(list.path)
+	if err != nil {
+		return false, err
+	}
+	rules, err := parseDomainRules(data)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", list.path, err)
+	}
+	list.mu.Lock()
+	list.rules = rules
+	list.modTime = info.ModTime()
+	list.size = info.Size()
+	list.mu.Unlock()
+	return true, nil
+}
+
+// parseDomainRules разбирает содержимое файла списка доменов.
+func parseDomainRules(data []byte) ([]domainRule, error) {
+	rules := make([]domainRule, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+		action, pattern := actionDeny, fields[0]
+		switch {
+		case len(fields) == 2 && (fields[0] == actionAllow || fields[0] == actionDeny):
+			action, pattern = fields[0], fields[1]
+		case len(fields) != 1:
+			return nil, fmt.Errorf("line %d: expected \"allow|deny <pattern>\"", line)
+		}
+		rule, err := parseDomainPattern(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		rule.action = action
+		rules = append(rules, rule)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// parseDomainPattern разбирает шаблон домена. Интернационализированные домены переводятся в punycode.
+func parseDomainPattern(pattern string) (domainRule, error) {
+	if pattern == "*" {
+		return domainRule{wildcard: true}, nil
+	}
+	rule := domainRule{}
+	if domain, ok := strings.CutPrefix(pattern, "*."); ok {
+		rule.wildcard = true
+		pattern = domain
+	}
+	domain, err := idna.Lookup.ToASCII(strings.TrimSuffix(pattern, "."))
+	if err != nil || domain == "" || strings.Contains(domain, "*") {
+		return domainRule{}, fmt.Errorf("invalid domain pattern %q", pattern)
+	}
+	rule.domain = domain
+	return rule, nil
+}
@@ -0,0 +1,54 @@
+// Package screening предоставляет проверку исходных URL перед сокращением:
+// списки запрещенных и разрешенных доменов из файла и обращение к локальному сервису проверки.
+package screening
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// Verdict результат проверки URL.
+type Verdict struct {
+	Blocked bool   // Blocked признак того, что URL отклонен.
+	Reason  string // Reason причина, по которой URL отклонен.
+}
+
+// Screener проверяет исходный URL перед сокращением.
+type Screener interface {
+	Screen(ctx context.Context, rawURL string) (Verdict, error)
+}
+
+// Chain последовательно опрашивает проверки и возвращает первый вердикт, отклоняющий URL.
+// Ошибки проверок не прерывают опрос остальных и возвращаются вместе с итоговым вердиктом,
+// чтобы вызывающий решил, пропускать ли URL при недоступности проверки.
+type Chain []Screener
+
+// Screen проверяет URL всеми проверками цепочки.
+func (chain Chain) Screen(ctx context.Context, rawURL string) (Verdict, error) {
+	var errs []error
+	for _, screener := range chain {
+		verdict, err := screener.Screen(ctx, rawURL)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if verdict.Blocked {
+			return verdict, nil
+		}
+	}
+	return Verdict{}, errors.Join(errs...)
+}
+
+// hostOf возвращает домен URL в нижнем регистре без завершающей точки.
+func hostOf(rawURL string) (string, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	if parsed.Hostname() == "" {
+		return "", errors.New("url has no host")
+	}
+	return strings.TrimSuffix(strings.ToLower(parsed.Hostname()), "."), nil
+}
@@ -0,0 +1,146 @@
+package screening
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newDomainList(t *testing.T, content string) (*DomainList, string) {
+	path := filepath.Join(t.TempDir(), "domains")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	list, err := NewDomainList(path)
+	require.NoError(t, err)
+	return list, path
+}
+
+func TestDomainListScreen(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		url     string
+		blocked bool
+	}{
+		{name: "exact", content: "deny evil.com", url: "https://evil.com/login", blocked: true},
+		{name: "exact does not match subdomain", content: "deny evil.com", url: "https://www.evil.com/", blocked: false},
+		{name: "bare pattern is deny", content: "evil.com", url: "https://evil.com/", blocked: true},
+		{name: "wildcard matches domain", content: "deny *.evil.com", url: "https://evil.com/", blocked: true},
+		{name: "wildcard matches subdomain", content: "deny *.evil.com", url: "https://a.b.evil.com/", blocked: true},
+		{name: "wildcard does not match suffix", content: "deny *.evil.com", url: "https://notevil.com/", blocked: false},
+		{name: "not listed", content: "# comment\n\ndeny evil.com", url: "https://example.com/", blocked: false},
+		{name: "specific allow wins", content: "deny *.example.com\nallow safe.example.com", url: "https://safe.example.com/", blocked: false},
+		{name: "specific deny wins", content: "allow *.example.com\ndeny bad.example.com", url: "https://bad.example.com/", blocked: true},
+		{name: "deny wins tie", content: "allow evil.com\ndeny evil.com", url: "https://evil.com/", blocked: true},
+		{name: "allowlist mode", content: "deny *\nallow *.example.com", url: "https://example.org/", blocked: true},
+		{name: "allowlist mode allowed", content: "deny *\nallow *.example.com", url: "https://www.example.com/", blocked: false},
+		{name: "idn pattern", content: "deny *.пример.рф", url: "https://www.xn--e1afmkfd.xn--p1ai/", blocked: true},
+		{name: "case insensitive", content: "deny EVIL.com.", url: "https://Evil.COM/", blocked: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			list, _ := newDomainList(t, test.content)
+			verdict, err := list.Screen(context.Background(), test.url)
+			require.NoError(t, err)
+			assert.Equal(t, test.blocked, verdict.Blocked)
+			if test.blocked {
+				assert.NotEmpty(t, verdict.Reason)
+			}
+		})
+	}
+}
+
+func TestDomainListInvalid(t *testing.T) {
+	for _, content := range []string{"block evil.com", "deny evil.com extra", "deny *evil.com", "deny *.*.com"} {
+		path := filepath.Join(t.TempDir(), "domains")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		_, err := NewDomainList(path)
+		assert.Error(t, err, content)
+	}
+}
+
+func TestDomainListWatch(t *testing.T) {
+	require.NoError(t, logger.Init(slog.LevelInfo))
+	list, path := newDomainList(t, "deny evil.com\n")
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		list.Watch(ctx, 10*time.Millisecond)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+	blocked := func(url string) bool {
+		verdict, err := list.Screen(context.Background(), url)
+		require.NoError(t, err)
+		return verdict.Blocked
+	}
+
+	require.NoError(t, os.WriteFile(path, []byte("deny evil.com\ndeny bad.com\n"), 0o644))
+	assert.Eventually(t, func() bool { return blocked("https://bad.com/") }, time.Second, 10*time.Millisecond)
+
+	// Некорректный файл не сбрасывает прежние правила.
+	require.NoError(t, os.WriteFile(path, []byte("block everything\n"), 0o644))
+	time.Sleep(50 * time.Millisecond)
+	assert.True(t, blocked("https://evil.com/"))
+	assert.True(t, blocked("https://bad.com/"))
+}
+
+func TestServiceScreener(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request serviceRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		switch request.URL {
+		case "https://phishing.example/":
+			w.Write([]byte(`{"blocked":true,"reason":"phishing"}`))
+		case "https://unknown.example/":
+			w.Write([]byte(`{"blocked":true}`))
+		case "https://broken.example/":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.Write([]byte(`{"blocked":false}`))
+		}
+	}))
+	defer server.Close()
+	screener := NewServiceScreener(server.URL, time.Second)
+
+	verdict, err := screener.Screen(context.Background(), "https://phishing.example/")
+	require.NoError(t, err)
+	assert.Equal(t, Verdict{Blocked: true, Reason: "phishing"}, verdict)
+
+	verdict, err = screener.Screen(context.Background(), "https://unknown.example/")
+	require.NoError(t, err)
+	assert.True(t, verdict.Blocked)
+	assert.NotEmpty(t, verdict.Reason)
+
+	verdict, err = screener.Screen(context.Background(), "https://example.com/")
+	require.NoError(t, err)
+	assert.False(t, verdict.Blocked)
+
+	_, err = screener.Screen(context.Background(), "https://broken.example/")
+	assert.Error(t, err)
+}
+
+func TestChain(t *testing.T) {
+	list, _ := newDomainList(t, "deny evil.com")
+	failing := NewServiceScreener("http://127.0.0.1:1", 100*time.Millisecond)
+
+	verdict, err := Chain{failing, list}.Screen(context.Background(), "https://evil.com/")
+	require.NoError(t, err)
+	assert.True(t, verdict.Blocked)
+
+	verdict, err = Chain{list, failing}.Screen(context.Background(), "https://example.com/")
+	assert.Error(t, err)
+	assert.False(t, verdict.Blocked)
+}
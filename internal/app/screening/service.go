@@ -0,0 +1,70 @@
+package screening
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// DefaultServiceTimeout время ожидания ответа сервиса проверки по умолчанию.
+const DefaultServiceTimeout = 2 * time.Second
+
+// serviceRequest тело запроса к сервису проверки.
+type serviceRequest struct {
+	URL string `json:"url"`
+}
+
+// serviceResponse тело ответа сервиса проверки.
+type serviceResponse struct {
+	Blocked bool   `json:"blocked"`
+	Reason  string `json:"reason"`
+}
+
+// ServiceScreener проверяет URL локальным сервисом проверки.
+// Сервис принимает POST-запрос с телом {"url": "..."} и отвечает 200 с телом {"blocked": true|false, "reason": "..."}.
+type ServiceScreener struct {
+	address string
+	client  *http.Client
+}
+
+// NewServiceScreener создает проверку сервисом по адресу address с временем ожидания ответа timeout.
+func NewServiceScreener(address string, timeout time.Duration) *ServiceScreener {
+	return &ServiceScreener{
+		address: address,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+// Screen отправляет URL сервису проверки и возвращает его вердикт.
+func (screener *ServiceScreener) Screen(ctx context.Context, rawURL string) (Verdict, error) {
+	body, err := json.Marshal(serviceRequest{URL: rawURL})
+	if err != nil {
+		return Verdict{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, screener.address, bytes.NewReader(body))
+	if err != nil {
+		return Verdict{}, err
+	}
+	req.Header.Set("content-type", "application/json")
+	res, err := screener.client.Do(req)
+	if err != nil {
+		return Verdict{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		io.Copy(io.Discard, res.Body)
+		return Verdict{}, fmt.Errorf("screening service responded with status %d", res.StatusCode)
+	}
+	var response serviceResponse
+	if err := json.NewDecoder(res.Body).Decode(&response); err != nil {
+		return Verdict{}, fmt.Errorf("screening service response is malformed: %w", err)
+	}
+	if response.Blocked && response.Reason == "" {
+		response.Reason = "url is rejected by screening service"
+	}
+	return Verdict{Blocked: response.Blocked, Reason: response.Reason}, nil
+}
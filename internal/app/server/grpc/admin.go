@@ -0,0 +1,223 @@
+package grpc
+
+import (
+	"context"
+	"crypto/subtle"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/config"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/models"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// adminTokenKey ключ метаданных, в котором передается токен администратора.
+const adminTokenKey = "x-admin-token"
+
+// AdminService определяет методы сервиса для административного API.
+type AdminService interface {
+	// AdminGetURL возвращает URL с владельцем и служебными данными.
+	AdminGetURL(ctx context.Context, shortURL string) (models.AdminURL, error)
+	// AdminSetURLDisabled отключает или включает URL.
+	AdminSetURLDisabled(ctx context.Context, shortURL string, disabled bool) (models.AdminURL, error)
+	// AdminSetURLOwner передает URL другому пользователю.
+	AdminSetURLOwner(ctx context.Context, shortURL string, userID int) (models.AdminURL, error)
+	// AdminGetUrlsByUser возвращает страницу URL пользователя со служебными данными.
+	AdminGetUrlsByUser(ctx context.Context, userID int, query models.URLQuery) (models.AdminURLPage, error)
+	// AdminDeleteUrlsOfUser помечает удаленными все URL пользователя.
+	AdminDeleteUrlsOfUser(ctx context.Context, userID int) (models.AdminDeleteResult, error)
+	// AdminDeleteUrlsOfDomain помечает удаленными все URL на домен и его поддомены.
+	AdminDeleteUrlsOfDomain(ctx context.Context, domain string) (models.AdminDeleteResult, error)
+}
+
+type adminHandler struct {
+	service AdminService
+	UnsafeAdminServiceServer
+}
+
+// NewAdminHandler создает обработчик административного API.
+func NewAdminHandler(service AdminService) *adminHandler {
+	return &adminHandler{service: service}
+}
+
+// GetURL возвращает любой сокращенный URL с владельцем и служебными данными.
+func (s *adminHandler) GetURL(ctx context.Context, in *AdminGetURLRequest) (*AdminURL, error) {
+	url, err := s.service.AdminGetURL(ctx, in.ShortURL)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	return adminURLToProto(url), nil
+}
+
+// SetURLDisabled отключает или включает сокращенный URL.
+func (s *adminHandler) SetURLDisabled(ctx context.Context, in *AdminSetURLDisabledRequest) (*AdminURL, error) {
+	url, err := s.service.AdminSetURLDisabled(ctx, in.ShortURL, in.Disabled)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	return adminURLToProto(url), nil
+}
+
+// SetURLOwner передает сокращенный URL другому пользователю.
+func (s *adminHandler) SetURLOwner(ctx context.Context, in *AdminSetURLOwnerRequest) (*AdminURL, error) {
+	url, err := s.service.AdminSetURLOwner(ctx, in.ShortURL, int(in.UserID))
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	return adminURLToProto(url), nil
+}
+
+// GetUrlsByUser возвращает страницу URL пользователя UserID со служебными данными.
+func (s *adminHandler) GetUrlsByUser(ctx context.Context, in *GetUrlsByUserRequest) (*AdminGetUrlsByUserResponse, error) {
+	query, err := urlQueryFromRequest(in)
+	if err != nil {
+		return nil, err
+	}
+	page, err := s.service.AdminGetUrlsByUser(ctx, int(in.UserID), query)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	urls := make([]*AdminURL, 0, len(page.URLs))
+	for _, url := range page.URLs {
+		urls = append(urls, adminURLToProto(url))
+	}
+	return &AdminGetUrlsByUserResponse{URLS: urls, NextCursor: page.NextCursor}, nil
+}
+
+// DeleteUrlsOfUser помечает удаленными все URL пользователя.
+func (s *adminHandler) DeleteUrlsOfUser(ctx context.Context, in *AdminDeleteUrlsOfUserRequest) (*AdminDeleteUrlsResponse, error) {
+	result, err := s.service.AdminDeleteUrlsOfUser(ctx, int(in.UserID))
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	return &AdminDeleteUrlsResponse{Deleted: int32(result.Deleted), ShortURLs: result.ShortURLs}, nil
+}
+
+// DeleteUrlsOfDomain помечает удаленными все URL на домен и его поддомены.
+func (s *adminHandler) DeleteUrlsOfDomain(ctx context.Context, in *AdminDeleteUrlsOfDomainRequest) (*AdminDeleteUrlsResponse, error) {
+	result, err := s.service.AdminDeleteUrlsOfDomain(ctx, in.Domain)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	return &AdminDeleteUrlsResponse{Deleted: int32(result.Deleted), ShortURLs: result.ShortURLs}, nil
+}
+
+func adminURLToProto(url models.AdminURL) *AdminURL {
+	return &AdminURL{
+		ShortURL:    url.ShortURL,
+		OriginalURL: url.OriginalURL,
+		UserID:      int32(url.UserID),
+		CreatedAt:   unixFromTime(&url.CreatedAt),
+		ExpiresAt:   unixFromTime(url.ExpiresAt),
+		IsDeleted:   url.IsDeleted,
+		DeletedAt:   unixFromTime(url.DeletedAt),
+		IsDisabled:  url.IsDisabled,
+		TotalClicks: int32(url.TotalClicks),
+	}
+}
+
+func unixFromTime(t *time.Time) int64 {
+	if t == nil || t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+// AdminInterceptor пропускает вызовы административного API AdminService только из доверенной подсети
+// с токеном администратора в метаданных x-admin-token. IP-адрес клиента берется из метаданных x-real-ip,
+// которые передает прокси, а без них — из адреса соединения. Общая статистика сервиса ShortenerService, как и в HTTP API,
+// доступна только из доверенной подсети, но без токена администратора. Остальные вызовы пропускаются без проверки.
+type AdminInterceptor struct {
+	subnet string
+	token  []byte
+}
+
+// NewAdminInterceptor создает новый экземпляр AdminInterceptor по доверенной подсети и токену администратора из конфигурации.
+// Если токен администратора не задан, административное API отключено.
+func NewAdminInterceptor(config config.Config) *AdminInterceptor {
+	return &AdminInterceptor{subnet: config.TrustedSubnet, token: []byte(config.AdminToken)}
+}
+
+// Unary проверяет доступ к унарным вызовам административного API.
+func (i *AdminInterceptor) Unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if isAdminMethod(info.FullMethod) {
+		if err := i.authorize(ctx); err != nil {
+			return nil, err
+		}
+	} else if _, ok := trustedSubnetMethods[info.FullMethod]; ok {
+		if err := i.checkSubnet(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return handler(ctx, req)
+}
+
+// Stream проверяет доступ к потоковым вызовам административного API.
+func (i *AdminInterceptor) Stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if isAdminMethod(info.FullMethod) {
+		if err := i.authorize(ss.Context()); err != nil {
+			return err
+		}
+	}
+	return handler(srv, ss)
+}
+
+func (i *AdminInterceptor) authorize(ctx context.Context) error {
+	if len(i.token) == 0 || i.subnet == "" {
+		return status.Error(codes.PermissionDenied, "admin api is disabled")
+	}
+	if err := i.checkSubnet(ctx); err != nil {
+		return err
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(adminTokenKey)
+	if len(values) == 0 || subtle.ConstantTimeCompare([]byte(values[0]), i.token) != 1 {
+		return status.Error(codes.Unauthenticated, "invalid admin token")
+	}
+	return nil
+}
+
+// checkSubnet проверяет, что клиент находится в доверенной подсети. Если подсеть не задана, доступ запрещен.
+func (i *AdminInterceptor) checkSubnet(ctx context.Context) error {
+	if i.subnet == "" {
+		return status.Error(codes.PermissionDenied, "trusted subnet is not configured")
+	}
+	_, ipnet, err := net.ParseCIDR(i.subnet)
+	if err != nil {
+		return status.Error(codes.Internal, "invalid trusted subnet")
+	}
+	if !ipnet.Contains(clientIP(ctx)) {
+		return status.Error(codes.PermissionDenied, "client is not in trusted subnet")
+	}
+	return nil
+}
+
+// clientIP возвращает IP-адрес клиента из метаданных x-real-ip или из адреса соединения.
+func clientIP(ctx context.Context) net.IP {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(realIPKey); len(values) > 0 {
+		return net.ParseIP(values[0])
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+			return net.ParseIP(host)
+		}
+	}
+	return nil
+}
+
+// trustedSubnetMethods методы ShortenerService, которые вызываются только из доверенной подсети.
+var trustedSubnetMethods = map[string]struct{}{
+	ShortenerService_GetStats_FullMethodName: {},
+}
+
+// isAdminMethod проверяет, что method является методом административного API.
+// Такие вызовы не аутентифицируются как вызовы пользователя, чтобы не регистрировать пользователей.
+func isAdminMethod(method string) bool {
+	return strings.HasPrefix(method, "/"+AdminService_ServiceDesc.ServiceName+"/")
+}
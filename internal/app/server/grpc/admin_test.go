@@ -0,0 +1,140 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/config"
+	customerrors "github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/errors"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newAdminTestClient(t *testing.T, config config.Config, service Service) AdminServiceClient {
+	return NewAdminServiceClient(newTestConn(t, config, service))
+}
+
+func newTestConn(t *testing.T, config config.Config, service Service) *grpc.ClientConn {
+	listener := bufconn.Listen(1024 * 1024)
+	server := NewServer(config, service, newTestTokens(t), nil, nil, nil)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestAdminService(t *testing.T) {
+	config := config.GetDefault()
+	config.TrustedSubnet = "10.0.0.0/8"
+	config.AdminToken = "admin-secret"
+	// вызовы административного API не аутентифицируются как вызовы пользователя, поэтому CreateUser не ожидается
+	mockService := new(MockShortenerService)
+	createdAt := time.Unix(1700000000, 0)
+	mockService.On("AdminGetURL", mock.Anything, "abc").Return(models.AdminURL{
+		ShortURL:    "abc",
+		OriginalURL: "https://example.com",
+		UserID:      3,
+		CreatedAt:   createdAt,
+		IsDisabled:  true,
+		TotalClicks: 5,
+	}, nil)
+	notFound := customerrors.NewCustomError(customerrors.ErrUserNotFound)
+	notFound.Status = http.StatusNotFound
+	mockService.On("AdminSetURLOwner", mock.Anything, "abc", 42).Return(models.AdminURL{}, notFound)
+	mockService.On("AdminDeleteUrlsOfDomain", mock.Anything, "example.com").Return(models.AdminDeleteResult{
+		Deleted:   2,
+		ShortURLs: []string{"abc", "def"},
+	}, nil)
+	client := newAdminTestClient(t, config, mockService)
+	admin := metadata.Pairs(realIPKey, "10.1.2.3", adminTokenKey, "admin-secret")
+
+	tests := []struct {
+		name     string
+		md       metadata.MD
+		expected codes.Code
+	}{
+		{name: "valid token", md: admin, expected: codes.OK},
+		{name: "no token", md: metadata.Pairs(realIPKey, "10.1.2.3"), expected: codes.Unauthenticated},
+		{name: "invalid token", md: metadata.Pairs(realIPKey, "10.1.2.3", adminTokenKey, "secret"), expected: codes.Unauthenticated},
+		{name: "untrusted subnet", md: metadata.Pairs(realIPKey, "192.168.0.1", adminTokenKey, "admin-secret"), expected: codes.PermissionDenied},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := client.GetURL(metadata.NewOutgoingContext(context.Background(), test.md), &AdminGetURLRequest{ShortURL: "abc"})
+			assert.Equal(t, test.expected, status.Code(err))
+		})
+	}
+
+	ctx := metadata.NewOutgoingContext(context.Background(), admin)
+	url, err := client.GetURL(ctx, &AdminGetURLRequest{ShortURL: "abc"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), url.UserID)
+	assert.Equal(t, createdAt.Unix(), url.CreatedAt)
+	assert.Zero(t, url.ExpiresAt)
+	assert.True(t, url.IsDisabled)
+	assert.Equal(t, int32(5), url.TotalClicks)
+
+	_, err = client.SetURLOwner(ctx, &AdminSetURLOwnerRequest{ShortURL: "abc", UserID: 42})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	result, err := client.DeleteUrlsOfDomain(ctx, &AdminDeleteUrlsOfDomainRequest{Domain: "example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), result.Deleted)
+	assert.Equal(t, []string{"abc", "def"}, result.ShortURLs)
+	mockService.AssertNotCalled(t, "CreateUser", mock.Anything)
+
+	config.AdminToken = ""
+	client = newAdminTestClient(t, config, mockService)
+	_, err = client.GetURL(ctx, &AdminGetURLRequest{ShortURL: "abc"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err), "admin api is disabled without admin token")
+}
+
+func TestGetStatsTrustedSubnet(t *testing.T) {
+	config := config.GetDefault()
+	config.TrustedSubnet = "10.0.0.0/8"
+	mockService := new(MockShortenerService)
+	mockService.On("GetStats", mock.Anything).Return(models.Stats{URLS: 2, Users: 1}, nil)
+	client := NewShortenerServiceClient(newTestConn(t, config, mockService))
+	value, _, err := newTestTokens(t).Build(7)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		md       metadata.MD
+		expected codes.Code
+	}{
+		{name: "anonymous outside subnet", md: metadata.Pairs(realIPKey, "192.168.0.1"), expected: codes.PermissionDenied},
+		{name: "user outside subnet", md: metadata.Pairs(realIPKey, "192.168.0.1", authorizationKey, bearerPrefix+value), expected: codes.PermissionDenied},
+		{name: "anonymous inside subnet", md: metadata.Pairs(realIPKey, "10.1.2.3"), expected: codes.Unauthenticated},
+		{name: "user inside subnet", md: metadata.Pairs(realIPKey, "10.1.2.3", authorizationKey, bearerPrefix+value), expected: codes.OK},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := client.GetStats(metadata.NewOutgoingContext(context.Background(), test.md), &GetStatsRequest{})
+			assert.Equal(t, test.expected, status.Code(err))
+		})
+	}
+	mockService.AssertNumberOfCalls(t, "GetStats", 1)
+
+	config.TrustedSubnet = ""
+	client = NewShortenerServiceClient(newTestConn(t, config, mockService))
+	ctx := metadata.NewOutgoingContext(context.Background(), metadata.Pairs(realIPKey, "10.1.2.3", authorizationKey, bearerPrefix+value))
+	_, err = client.GetStats(ctx, &GetStatsRequest{})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err), "stats are unavailable without trusted subnet")
+}
@@ -7,22 +7,18 @@ import (
 	"os/exec"
 	"time"
 
-	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/models"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
 )
 
-func ExampleStartServer() {
+func ExampleNewServer() {
 	cmd := exec.Command("go", "run", "./start_server/example_start_server.go")
 	err := cmd.Start()
 	if err != nil {
 		log.Fatal(err)
 	}
-	md := metadata.New(map[string]string{
-		string(models.UserID): "1",
-	})
-	ctx := metadata.NewOutgoingContext(context.Background(), md)
+	ctx := context.Background()
 	conn, err := grpc.DialContext(
 		ctx,
 		":50051",
@@ -37,10 +33,14 @@ func ExampleStartServer() {
 	client := NewShortenerServiceClient(conn)
 	request := &CreateShortURLRequest{URL: "http://example.com"}
 
-	responseCreate, err := client.CreateShortURL(ctx, request)
+	// Анонимному клиенту сервер выдает токен в метаданных заголовка ответа,
+	// который передается в последующих вызовах.
+	var header metadata.MD
+	responseCreate, err := client.CreateShortURL(ctx, request, grpc.Header(&header), grpc.WaitForReady(true))
 	if err != nil {
 		panic(err)
 	}
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", header.Get("authorization")[0])
 
 	responseGet, err := client.GetByShortURL(ctx, &GetByShortURLRequest{ShortURL: "abc123"})
 	if err != nil {
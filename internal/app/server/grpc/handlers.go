@@ -3,30 +3,39 @@ package grpc
 import (
 	"context"
 	"errors"
-	"strconv"
+	"net/http"
+	"time"
 
 	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/config"
+	customerrors "github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/errors"
 	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/models"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/metadata"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/util"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // ShortenerService определяет методы для взаимодействия с сервисом сокращения URL.
 type ShortenerService interface {
 	// CreateShortURL создает сокращенный URL на основе исходного URL.
-	CreateShortURL(ctx context.Context, userInfo models.UserInfo, shortURL string) (string, error)
+	CreateShortURL(ctx context.Context, userInfo models.UserInfo, request models.Request) (string, error)
 	// CreateBatchShortURL создает несколько сокращенных URL на основе списка исходных URL.
 	CreateBatchShortURL(ctx context.Context, userInfo models.UserInfo, arr []models.OriginalURLInfoBatch) ([]models.ShortURLInfoBatch, error)
 	// GetByShortURL возвращает исходный URL по сокращенному URL.
 	GetByShortURL(ctx context.Context, shortURL string) (string, error)
 	// PingStorage проверяет доступность хранилища данных.
 	PingStorage(ctx context.Context) bool
-	// GetUrlsByUser возвращает список URL, созданных пользователем.
-	GetUrlsByUser(ctx context.Context, userInfo models.UserInfo) ([]models.URLByUser, error)
-	// DeleteUrlsByUser удаляет список URL, созданных пользователем.
-	DeleteUrlsByUser(ctx context.Context, userInfo models.UserInfo, urls []string)
+	// GetUrlsByUser возвращает страницу URL, созданных пользователем.
+	GetUrlsByUser(ctx context.Context, userInfo models.UserInfo, query models.URLQuery) (models.URLPage, error)
+	// DeleteUrlsByUser создает задание на удаление списка URL, созданных пользователем.
+	DeleteUrlsByUser(ctx context.Context, userInfo models.UserInfo, urls []string) (models.DeletionJob, error)
+	// RestoreUrlsByUser восстанавливает удаленные URL, созданные пользователем.
+	RestoreUrlsByUser(ctx context.Context, userInfo models.UserInfo, urls []string) ([]models.RestoreResult, error)
+	// GetDeletionJob возвращает задание на удаление, созданное пользователем.
+	GetDeletionJob(ctx context.Context, userInfo models.UserInfo, id string) (models.DeletionJob, error)
 	// GetStats возвращающий в ответ объект статистики.
 	GetStats(ctx context.Context) (models.Stats, error)
+	// GetURLStats возвращает статистику переходов по сокращенному URL, созданному пользователем.
+	GetURLStats(ctx context.Context, userInfo models.UserInfo, shortURL string) (models.LinkStats, error)
 }
 
 type shortenerHandler struct {
@@ -42,32 +51,29 @@ func NewShortenerHandler(config config.Config, service ShortenerService) *shorte
 	}
 }
 
-func UnarySecurityInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
-	md, ok := metadata.FromIncomingContext(ctx)
-	if !ok {
-		return nil, errors.New("user ID not found in metadata")
-	}
-	userIDStr := md.Get(string(models.UserID))
-	if len(userIDStr) == 0 {
-		return nil, errors.New("user ID not found in metadata")
-	}
-	userID, err := strconv.Atoi(userIDStr[0])
-	if err != nil {
-		return nil, err
-	}
-	resp, err = handler(context.WithValue(ctx, models.UserID, userID), req)
-	return resp, err
-}
-
 // CreateShortURL создает сокращенный URL на основе исходного URL.
+// Если пользователь уже сокращал URL и запрос требует дедупликации, возвращается существующий сокращенный URL с описанием в Error.
 func (s *shortenerHandler) CreateShortURL(ctx context.Context, in *CreateShortURLRequest) (*CreateShortURLResponse, error) {
 	userID, ok := ctx.Value(models.UserID).(int)
 	if !ok {
 		return nil, errors.New("invalid user id")
 	}
-	shortURL, err := s.service.CreateShortURL(ctx, models.UserInfo{UserID: userID}, in.URL)
+	shortURL, err := s.service.CreateShortURL(ctx, models.UserInfo{UserID: userID}, models.Request{
+		URL:         in.URL,
+		CustomAlias: in.CustomAlias,
+		ExpiresAt:   timeFromUnix(in.ExpiresAt),
+		TTL:         in.Ttl,
+		Dedupe:      in.Dedupe,
+	})
+	var customErr *customerrors.CustomError
+	if errors.As(err, &customErr) && customErr.Status == http.StatusConflict && customErr.ShortURL != "" {
+		return &CreateShortURLResponse{
+			URL:   s.serverConfig.BaseReturnURL + "/" + customErr.ShortURL,
+			Error: customErr.Error(),
+		}, nil
+	}
 	if err != nil {
-		return nil, err
+		return nil, statusFromError(err)
 	}
 	return &CreateShortURLResponse{
 		URL: s.serverConfig.BaseReturnURL + "/" + shortURL,
@@ -82,17 +88,26 @@ func (s *shortenerHandler) CreateBatchShortURL(ctx context.Context, in *CreateBa
 	}
 	urlsOrig := make([]models.OriginalURLInfoBatch, 0, len(in.URLS))
 	for _, url := range in.URLS {
-		urlsOrig = append(urlsOrig, models.OriginalURLInfoBatch{OriginalURL: url.OriginalUrl, CorrelationID: url.CorrelationId})
+		urlsOrig = append(urlsOrig, models.OriginalURLInfoBatch{
+			OriginalURL:   url.OriginalUrl,
+			CorrelationID: url.CorrelationId,
+			CustomAlias:   url.CustomAlias,
+			ExpiresAt:     timeFromUnix(url.ExpiresAt),
+			TTL:           url.Ttl,
+			Dedupe:        url.Dedupe,
+		})
 	}
 	urlsShort, err := s.service.CreateBatchShortURL(ctx, models.UserInfo{UserID: userID}, urlsOrig)
 	if err != nil {
-		return nil, err
+		return nil, statusFromError(err)
 	}
 	outURLS := make([]*CreateBatchShortURLResponseItem, 0, len(urlsShort))
 	for _, url := range urlsShort {
 		outURLS = append(outURLS, &CreateBatchShortURLResponseItem{
 			CorrelationId: url.CorrelationID,
-			ShortUrl:      s.serverConfig.BaseReturnURL + "/" + url.ShortURL,
+			ShortUrl:      url.ShortURL,
+			Status:        url.Status,
+			Error:         url.Error,
 		})
 	}
 	return &CreateBatchShortURLResponse{URLS: outURLS}, nil
@@ -100,23 +115,15 @@ func (s *shortenerHandler) CreateBatchShortURL(ctx context.Context, in *CreateBa
 
 // GetByShortURL возвращает исходный URL по сокращенному URL.
 func (s *shortenerHandler) GetByShortURL(ctx context.Context, in *GetByShortURLRequest) (*GetByShortURLResponse, error) {
-	_, ok := ctx.Value(models.UserID).(int)
-	if !ok {
-		return nil, errors.New("invalid user id")
-	}
 	urlOrig, err := s.service.GetByShortURL(ctx, in.ShortURL)
 	if err != nil {
-		return nil, err
+		return nil, statusFromError(err)
 	}
 	return &GetByShortURLResponse{OriginalURL: urlOrig}, nil
 }
 
 // PingStorage проверяет доступность хранилища данных.
 func (s *shortenerHandler) PingStorage(ctx context.Context, in *PingStorageRequest) (*PingStorageResponse, error) {
-	_, ok := ctx.Value(models.UserID).(int)
-	if !ok {
-		return nil, errors.New("invalid user id")
-	}
 	ping := s.service.PingStorage(ctx)
 	return &PingStorageResponse{Ping: ping}, nil
 }
@@ -127,21 +134,25 @@ func (s *shortenerHandler) GetUrlsByUser(ctx context.Context, in *GetUrlsByUserR
 	if !ok {
 		return nil, errors.New("invalid user id")
 	}
-	urls, err := s.service.GetUrlsByUser(ctx, models.UserInfo{UserID: userID})
+	query, err := urlQueryFromRequest(in)
 	if err != nil {
 		return nil, err
 	}
-	urlsOut := make([]*GetUrlsByUserResponseItem, 0, len(urls))
-	for _, url := range urls {
+	page, err := s.service.GetUrlsByUser(ctx, models.UserInfo{UserID: userID}, query)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	urlsOut := make([]*GetUrlsByUserResponseItem, 0, len(page.URLs))
+	for _, url := range page.URLs {
 		urlsOut = append(urlsOut, &GetUrlsByUserResponseItem{
 			ShortUrl:    s.serverConfig.BaseReturnURL + "/" + url.ShortURL,
 			OriginalUrl: url.OriginalURL,
 		})
 	}
-	return &GetUrlsByUserResponse{URLS: urlsOut}, nil
+	return &GetUrlsByUserResponse{URLS: urlsOut, NextCursor: page.NextCursor}, nil
 }
 
-// DeleteUrlsByUser удаляет список URL, созданных пользователем.
+// DeleteUrlsByUser создает задание на удаление списка URL, созданных пользователем.
 func (s *shortenerHandler) DeleteUrlsByUser(ctx context.Context, in *DeleteUrlsByUserRequest) (*DeleteUrlsByUserResponse, error) {
 	userID, ok := ctx.Value(models.UserID).(int)
 	if !ok {
@@ -151,19 +162,155 @@ func (s *shortenerHandler) DeleteUrlsByUser(ctx context.Context, in *DeleteUrlsB
 	for _, url := range in.URLS {
 		urlsToDelete = append(urlsToDelete, url.ShortURL)
 	}
-	s.service.DeleteUrlsByUser(ctx, models.UserInfo{UserID: userID}, urlsToDelete)
-	return &DeleteUrlsByUserResponse{}, nil
+	job, err := s.service.DeleteUrlsByUser(ctx, models.UserInfo{UserID: userID}, urlsToDelete)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	return &DeleteUrlsByUserResponse{JobID: job.ID}, nil
 }
 
-// GetStats возвращающий в ответ объект статистики.
-func (s *shortenerHandler) GetStats(ctx context.Context, in *GetStatsRequest) (*GetStatsResponse, error) {
-	_, ok := ctx.Value(models.UserID).(int)
+// RestoreUrlsByUser восстанавливает удаленные URL, созданные пользователем.
+func (s *shortenerHandler) RestoreUrlsByUser(ctx context.Context, in *RestoreUrlsByUserRequest) (*RestoreUrlsByUserResponse, error) {
+	userID, ok := ctx.Value(models.UserID).(int)
 	if !ok {
 		return nil, errors.New("invalid user id")
 	}
+	urlsToRestore := make([]string, 0, len(in.URLS))
+	for _, url := range in.URLS {
+		urlsToRestore = append(urlsToRestore, url.ShortURL)
+	}
+	results, err := s.service.RestoreUrlsByUser(ctx, models.UserInfo{UserID: userID}, urlsToRestore)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	urlsOut := make([]*RestoreUrlsByUserResponseItem, 0, len(results))
+	for _, result := range results {
+		urlsOut = append(urlsOut, &RestoreUrlsByUserResponseItem{ShortURL: result.ShortURL, Status: result.Status})
+	}
+	return &RestoreUrlsByUserResponse{URLS: urlsOut}, nil
+}
+
+// GetDeletionJob возвращает состояние задания на удаление, созданного пользователем.
+func (s *shortenerHandler) GetDeletionJob(ctx context.Context, in *GetDeletionJobRequest) (*GetDeletionJobResponse, error) {
+	userID, ok := ctx.Value(models.UserID).(int)
+	if !ok {
+		return nil, errors.New("invalid user id")
+	}
+	job, err := s.service.GetDeletionJob(ctx, models.UserInfo{UserID: userID}, in.ID)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	urls := make([]*GetDeletionJobResponseItem, 0, len(job.URLs))
+	for _, url := range job.URLs {
+		urls = append(urls, &GetDeletionJobResponseItem{ShortURL: url.ShortURL, Status: url.Status})
+	}
+	return &GetDeletionJobResponse{
+		ID:       job.ID,
+		Status:   job.Status,
+		URLS:     urls,
+		Attempts: int32(job.Attempts),
+		Error:    job.LastError,
+	}, nil
+}
+
+// GetStats возвращающий в ответ объект статистики.
+func (s *shortenerHandler) GetStats(ctx context.Context, in *GetStatsRequest) (*GetStatsResponse, error) {
 	stats, err := s.service.GetStats(ctx)
 	if err != nil {
-		return nil, err
+		return nil, statusFromError(err)
 	}
 	return &GetStatsResponse{URLS: int32(stats.URLS), Users: int32(stats.Users)}, nil
 }
+
+// GetURLStats возвращает статистику переходов по сокращенному URL, созданному пользователем.
+func (s *shortenerHandler) GetURLStats(ctx context.Context, in *GetURLStatsRequest) (*GetURLStatsResponse, error) {
+	userID, ok := ctx.Value(models.UserID).(int)
+	if !ok {
+		return nil, errors.New("invalid user id")
+	}
+	stats, err := s.service.GetURLStats(ctx, models.UserInfo{UserID: userID}, in.ShortURL)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	clicksPerDay := make([]*ClicksPerDayItem, 0, len(stats.ClicksPerDay))
+	for _, day := range stats.ClicksPerDay {
+		clicksPerDay = append(clicksPerDay, &ClicksPerDayItem{Date: day.Date, Clicks: int32(day.Clicks)})
+	}
+	return &GetURLStatsResponse{
+		ShortURL:       stats.ShortURL,
+		TotalClicks:    int32(stats.TotalClicks),
+		UniqueVisitors: int32(stats.UniqueVisitors),
+		ClicksPerDay:   clicksPerDay,
+		TopReferrers:   countItemsToProto(stats.TopReferrers),
+		TopUserAgents:  countItemsToProto(stats.TopUserAgents),
+	}, nil
+}
+
+func countItemsToProto(items []models.CountItem) []*CountItem {
+	out := make([]*CountItem, 0, len(items))
+	for _, item := range items {
+		out = append(out, &CountItem{Value: item.Value, Count: int32(item.Count)})
+	}
+	return out
+}
+
+// urlQueryFromRequest возвращает параметры выборки URL из запроса. Поле UserID запроса не используется.
+func urlQueryFromRequest(in *GetUrlsByUserRequest) (models.URLQuery, error) {
+	cursor, err := util.DecodeURLCursor(in.Cursor)
+	if err != nil {
+		return models.URLQuery{}, status.Error(codes.InvalidArgument, err.Error())
+	}
+	query := models.URLQuery{
+		Limit:               int(in.Limit),
+		Cursor:              cursor,
+		OriginalURLContains: in.OriginalURL,
+		Deleted:             in.Deleted,
+		SortBy:              in.SortBy,
+		SortDesc:            in.SortDesc,
+	}
+	if createdFrom := timeFromUnix(in.CreatedFrom); createdFrom != nil {
+		query.CreatedFrom = *createdFrom
+	}
+	if createdTo := timeFromUnix(in.CreatedTo); createdTo != nil {
+		query.CreatedTo = *createdTo
+	}
+	return query, nil
+}
+
+// timeFromUnix преобразует время в секундах Unix в time.Time, нулевое значение означает отсутствие времени.
+func timeFromUnix(sec int64) *time.Time {
+	if sec == 0 {
+		return nil
+	}
+	t := time.Unix(sec, 0)
+	return &t
+}
+
+// statusFromError преобразует CustomError в ошибку gRPC с кодом, соответствующим статусу HTTP.
+// Остальные ошибки возвращаются без изменений.
+func statusFromError(err error) error {
+	var customErr *customerrors.CustomError
+	if !errors.As(err, &customErr) {
+		return err
+	}
+	return status.Error(codeFromHTTPStatus(customErr.Status), customErr.Error())
+}
+
+func codeFromHTTPStatus(httpStatus int) codes.Code {
+	switch httpStatus {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound, http.StatusGone:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	default:
+		return codes.Internal
+	}
+}
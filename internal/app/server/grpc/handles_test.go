@@ -6,8 +6,11 @@ import (
 	"testing"
 
 	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/config"
+	customerrors "github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/errors"
 	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/models"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func TestCreateShortURL(t *testing.T) {
@@ -16,7 +19,7 @@ func TestCreateShortURL(t *testing.T) {
 	ctx := context.WithValue(context.Background(), models.UserID, 1)
 	request := &CreateShortURLRequest{URL: "http://example.com"}
 
-	mockService.On("CreateShortURL", ctx, models.UserInfo{UserID: 1}, "http://example.com").Return("abc123", nil)
+	mockService.On("CreateShortURL", ctx, models.UserInfo{UserID: 1}, models.Request{URL: "http://example.com"}).Return("abc123", nil)
 
 	response, err := handler.CreateShortURL(ctx, request)
 	assert.NoError(t, err)
@@ -31,7 +34,7 @@ func TestCreateShortURLError(t *testing.T) {
 	ctx := context.WithValue(context.Background(), models.UserID, 1)
 	request := &CreateShortURLRequest{URL: "http://example.com"}
 
-	mockService.On("CreateShortURL", ctx, models.UserInfo{UserID: 1}, "http://example.com").Return("", errors.New("service error"))
+	mockService.On("CreateShortURL", ctx, models.UserInfo{UserID: 1}, models.Request{URL: "http://example.com"}).Return("", errors.New("service error"))
 
 	_, err := handler.CreateShortURL(ctx, request)
 	assert.Error(t, err)
@@ -40,6 +43,39 @@ func TestCreateShortURLError(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestCreateShortURLInvalidURL(t *testing.T) {
+	mockService := new(MockShortenerService)
+	handler := NewShortenerHandler(config.Config{BaseReturnURL: "http://short.url"}, mockService)
+	ctx := context.WithValue(context.Background(), models.UserID, 1)
+	request := &CreateShortURLRequest{URL: "ftp://example.com"}
+
+	mockService.On("CreateShortURL", ctx, models.UserInfo{UserID: 1}, models.Request{URL: "ftp://example.com"}).
+		Return("", customerrors.NewCustomErrorValidation("url", "scheme_not_allowed", "original url scheme must be http or https"))
+
+	_, err := handler.CreateShortURL(ctx, request)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, "original url scheme must be http or https", status.Convert(err).Message())
+
+	mockService.AssertExpectations(t)
+}
+
+func TestCreateShortURLDedupe(t *testing.T) {
+	mockService := new(MockShortenerService)
+	handler := NewShortenerHandler(config.Config{BaseReturnURL: "http://short.url"}, mockService)
+	ctx := context.WithValue(context.Background(), models.UserID, 1)
+	request := &CreateShortURLRequest{URL: "http://example.com", Dedupe: true}
+
+	mockService.On("CreateShortURL", ctx, models.UserInfo{UserID: 1}, models.Request{URL: "http://example.com", Dedupe: true}).
+		Return("", customerrors.NewCustomErrorOriginalURLConflict("abc123"))
+
+	response, err := handler.CreateShortURL(ctx, request)
+	assert.NoError(t, err)
+	assert.Equal(t, "http://short.url/abc123", response.URL)
+	assert.Equal(t, customerrors.ErrOriginalURLAlreadyExists.Error(), response.Error)
+
+	mockService.AssertExpectations(t)
+}
+
 func TestCreateBatchShortURL(t *testing.T) {
 	mockService := new(MockShortenerService)
 	handler := NewShortenerHandler(config.Config{BaseReturnURL: "http://short.url"}, mockService)
@@ -55,15 +91,18 @@ func TestCreateBatchShortURL(t *testing.T) {
 		{OriginalURL: "http://example1.com", CorrelationID: "1"},
 		{OriginalURL: "http://example2.com", CorrelationID: "2"},
 	}).Return([]models.ShortURLInfoBatch{
-		{ShortURL: "short1", CorrelationID: "1"},
-		{ShortURL: "short2", CorrelationID: "2"},
+		{ShortURL: "http://short.url/short1", CorrelationID: "1", Status: models.BatchItemCreated},
+		{CorrelationID: "2", Status: models.BatchItemInvalid, Error: "ttl must be positive"},
 	}, nil)
 
 	response, err := handler.CreateBatchShortURL(ctx, request)
 	assert.NoError(t, err)
 	assert.Len(t, response.URLS, 2)
 	assert.Equal(t, "http://short.url/short1", response.URLS[0].ShortUrl)
-	assert.Equal(t, "http://short.url/short2", response.URLS[1].ShortUrl)
+	assert.Equal(t, models.BatchItemCreated, response.URLS[0].Status)
+	assert.Empty(t, response.URLS[1].ShortUrl)
+	assert.Equal(t, models.BatchItemInvalid, response.URLS[1].Status)
+	assert.Equal(t, "ttl must be positive", response.URLS[1].Error)
 
 	mockService.AssertExpectations(t)
 }
@@ -102,11 +141,14 @@ func TestGetUrlsByUser(t *testing.T) {
 	mockService := new(MockShortenerService)
 	handler := NewShortenerHandler(config.Config{BaseReturnURL: "http://short.url"}, mockService)
 	ctx := context.WithValue(context.Background(), models.UserID, 1)
-	request := &GetUrlsByUserRequest{}
+	request := &GetUrlsByUserRequest{Limit: 2, SortBy: models.SortByShortURL}
 
-	mockService.On("GetUrlsByUser", ctx, models.UserInfo{UserID: 1}).Return([]models.URLByUser{
-		{ShortURL: "short1", OriginalURL: "http://example1.com"},
-		{ShortURL: "short2", OriginalURL: "http://example2.com"},
+	mockService.On("GetUrlsByUser", ctx, models.UserInfo{UserID: 1}, models.URLQuery{Limit: 2, SortBy: models.SortByShortURL}).Return(models.URLPage{
+		URLs: []models.URLByUser{
+			{ShortURL: "short1", OriginalURL: "http://example1.com"},
+			{ShortURL: "short2", OriginalURL: "http://example2.com"},
+		},
+		NextCursor: "next",
 	}, nil)
 
 	response, err := handler.GetUrlsByUser(ctx, request)
@@ -114,6 +156,7 @@ func TestGetUrlsByUser(t *testing.T) {
 	assert.Len(t, response.URLS, 2)
 	assert.Equal(t, "http://short.url/short1", response.URLS[0].ShortUrl)
 	assert.Equal(t, "http://short.url/short2", response.URLS[1].ShortUrl)
+	assert.Equal(t, "next", response.NextCursor)
 
 	mockService.AssertExpectations(t)
 }
@@ -129,10 +172,64 @@ func TestDeleteUrlsByUser(t *testing.T) {
 		},
 	}
 
-	mockService.On("DeleteUrlsByUser", ctx, models.UserInfo{UserID: 1}, []string{"short1", "short2"}).Return()
+	mockService.On("DeleteUrlsByUser", ctx, models.UserInfo{UserID: 1}, []string{"short1", "short2"}).
+		Return(models.DeletionJob{ID: "job1", Status: models.DeletionJobPending}, nil)
+
+	response, err := handler.DeleteUrlsByUser(ctx, request)
+	assert.NoError(t, err)
+	assert.Equal(t, "job1", response.JobID)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestRestoreUrlsByUser(t *testing.T) {
+	mockService := new(MockShortenerService)
+	handler := NewShortenerHandler(config.Config{}, mockService)
+	ctx := context.WithValue(context.Background(), models.UserID, 1)
+	request := &RestoreUrlsByUserRequest{
+		URLS: []*RestoreUrlsByUserRequestItem{
+			{ShortURL: "short1"},
+			{ShortURL: "short2"},
+		},
+	}
+
+	mockService.On("RestoreUrlsByUser", ctx, models.UserInfo{UserID: 1}, []string{"short1", "short2"}).Return([]models.RestoreResult{
+		{ShortURL: "short1", Status: models.RestoreURLRestored},
+		{ShortURL: "short2", Status: models.RestoreURLNotRestored},
+	}, nil)
 
-	_, err := handler.DeleteUrlsByUser(ctx, request)
+	response, err := handler.RestoreUrlsByUser(ctx, request)
 	assert.NoError(t, err)
+	assert.Len(t, response.URLS, 2)
+	assert.Equal(t, models.RestoreURLRestored, response.URLS[0].Status)
+	assert.Equal(t, models.RestoreURLNotRestored, response.URLS[1].Status)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestGetDeletionJob(t *testing.T) {
+	mockService := new(MockShortenerService)
+	handler := NewShortenerHandler(config.Config{}, mockService)
+	ctx := context.WithValue(context.Background(), models.UserID, 1)
+	request := &GetDeletionJobRequest{ID: "job1"}
+
+	mockService.On("GetDeletionJob", ctx, models.UserInfo{UserID: 1}, "job1").Return(models.DeletionJob{
+		ID:       "job1",
+		Status:   models.DeletionJobDone,
+		Attempts: 1,
+		URLs: []models.DeletionJobURL{
+			{ShortURL: "short1", Status: models.DeletionURLDeleted},
+			{ShortURL: "short2", Status: models.DeletionURLNotFound},
+		},
+	}, nil)
+
+	response, err := handler.GetDeletionJob(ctx, request)
+	assert.NoError(t, err)
+	assert.Equal(t, "job1", response.ID)
+	assert.Equal(t, models.DeletionJobDone, response.Status)
+	assert.Equal(t, int32(1), response.Attempts)
+	assert.Len(t, response.URLS, 2)
+	assert.Equal(t, models.DeletionURLNotFound, response.URLS[1].Status)
 
 	mockService.AssertExpectations(t)
 }
@@ -152,3 +249,29 @@ func TestGetStats(t *testing.T) {
 
 	mockService.AssertExpectations(t)
 }
+
+func TestGetURLStats(t *testing.T) {
+	mockService := new(MockShortenerService)
+	handler := NewShortenerHandler(config.Config{}, mockService)
+	ctx := context.WithValue(context.Background(), models.UserID, 1)
+	request := &GetURLStatsRequest{ShortURL: "short1"}
+
+	mockService.On("GetURLStats", ctx, models.UserInfo{UserID: 1}, "short1").Return(models.LinkStats{
+		ShortURL:       "short1",
+		TotalClicks:    3,
+		UniqueVisitors: 2,
+		ClicksPerDay:   []models.ClicksPerDay{{Date: "2024-05-01", Clicks: 3}},
+		TopReferrers:   []models.CountItem{{Value: "https://ya.ru", Count: 2}},
+		TopUserAgents:  []models.CountItem{{Value: "curl", Count: 3}},
+	}, nil)
+
+	response, err := handler.GetURLStats(ctx, request)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), response.TotalClicks)
+	assert.Equal(t, int32(2), response.UniqueVisitors)
+	assert.Equal(t, "2024-05-01", response.ClicksPerDay[0].Date)
+	assert.Equal(t, "https://ya.ru", response.TopReferrers[0].Value)
+	assert.Equal(t, int32(3), response.TopUserAgents[0].Count)
+
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,78 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/health"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// healthWatchPeriod период проверки готовности для вызова Watch.
+const healthWatchPeriod = 5 * time.Second
+
+// healthServer реализует стандартный протокол проверки состояния gRPC grpc.health.v1.Health.
+// Состояние сервиса "" и ShortenerService определяется проверкой готовности: SERVING, если сервис готов,
+// и NOT_SERVING, если нет или сервис останавливается.
+type healthServer struct {
+	healthpb.UnimplementedHealthServer
+	checker *health.Checker
+}
+
+// NewHealthServer создает сервер протокола проверки состояния gRPC по проверке готовности checker.
+func NewHealthServer(checker *health.Checker) healthpb.HealthServer {
+	return &healthServer{checker: checker}
+}
+
+// Check возвращает текущее состояние сервиса.
+func (s *healthServer) Check(ctx context.Context, in *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	if !isHealthService(in.GetService()) {
+		return nil, status.Error(codes.NotFound, "unknown service")
+	}
+	return &healthpb.HealthCheckResponse{Status: s.status(ctx)}, nil
+}
+
+// Watch отправляет состояние сервиса сразу и затем при каждом его изменении.
+func (s *healthServer) Watch(in *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	ctx := stream.Context()
+	if !isHealthService(in.GetService()) {
+		return stream.Send(&healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVICE_UNKNOWN})
+	}
+	ticker := time.NewTicker(healthWatchPeriod)
+	defer ticker.Stop()
+	last := healthpb.HealthCheckResponse_UNKNOWN
+	for {
+		if current := s.status(ctx); current != last {
+			if err := stream.Send(&healthpb.HealthCheckResponse{Status: current}); err != nil {
+				return err
+			}
+			last = current
+		}
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *healthServer) status(ctx context.Context) healthpb.HealthCheckResponse_ServingStatus {
+	if s.checker.Ready(ctx).Ready() {
+		return healthpb.HealthCheckResponse_SERVING
+	}
+	return healthpb.HealthCheckResponse_NOT_SERVING
+}
+
+// isHealthService проверяет, что состояние сервиса service известно: "" означает сервер целиком.
+func isHealthService(service string) bool {
+	return service == "" || service == ShortenerService_ServiceDesc.ServiceName
+}
+
+// isHealthMethod проверяет, что method является методом протокола проверки состояния.
+// Такие вызовы не аутентифицируются, чтобы проверки не регистрировали пользователей.
+func isHealthMethod(method string) bool {
+	return strings.HasPrefix(method, "/"+healthpb.Health_ServiceDesc.ServiceName+"/")
+}
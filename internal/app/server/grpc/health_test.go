@@ -0,0 +1,73 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/config"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/health"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestHealthServer(t *testing.T) {
+	tokens := newTestTokens(t)
+	// вызовы проверки состояния не аутентифицируются, поэтому сервис не должен регистрировать пользователей
+	mockService := new(MockShortenerService)
+	var healthy atomic.Bool
+	healthy.Store(true)
+	checker := health.NewChecker(time.Second, health.Component{Name: "storage", Check: func(context.Context) error {
+		if !healthy.Load() {
+			return errors.New("storage is unavailable")
+		}
+		return nil
+	}})
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := NewServer(config.GetDefault(), mockService, tokens, nil, nil, checker)
+	go server.Serve(listener)
+	defer server.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+	client := healthpb.NewHealthClient(conn)
+	ctx := context.Background()
+
+	for _, service := range []string{"", ShortenerService_ServiceDesc.ServiceName} {
+		response, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+		require.NoError(t, err)
+		assert.Equal(t, healthpb.HealthCheckResponse_SERVING, response.Status, service)
+	}
+	_, err = client.Check(ctx, &healthpb.HealthCheckRequest{Service: "unknown"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	healthy.Store(false)
+	response, err := client.Check(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, response.Status)
+
+	healthy.Store(true)
+	checker.SetShuttingDown()
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := client.Watch(watchCtx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	response, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, response.Status, "shutting down server must not serve")
+	mockService.AssertNotCalled(t, "CreateUser")
+}
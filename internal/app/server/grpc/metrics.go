@@ -0,0 +1,40 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/metrics"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// MetricsInterceptor считает вызовы gRPC по методу и коду статуса и измеряет их длительность.
+type MetricsInterceptor struct{}
+
+// NewMetricsInterceptor создает новый экземпляр MetricsInterceptor.
+func NewMetricsInterceptor() *MetricsInterceptor {
+	return &MetricsInterceptor{}
+}
+
+// Unary собирает метрики унарных вызовов.
+func (i *MetricsInterceptor) Unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	observe(info.FullMethod, start, err)
+	return resp, err
+}
+
+// Stream собирает метрики потоковых вызовов.
+func (i *MetricsInterceptor) Stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, ss)
+	observe(info.FullMethod, start, err)
+	return err
+}
+
+// observe записывает вызов метода method, начатый в start и завершенный с ошибкой err.
+func observe(method string, start time.Time, err error) {
+	metrics.GRPCRequests.With(method, status.Code(err).String()).Inc()
+	metrics.GRPCRequestDuration.With(method).Observe(time.Since(start).Seconds())
+}
@@ -11,8 +11,8 @@ type MockShortenerService struct {
 	mock.Mock
 }
 
-func (m *MockShortenerService) CreateShortURL(ctx context.Context, userInfo models.UserInfo, originalURL string) (string, error) {
-	args := m.Called(ctx, userInfo, originalURL)
+func (m *MockShortenerService) CreateShortURL(ctx context.Context, userInfo models.UserInfo, request models.Request) (string, error) {
+	args := m.Called(ctx, userInfo, request)
 	return args.String(0), args.Error(1)
 }
 
@@ -26,21 +26,72 @@ func (m *MockShortenerService) GetByShortURL(ctx context.Context, shortURL strin
 	return args.String(0), args.Error(1)
 }
 
+func (m *MockShortenerService) CreateUser(ctx context.Context) (models.User, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(models.User), args.Error(1)
+}
+
 func (m *MockShortenerService) PingStorage(ctx context.Context) bool {
 	args := m.Called(ctx)
 	return args.Bool(0)
 }
 
-func (m *MockShortenerService) GetUrlsByUser(ctx context.Context, userInfo models.UserInfo) ([]models.URLByUser, error) {
-	args := m.Called(ctx, userInfo)
-	return args.Get(0).([]models.URLByUser), args.Error(1)
+func (m *MockShortenerService) GetUrlsByUser(ctx context.Context, userInfo models.UserInfo, query models.URLQuery) (models.URLPage, error) {
+	args := m.Called(ctx, userInfo, query)
+	return args.Get(0).(models.URLPage), args.Error(1)
 }
 
-func (m *MockShortenerService) DeleteUrlsByUser(ctx context.Context, userInfo models.UserInfo, urls []string) {
-	m.Called(ctx, userInfo, urls)
+func (m *MockShortenerService) DeleteUrlsByUser(ctx context.Context, userInfo models.UserInfo, urls []string) (models.DeletionJob, error) {
+	args := m.Called(ctx, userInfo, urls)
+	return args.Get(0).(models.DeletionJob), args.Error(1)
+}
+
+func (m *MockShortenerService) RestoreUrlsByUser(ctx context.Context, userInfo models.UserInfo, urls []string) ([]models.RestoreResult, error) {
+	args := m.Called(ctx, userInfo, urls)
+	return args.Get(0).([]models.RestoreResult), args.Error(1)
+}
+
+func (m *MockShortenerService) GetDeletionJob(ctx context.Context, userInfo models.UserInfo, id string) (models.DeletionJob, error) {
+	args := m.Called(ctx, userInfo, id)
+	return args.Get(0).(models.DeletionJob), args.Error(1)
 }
 
 func (m *MockShortenerService) GetStats(ctx context.Context) (models.Stats, error) {
 	args := m.Called(ctx)
 	return args.Get(0).(models.Stats), args.Error(1)
 }
+
+func (m *MockShortenerService) GetURLStats(ctx context.Context, userInfo models.UserInfo, shortURL string) (models.LinkStats, error) {
+	args := m.Called(ctx, userInfo, shortURL)
+	return args.Get(0).(models.LinkStats), args.Error(1)
+}
+
+func (m *MockShortenerService) AdminGetURL(ctx context.Context, shortURL string) (models.AdminURL, error) {
+	args := m.Called(ctx, shortURL)
+	return args.Get(0).(models.AdminURL), args.Error(1)
+}
+
+func (m *MockShortenerService) AdminSetURLDisabled(ctx context.Context, shortURL string, disabled bool) (models.AdminURL, error) {
+	args := m.Called(ctx, shortURL, disabled)
+	return args.Get(0).(models.AdminURL), args.Error(1)
+}
+
+func (m *MockShortenerService) AdminSetURLOwner(ctx context.Context, shortURL string, userID int) (models.AdminURL, error) {
+	args := m.Called(ctx, shortURL, userID)
+	return args.Get(0).(models.AdminURL), args.Error(1)
+}
+
+func (m *MockShortenerService) AdminGetUrlsByUser(ctx context.Context, userID int, query models.URLQuery) (models.AdminURLPage, error) {
+	args := m.Called(ctx, userID, query)
+	return args.Get(0).(models.AdminURLPage), args.Error(1)
+}
+
+func (m *MockShortenerService) AdminDeleteUrlsOfUser(ctx context.Context, userID int) (models.AdminDeleteResult, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(models.AdminDeleteResult), args.Error(1)
+}
+
+func (m *MockShortenerService) AdminDeleteUrlsOfDomain(ctx context.Context, domain string) (models.AdminDeleteResult, error) {
+	args := m.Called(ctx, domain)
+	return args.Get(0).(models.AdminDeleteResult), args.Error(1)
+}
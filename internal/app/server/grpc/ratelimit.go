@@ -0,0 +1,118 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/clientip"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/ratelimit"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/token"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// realIPKey ключ метаданных с IP-адресом клиента, который передает прокси.
+const realIPKey = "x-real-ip"
+
+// rateLimitGroups сопоставляет методы ShortenerService группам лимитов. Остальные методы не ограничиваются.
+var rateLimitGroups = map[string]string{
+	ShortenerService_CreateShortURL_FullMethodName:      ratelimit.GroupCreate,
+	ShortenerService_CreateBatchShortURL_FullMethodName: ratelimit.GroupCreate,
+	ShortenerService_GetByShortURL_FullMethodName:       ratelimit.GroupExpand,
+	ShortenerService_GetUrlsByUser_FullMethodName:       ratelimit.GroupUser,
+	ShortenerService_DeleteUrlsByUser_FullMethodName:    ratelimit.GroupUser,
+	ShortenerService_RestoreUrlsByUser_FullMethodName:   ratelimit.GroupUser,
+	ShortenerService_GetDeletionJob_FullMethodName:      ratelimit.GroupUser,
+	ShortenerService_GetURLStats_FullMethodName:         ratelimit.GroupUser,
+}
+
+// RateLimitInterceptor ограничивает частоту вызовов gRPC клиента теми же лимитами, что и HTTP-сервер.
+// Клиент определяется по пользователю из JWT в метаданных authorization, а без действительного токена —
+// по адресу соединения или, если вызов пришел от доверенного прокси, по IP-адресу из метаданных x-real-ip. Состояние лимита возвращается в метаданных
+// заголовка ответа ratelimit-*, отклоненный вызов завершается с кодом ResourceExhausted и метаданными retry-after.
+type RateLimitInterceptor struct {
+	limiters *ratelimit.Limiters
+	tokens   *token.Manager
+	clients  *clientip.Resolver
+}
+
+// NewRateLimitInterceptor создает новый экземпляр RateLimitInterceptor. Если limiters равен nil, вызовы не ограничиваются.
+func NewRateLimitInterceptor(limiters *ratelimit.Limiters, tokens *token.Manager, clients *clientip.Resolver) *RateLimitInterceptor {
+	return &RateLimitInterceptor{
+		limiters: limiters,
+		tokens:   tokens,
+		clients:  clients,
+	}
+}
+
+// Unary ограничивает частоту унарных вызовов.
+func (i *RateLimitInterceptor) Unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	header, err := i.allow(ctx, info.FullMethod)
+	if header != nil {
+		if err := grpc.SetHeader(ctx, header); err != nil {
+			return nil, err
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// Stream ограничивает частоту потоковых вызовов.
+func (i *RateLimitInterceptor) Stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	header, err := i.allow(ss.Context(), info.FullMethod)
+	if header != nil {
+		if err := ss.SetHeader(header); err != nil {
+			return err
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
+
+// allow проверяет лимит группы метода method и возвращает метаданные заголовка ответа с состоянием лимита.
+func (i *RateLimitInterceptor) allow(ctx context.Context, method string) (metadata.MD, error) {
+	group, ok := rateLimitGroups[method]
+	if !ok {
+		return nil, nil
+	}
+	result, limited := i.limiters.Allow(group, i.clientKey(ctx))
+	if !limited {
+		return nil, nil
+	}
+	header := metadata.MD{}
+	for name, value := range result.Headers() {
+		header.Set(strings.ToLower(name), value)
+	}
+	if !result.Allowed {
+		return header, status.Error(codes.ResourceExhausted, fmt.Sprintf("rate limit exceeded, retry after %ss", header.Get("retry-after")[0]))
+	}
+	return header, nil
+}
+
+// clientKey возвращает ключ клиента: пользователь из действительного JWT или IP-адрес клиента.
+func (i *RateLimitInterceptor) clientKey(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(authorizationKey); len(values) > 0 && i.tokens != nil {
+		if value, ok := strings.CutPrefix(values[0], bearerPrefix); ok {
+			if claims, err := i.tokens.Parse(value); err == nil {
+				return ratelimit.UserKey(claims.UserID)
+			}
+		}
+	}
+	var remoteAddr, realIP string
+	if p, ok := peer.FromContext(ctx); ok {
+		remoteAddr = p.Addr.String()
+	}
+	if values := md.Get(realIPKey); len(values) > 0 {
+		realIP = values[0]
+	}
+	return ratelimit.IPKey(i.clients.ClientIP(remoteAddr, realIP))
+}
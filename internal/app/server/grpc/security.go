@@ -0,0 +1,154 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/logger"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/models"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/token"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// authorizationKey ключ метаданных, в котором передается JWT.
+	authorizationKey = "authorization"
+	// bearerPrefix префикс значения authorization.
+	bearerPrefix = "Bearer "
+)
+
+// registeringMethods методы, которым нужен владелец: анонимному клиенту при их вызове регистрируется новый пользователь.
+var registeringMethods = map[string]struct{}{
+	ShortenerService_CreateShortURL_FullMethodName:      {},
+	ShortenerService_CreateBatchShortURL_FullMethodName: {},
+}
+
+// anonymousMethods методы, которые анонимный клиент вызывает без регистрации пользователя.
+var anonymousMethods = map[string]struct{}{
+	ShortenerService_GetByShortURL_FullMethodName: {},
+	ShortenerService_PingStorage_FullMethodName:   {},
+}
+
+// UserIDService определяет методы сервиса, необходимые для выдачи идентификаторов новым пользователям.
+type UserIDService interface {
+	// CreateUser регистрирует нового пользователя.
+	CreateUser(ctx context.Context) (models.User, error)
+}
+
+// SecurityInterceptor аутентифицирует вызовы gRPC с помощью JWT, который передается в метаданных
+// authorization в виде "Bearer <token>". Это тот же токен, который HTTP-сервер выдает в cookie.
+// Анонимному клиенту новый пользователь регистрируется только при создании сокращенных URL, а токен возвращается
+// в метаданных заголовка ответа. Переходы и проверка хранилища вызываются анонимно,
+// остальные методы требуют токен.
+// Вызовы протокола проверки состояния и административного API не аутентифицируются.
+type SecurityInterceptor struct {
+	tokens  *token.Manager
+	service UserIDService
+}
+
+// NewSecurityInterceptor создает новый экземпляр SecurityInterceptor.
+func NewSecurityInterceptor(tokens *token.Manager, service UserIDService) *SecurityInterceptor {
+	return &SecurityInterceptor{
+		tokens:  tokens,
+		service: service,
+	}
+}
+
+// Unary аутентифицирует унарные вызовы.
+func (i *SecurityInterceptor) Unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if isHealthMethod(info.FullMethod) || isAdminMethod(info.FullMethod) {
+		return handler(ctx, req)
+	}
+	userID, header, err := i.authenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	if header != nil {
+		if err := grpc.SetHeader(ctx, header); err != nil {
+			return nil, err
+		}
+	}
+	if userID == 0 {
+		return handler(ctx, req)
+	}
+	return handler(context.WithValue(ctx, models.UserID, userID), req)
+}
+
+// Stream аутентифицирует потоковые вызовы.
+func (i *SecurityInterceptor) Stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if isHealthMethod(info.FullMethod) || isAdminMethod(info.FullMethod) {
+		return handler(srv, ss)
+	}
+	userID, header, err := i.authenticate(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	if header != nil {
+		if err := ss.SetHeader(header); err != nil {
+			return err
+		}
+	}
+	if userID == 0 {
+		return handler(srv, ss)
+	}
+	return handler(srv, &authenticatedStream{
+		ServerStream: ss,
+		ctx:          context.WithValue(ss.Context(), models.UserID, userID),
+	})
+}
+
+// authenticate возвращает идентификатор пользователя из токена и метаданные заголовка ответа с новым токеном,
+// если токен нужно выдать или перевыпустить. Для анонимного вызова метода из anonymousMethods возвращается 0.
+func (i *SecurityInterceptor) authenticate(ctx context.Context, method string) (int, metadata.MD, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(authorizationKey)
+	if len(values) == 0 {
+		if _, ok := anonymousMethods[method]; ok {
+			return 0, nil, nil
+		}
+		if _, ok := registeringMethods[method]; !ok {
+			return 0, nil, status.Error(codes.Unauthenticated, "authorization is required")
+		}
+		user, err := i.service.CreateUser(ctx)
+		if err != nil {
+			logger.Logger.Error("failed to create user", "error", err.Error())
+			return 0, nil, status.Error(codes.Internal, "failed to create user")
+		}
+		return i.issue(user.ID)
+	}
+	value, ok := strings.CutPrefix(values[0], bearerPrefix)
+	if !ok {
+		return 0, nil, status.Error(codes.Unauthenticated, "authorization must be a bearer token")
+	}
+	claims, err := i.tokens.Parse(value)
+	if err != nil {
+		logger.Logger.Info("invalid jwt", "error", err.Error())
+		return 0, nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+	if i.tokens.ShouldRefresh(claims) {
+		return i.issue(claims.UserID)
+	}
+	return claims.UserID, nil, nil
+}
+
+func (i *SecurityInterceptor) issue(userID int) (int, metadata.MD, error) {
+	value, _, err := i.tokens.Build(userID)
+	if err != nil {
+		return 0, nil, status.Error(codes.Internal, err.Error())
+	}
+	return userID, metadata.Pairs(authorizationKey, bearerPrefix+value), nil
+}
+
+// authenticatedStream подменяет контекст потока контекстом с идентификатором пользователя.
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context возвращает контекст потока с идентификатором пользователя.
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
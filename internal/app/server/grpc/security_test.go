@@ -0,0 +1,183 @@
+package grpc
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"testing"
+
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/config"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/logger"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/models"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/ratelimit"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/token"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newTestTokens(t *testing.T) *token.Manager {
+	err := logger.Init(slog.LevelInfo)
+	require.NoError(t, err)
+	config := config.GetDefault()
+	config.JWTKeys = "k1:secret"
+	tokens, err := token.NewManager(config)
+	require.NoError(t, err)
+	return tokens
+}
+
+func TestSecurityInterceptorUnary(t *testing.T) {
+	tokens := newTestTokens(t)
+	mockService := new(MockShortenerService)
+	mockService.On("CreateUser", mock.Anything).Return(models.User{ID: 7}, nil)
+	mockService.On("GetStats", mock.Anything).Return(models.Stats{URLS: 1, Users: 1}, nil)
+	mockService.On("PingStorage", mock.Anything).Return(true)
+	mockService.On("CreateShortURL", mock.Anything, models.UserInfo{UserID: 7}, mock.Anything).Return("abc", nil)
+
+	security := NewSecurityInterceptor(tokens, mockService)
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(grpc.UnaryInterceptor(security.Unary))
+	RegisterShortenerServiceServer(server, NewShortenerHandler(config.GetDefault(), mockService))
+	go server.Serve(listener)
+	defer server.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+	client := NewShortenerServiceClient(conn)
+
+	// Анонимный вызов, которому не нужен владелец, не регистрирует пользователя
+	var header metadata.MD
+	_, err = client.PingStorage(context.Background(), &PingStorageRequest{}, grpc.Header(&header))
+	require.NoError(t, err)
+	assert.Empty(t, header.Get(authorizationKey))
+	mockService.AssertNotCalled(t, "CreateUser", mock.Anything)
+
+	// Методы пользователя и общая статистика требуют токен
+	_, err = client.GetUrlsByUser(context.Background(), &GetUrlsByUserRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = client.GetStats(context.Background(), &GetStatsRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// Анонимному клиенту при создании сокращенного URL выдается токен
+	_, err = client.CreateShortURL(context.Background(), &CreateShortURLRequest{URL: "https://example.com"}, grpc.Header(&header))
+	require.NoError(t, err)
+	require.Len(t, header.Get(authorizationKey), 1)
+	claims, err := tokens.Parse(header.Get(authorizationKey)[0][len(bearerPrefix):])
+	require.NoError(t, err)
+	assert.Equal(t, 7, claims.UserID)
+
+	// Клиент с действующим токеном не получает новый
+	header = nil
+	value, _, err := tokens.Build(7)
+	require.NoError(t, err)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), authorizationKey, bearerPrefix+value)
+	_, err = client.GetStats(ctx, &GetStatsRequest{}, grpc.Header(&header))
+	require.NoError(t, err)
+	assert.Empty(t, header.Get(authorizationKey))
+
+	// Недействительный токен и токен без префикса Bearer отклоняются
+	for _, value := range []string{bearerPrefix + "abc", value} {
+		ctx := metadata.AppendToOutgoingContext(context.Background(), authorizationKey, value)
+		_, err = client.GetStats(ctx, &GetStatsRequest{})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	}
+}
+
+type testServerStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	header metadata.MD
+}
+
+func (s *testServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *testServerStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func TestSecurityInterceptorStream(t *testing.T) {
+	tokens := newTestTokens(t)
+	mockService := new(MockShortenerService)
+	mockService.On("CreateUser", mock.Anything).Return(models.User{ID: 7}, nil)
+	security := NewSecurityInterceptor(tokens, mockService)
+
+	var userID int
+	handler := func(_ interface{}, ss grpc.ServerStream) error {
+		userID, _ = ss.Context().Value(models.UserID).(int)
+		return nil
+	}
+
+	info := &grpc.StreamServerInfo{FullMethod: ShortenerService_CreateShortURL_FullMethodName}
+	stream := &testServerStream{ctx: context.Background()}
+	err := security.Stream(nil, stream, info, handler)
+	require.NoError(t, err)
+	assert.Equal(t, 7, userID)
+	assert.Len(t, stream.header.Get(authorizationKey), 1)
+
+	stream = &testServerStream{ctx: context.Background()}
+	err = security.Stream(nil, stream, &grpc.StreamServerInfo{FullMethod: ShortenerService_GetUrlsByUser_FullMethodName}, handler)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	value, _, err := tokens.Build(3)
+	require.NoError(t, err)
+	stream = &testServerStream{ctx: metadata.NewIncomingContext(context.Background(), metadata.Pairs(authorizationKey, bearerPrefix+value))}
+	err = security.Stream(nil, stream, info, handler)
+	require.NoError(t, err)
+	assert.Equal(t, 3, userID)
+	assert.Empty(t, stream.header)
+}
+
+func TestRateLimitInterceptorUnary(t *testing.T) {
+	tokens := newTestTokens(t)
+	mockService := new(MockShortenerService)
+	mockService.On("CreateUser", mock.Anything).Return(models.User{ID: 7}, nil)
+	mockService.On("GetByShortURL", mock.Anything, "abc").Return("https://example.com/", nil)
+	mockService.On("PingStorage", mock.Anything).Return(true)
+	config := config.GetDefault()
+	config.RateLimitExpand = "1/1m"
+	limiters, err := ratelimit.New(config)
+	require.NoError(t, err)
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := NewServer(config, mockService, tokens, limiters, nil, nil)
+	go server.Serve(listener)
+	defer server.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+	client := NewShortenerServiceClient(conn)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), realIPKey, "10.0.0.1")
+
+	var header metadata.MD
+	_, err = client.GetByShortURL(ctx, &GetByShortURLRequest{ShortURL: "abc"}, grpc.Header(&header))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1"}, header.Get("ratelimit-limit"))
+	assert.Equal(t, []string{"0"}, header.Get("ratelimit-remaining"))
+
+	var trailer metadata.MD
+	header = nil
+	_, err = client.GetByShortURL(ctx, &GetByShortURLRequest{ShortURL: "abc"}, grpc.Header(&header), grpc.Trailer(&trailer))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Equal(t, []string{"60"}, metadata.Join(header, trailer).Get("retry-after"))
+
+	// Методы вне групп лимитов не ограничиваются
+	_, err = client.PingStorage(ctx, &PingStorageRequest{})
+	require.NoError(t, err)
+}
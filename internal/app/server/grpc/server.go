@@ -1,37 +1,41 @@
 package grpc
 
 import (
-	"context"
-	"log"
-	"net"
-
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/clientip"
 	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/config"
-	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/service"
-	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/storage"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/health"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/ratelimit"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/token"
 	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
-func StartServer(ctx context.Context, config config.Config) error {
-	listen, err := net.Listen("tcp", config.ServerURL)
-	if err != nil {
-		log.Fatalf("Failed to listen: %v", err)
-	}
-
-	s := grpc.NewServer()
-	storage, err := storage.NewShortenerStorage(storage.GetStorageTypeByConfig(config), config)
-	if err != nil {
-		return err
-	}
-	service, err := service.NewShortenerService(ctx, config, storage)
-	if err != nil {
-		return err
-	}
-	handler := NewShortenerHandler(config, service)
-	RegisterShortenerServiceServer(s, handler)
+// Service объединяет методы сервиса, необходимые gRPC серверу.
+type Service interface {
+	ShortenerService
+	AdminService
+	UserIDService
+}
 
-	log.Println("Starting gRPC server on port :50051")
-	if err := s.Serve(listen); err != nil {
-		log.Fatalf("Failed to serve: %v", err)
+// NewServer создает gRPC сервер с зарегистрированными ShortenerService и AdminService и интерсепторами метрик, трассировки,
+// ограничения частоты вызовов, доступа к административному API и аутентификации. Если limiters равен nil, частота вызовов не ограничивается.
+// IP-адрес клиента для лимитов определяется clients с учетом доверенных прокси.
+// Если checker не равен nil, регистрируется стандартный сервис проверки состояния grpc.health.v1.Health.
+// Запуск и остановка сервера выполняются вызывающей стороной.
+func NewServer(config config.Config, service Service, tokens *token.Manager, limiters *ratelimit.Limiters, clients *clientip.Resolver, checker *health.Checker) *grpc.Server {
+	metrics := NewMetricsInterceptor()
+	tracing := NewTracingInterceptor()
+	rateLimit := NewRateLimitInterceptor(limiters, tokens, clients)
+	admin := NewAdminInterceptor(config)
+	security := NewSecurityInterceptor(tokens, service)
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(metrics.Unary, tracing.Unary, rateLimit.Unary, admin.Unary, security.Unary),
+		grpc.ChainStreamInterceptor(metrics.Stream, tracing.Stream, rateLimit.Stream, admin.Stream, security.Stream),
+	)
+	RegisterShortenerServiceServer(s, NewShortenerHandler(config, service))
+	RegisterAdminServiceServer(s, NewAdminHandler(service))
+	if checker != nil {
+		healthpb.RegisterHealthServer(s, NewHealthServer(checker))
 	}
-	return nil
+	return s
 }
@@ -25,7 +25,10 @@ const (
 	ShortenerService_PingStorage_FullMethodName         = "/url_shortener.ShortenerService/PingStorage"
 	ShortenerService_GetUrlsByUser_FullMethodName       = "/url_shortener.ShortenerService/GetUrlsByUser"
 	ShortenerService_DeleteUrlsByUser_FullMethodName    = "/url_shortener.ShortenerService/DeleteUrlsByUser"
+	ShortenerService_RestoreUrlsByUser_FullMethodName   = "/url_shortener.ShortenerService/RestoreUrlsByUser"
+	ShortenerService_GetDeletionJob_FullMethodName      = "/url_shortener.ShortenerService/GetDeletionJob"
 	ShortenerService_GetStats_FullMethodName            = "/url_shortener.ShortenerService/GetStats"
+	ShortenerService_GetURLStats_FullMethodName         = "/url_shortener.ShortenerService/GetURLStats"
 )
 
 // ShortenerServiceClient is the client API for ShortenerService service.
@@ -42,10 +45,16 @@ type ShortenerServiceClient interface {
 	PingStorage(ctx context.Context, in *PingStorageRequest, opts ...grpc.CallOption) (*PingStorageResponse, error)
 	// GetUrlsByUser возвращает список URL, созданных пользователем.
 	GetUrlsByUser(ctx context.Context, in *GetUrlsByUserRequest, opts ...grpc.CallOption) (*GetUrlsByUserResponse, error)
-	// DeleteUrlsByUser удаляет список URL, созданных пользователем.
+	// DeleteUrlsByUser создает задание на удаление списка URL, созданных пользователем.
 	DeleteUrlsByUser(ctx context.Context, in *DeleteUrlsByUserRequest, opts ...grpc.CallOption) (*DeleteUrlsByUserResponse, error)
+	// RestoreUrlsByUser восстанавливает удаленные URL, созданные пользователем.
+	RestoreUrlsByUser(ctx context.Context, in *RestoreUrlsByUserRequest, opts ...grpc.CallOption) (*RestoreUrlsByUserResponse, error)
+	// GetDeletionJob возвращает состояние задания на удаление, созданного пользователем.
+	GetDeletionJob(ctx context.Context, in *GetDeletionJobRequest, opts ...grpc.CallOption) (*GetDeletionJobResponse, error)
 	// GetStats возвращающий в ответ объект статистики.
 	GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*GetStatsResponse, error)
+	// GetURLStats возвращает статистику переходов по сокращенному URL, созданному пользователем.
+	GetURLStats(ctx context.Context, in *GetURLStatsRequest, opts ...grpc.CallOption) (*GetURLStatsResponse, error)
 }
 
 type shortenerServiceClient struct {
@@ -110,6 +119,24 @@ func (c *shortenerServiceClient) DeleteUrlsByUser(ctx context.Context, in *Delet
 	return out, nil
 }
 
+func (c *shortenerServiceClient) RestoreUrlsByUser(ctx context.Context, in *RestoreUrlsByUserRequest, opts ...grpc.CallOption) (*RestoreUrlsByUserResponse, error) {
+	out := new(RestoreUrlsByUserResponse)
+	err := c.cc.Invoke(ctx, ShortenerService_RestoreUrlsByUser_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerServiceClient) GetDeletionJob(ctx context.Context, in *GetDeletionJobRequest, opts ...grpc.CallOption) (*GetDeletionJobResponse, error) {
+	out := new(GetDeletionJobResponse)
+	err := c.cc.Invoke(ctx, ShortenerService_GetDeletionJob_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *shortenerServiceClient) GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*GetStatsResponse, error) {
 	out := new(GetStatsResponse)
 	err := c.cc.Invoke(ctx, ShortenerService_GetStats_FullMethodName, in, out, opts...)
@@ -119,6 +146,15 @@ func (c *shortenerServiceClient) GetStats(ctx context.Context, in *GetStatsReque
 	return out, nil
 }
 
+func (c *shortenerServiceClient) GetURLStats(ctx context.Context, in *GetURLStatsRequest, opts ...grpc.CallOption) (*GetURLStatsResponse, error) {
+	out := new(GetURLStatsResponse)
+	err := c.cc.Invoke(ctx, ShortenerService_GetURLStats_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ShortenerServiceServer is the server API for ShortenerService service.
 // All implementations must embed UnimplementedShortenerServiceServer
 // for forward compatibility
@@ -133,10 +169,16 @@ type ShortenerServiceServer interface {
 	PingStorage(context.Context, *PingStorageRequest) (*PingStorageResponse, error)
 	// GetUrlsByUser возвращает список URL, созданных пользователем.
 	GetUrlsByUser(context.Context, *GetUrlsByUserRequest) (*GetUrlsByUserResponse, error)
-	// DeleteUrlsByUser удаляет список URL, созданных пользователем.
+	// DeleteUrlsByUser создает задание на удаление списка URL, созданных пользователем.
 	DeleteUrlsByUser(context.Context, *DeleteUrlsByUserRequest) (*DeleteUrlsByUserResponse, error)
+	// RestoreUrlsByUser восстанавливает удаленные URL, созданные пользователем.
+	RestoreUrlsByUser(context.Context, *RestoreUrlsByUserRequest) (*RestoreUrlsByUserResponse, error)
+	// GetDeletionJob возвращает состояние задания на удаление, созданного пользователем.
+	GetDeletionJob(context.Context, *GetDeletionJobRequest) (*GetDeletionJobResponse, error)
 	// GetStats возвращающий в ответ объект статистики.
 	GetStats(context.Context, *GetStatsRequest) (*GetStatsResponse, error)
+	// GetURLStats возвращает статистику переходов по сокращенному URL, созданному пользователем.
+	GetURLStats(context.Context, *GetURLStatsRequest) (*GetURLStatsResponse, error)
 	mustEmbedUnimplementedShortenerServiceServer()
 }
 
@@ -162,9 +204,18 @@ func (UnimplementedShortenerServiceServer) GetUrlsByUser(context.Context, *GetUr
 func (UnimplementedShortenerServiceServer) DeleteUrlsByUser(context.Context, *DeleteUrlsByUserRequest) (*DeleteUrlsByUserResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteUrlsByUser not implemented")
 }
+func (UnimplementedShortenerServiceServer) RestoreUrlsByUser(context.Context, *RestoreUrlsByUserRequest) (*RestoreUrlsByUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreUrlsByUser not implemented")
+}
+func (UnimplementedShortenerServiceServer) GetDeletionJob(context.Context, *GetDeletionJobRequest) (*GetDeletionJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDeletionJob not implemented")
+}
 func (UnimplementedShortenerServiceServer) GetStats(context.Context, *GetStatsRequest) (*GetStatsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetStats not implemented")
 }
+func (UnimplementedShortenerServiceServer) GetURLStats(context.Context, *GetURLStatsRequest) (*GetURLStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetURLStats not implemented")
+}
 func (UnimplementedShortenerServiceServer) mustEmbedUnimplementedShortenerServiceServer() {}
 
 // UnsafeShortenerServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -286,6 +337,42 @@ func _ShortenerService_DeleteUrlsByUser_Handler(srv interface{}, ctx context.Con
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ShortenerService_RestoreUrlsByUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreUrlsByUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServiceServer).RestoreUrlsByUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortenerService_RestoreUrlsByUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServiceServer).RestoreUrlsByUser(ctx, req.(*RestoreUrlsByUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortenerService_GetDeletionJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDeletionJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServiceServer).GetDeletionJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortenerService_GetDeletionJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServiceServer).GetDeletionJob(ctx, req.(*GetDeletionJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ShortenerService_GetStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetStatsRequest)
 	if err := dec(in); err != nil {
@@ -304,6 +391,24 @@ func _ShortenerService_GetStats_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ShortenerService_GetURLStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetURLStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServiceServer).GetURLStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortenerService_GetURLStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServiceServer).GetURLStats(ctx, req.(*GetURLStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ShortenerService_ServiceDesc is the grpc.ServiceDesc for ShortenerService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -335,10 +440,309 @@ var ShortenerService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteUrlsByUser",
 			Handler:    _ShortenerService_DeleteUrlsByUser_Handler,
 		},
+		{
+			MethodName: "RestoreUrlsByUser",
+			Handler:    _ShortenerService_RestoreUrlsByUser_Handler,
+		},
+		{
+			MethodName: "GetDeletionJob",
+			Handler:    _ShortenerService_GetDeletionJob_Handler,
+		},
 		{
 			MethodName: "GetStats",
 			Handler:    _ShortenerService_GetStats_Handler,
 		},
+		{
+			MethodName: "GetURLStats",
+			Handler:    _ShortenerService_GetURLStats_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "server.proto",
+}
+
+const (
+	AdminService_GetURL_FullMethodName             = "/url_shortener.AdminService/GetURL"
+	AdminService_SetURLDisabled_FullMethodName     = "/url_shortener.AdminService/SetURLDisabled"
+	AdminService_SetURLOwner_FullMethodName        = "/url_shortener.AdminService/SetURLOwner"
+	AdminService_GetUrlsByUser_FullMethodName      = "/url_shortener.AdminService/GetUrlsByUser"
+	AdminService_DeleteUrlsOfUser_FullMethodName   = "/url_shortener.AdminService/DeleteUrlsOfUser"
+	AdminService_DeleteUrlsOfDomain_FullMethodName = "/url_shortener.AdminService/DeleteUrlsOfDomain"
+)
+
+// AdminServiceClient is the client API for AdminService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AdminServiceClient interface {
+	// GetURL возвращает любой сокращенный URL с владельцем и служебными данными.
+	GetURL(ctx context.Context, in *AdminGetURLRequest, opts ...grpc.CallOption) (*AdminURL, error)
+	// SetURLDisabled отключает или включает сокращенный URL.
+	SetURLDisabled(ctx context.Context, in *AdminSetURLDisabledRequest, opts ...grpc.CallOption) (*AdminURL, error)
+	// SetURLOwner передает сокращенный URL другому пользователю.
+	SetURLOwner(ctx context.Context, in *AdminSetURLOwnerRequest, opts ...grpc.CallOption) (*AdminURL, error)
+	// GetUrlsByUser возвращает страницу URL пользователя user_id со служебными данными.
+	GetUrlsByUser(ctx context.Context, in *GetUrlsByUserRequest, opts ...grpc.CallOption) (*AdminGetUrlsByUserResponse, error)
+	// DeleteUrlsOfUser помечает удаленными все URL пользователя.
+	DeleteUrlsOfUser(ctx context.Context, in *AdminDeleteUrlsOfUserRequest, opts ...grpc.CallOption) (*AdminDeleteUrlsResponse, error)
+	// DeleteUrlsOfDomain помечает удаленными все URL на домен и его поддомены.
+	DeleteUrlsOfDomain(ctx context.Context, in *AdminDeleteUrlsOfDomainRequest, opts ...grpc.CallOption) (*AdminDeleteUrlsResponse, error)
+}
+
+type adminServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdminServiceClient(cc grpc.ClientConnInterface) AdminServiceClient {
+	return &adminServiceClient{cc}
+}
+
+func (c *adminServiceClient) GetURL(ctx context.Context, in *AdminGetURLRequest, opts ...grpc.CallOption) (*AdminURL, error) {
+	out := new(AdminURL)
+	err := c.cc.Invoke(ctx, AdminService_GetURL_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) SetURLDisabled(ctx context.Context, in *AdminSetURLDisabledRequest, opts ...grpc.CallOption) (*AdminURL, error) {
+	out := new(AdminURL)
+	err := c.cc.Invoke(ctx, AdminService_SetURLDisabled_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) SetURLOwner(ctx context.Context, in *AdminSetURLOwnerRequest, opts ...grpc.CallOption) (*AdminURL, error) {
+	out := new(AdminURL)
+	err := c.cc.Invoke(ctx, AdminService_SetURLOwner_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetUrlsByUser(ctx context.Context, in *GetUrlsByUserRequest, opts ...grpc.CallOption) (*AdminGetUrlsByUserResponse, error) {
+	out := new(AdminGetUrlsByUserResponse)
+	err := c.cc.Invoke(ctx, AdminService_GetUrlsByUser_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) DeleteUrlsOfUser(ctx context.Context, in *AdminDeleteUrlsOfUserRequest, opts ...grpc.CallOption) (*AdminDeleteUrlsResponse, error) {
+	out := new(AdminDeleteUrlsResponse)
+	err := c.cc.Invoke(ctx, AdminService_DeleteUrlsOfUser_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) DeleteUrlsOfDomain(ctx context.Context, in *AdminDeleteUrlsOfDomainRequest, opts ...grpc.CallOption) (*AdminDeleteUrlsResponse, error) {
+	out := new(AdminDeleteUrlsResponse)
+	err := c.cc.Invoke(ctx, AdminService_DeleteUrlsOfDomain_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminServiceServer is the server API for AdminService service.
+// All implementations must embed UnimplementedAdminServiceServer
+// for forward compatibility
+type AdminServiceServer interface {
+	// GetURL возвращает любой сокращенный URL с владельцем и служебными данными.
+	GetURL(context.Context, *AdminGetURLRequest) (*AdminURL, error)
+	// SetURLDisabled отключает или включает сокращенный URL.
+	SetURLDisabled(context.Context, *AdminSetURLDisabledRequest) (*AdminURL, error)
+	// SetURLOwner передает сокращенный URL другому пользователю.
+	SetURLOwner(context.Context, *AdminSetURLOwnerRequest) (*AdminURL, error)
+	// GetUrlsByUser возвращает страницу URL пользователя user_id со служебными данными.
+	GetUrlsByUser(context.Context, *GetUrlsByUserRequest) (*AdminGetUrlsByUserResponse, error)
+	// DeleteUrlsOfUser помечает удаленными все URL пользователя.
+	DeleteUrlsOfUser(context.Context, *AdminDeleteUrlsOfUserRequest) (*AdminDeleteUrlsResponse, error)
+	// DeleteUrlsOfDomain помечает удаленными все URL на домен и его поддомены.
+	DeleteUrlsOfDomain(context.Context, *AdminDeleteUrlsOfDomainRequest) (*AdminDeleteUrlsResponse, error)
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+// UnimplementedAdminServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedAdminServiceServer struct {
+}
+
+func (UnimplementedAdminServiceServer) GetURL(context.Context, *AdminGetURLRequest) (*AdminURL, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetURL not implemented")
+}
+func (UnimplementedAdminServiceServer) SetURLDisabled(context.Context, *AdminSetURLDisabledRequest) (*AdminURL, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetURLDisabled not implemented")
+}
+func (UnimplementedAdminServiceServer) SetURLOwner(context.Context, *AdminSetURLOwnerRequest) (*AdminURL, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetURLOwner not implemented")
+}
+func (UnimplementedAdminServiceServer) GetUrlsByUser(context.Context, *GetUrlsByUserRequest) (*AdminGetUrlsByUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUrlsByUser not implemented")
+}
+func (UnimplementedAdminServiceServer) DeleteUrlsOfUser(context.Context, *AdminDeleteUrlsOfUserRequest) (*AdminDeleteUrlsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteUrlsOfUser not implemented")
+}
+func (UnimplementedAdminServiceServer) DeleteUrlsOfDomain(context.Context, *AdminDeleteUrlsOfDomainRequest) (*AdminDeleteUrlsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteUrlsOfDomain not implemented")
+}
+func (UnimplementedAdminServiceServer) mustEmbedUnimplementedAdminServiceServer() {}
+
+// UnsafeAdminServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdminServiceServer will
+// result in compilation errors.
+type UnsafeAdminServiceServer interface {
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+func RegisterAdminServiceServer(s grpc.ServiceRegistrar, srv AdminServiceServer) {
+	s.RegisterService(&AdminService_ServiceDesc, srv)
+}
+
+func _AdminService_GetURL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminGetURLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetURL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetURL_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetURL(ctx, req.(*AdminGetURLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_SetURLDisabled_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminSetURLDisabledRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).SetURLDisabled(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_SetURLDisabled_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).SetURLDisabled(ctx, req.(*AdminSetURLDisabledRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_SetURLOwner_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminSetURLOwnerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).SetURLOwner(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_SetURLOwner_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).SetURLOwner(ctx, req.(*AdminSetURLOwnerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetUrlsByUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUrlsByUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetUrlsByUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetUrlsByUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetUrlsByUser(ctx, req.(*GetUrlsByUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_DeleteUrlsOfUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminDeleteUrlsOfUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).DeleteUrlsOfUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_DeleteUrlsOfUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).DeleteUrlsOfUser(ctx, req.(*AdminDeleteUrlsOfUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_DeleteUrlsOfDomain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminDeleteUrlsOfDomainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).DeleteUrlsOfDomain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_DeleteUrlsOfDomain_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).DeleteUrlsOfDomain(ctx, req.(*AdminDeleteUrlsOfDomainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AdminService_ServiceDesc is the grpc.ServiceDesc for AdminService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AdminService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "url_shortener.AdminService",
+	HandlerType: (*AdminServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetURL",
+			Handler:    _AdminService_GetURL_Handler,
+		},
+		{
+			MethodName: "SetURLDisabled",
+			Handler:    _AdminService_SetURLDisabled_Handler,
+		},
+		{
+			MethodName: "SetURLOwner",
+			Handler:    _AdminService_SetURLOwner_Handler,
+		},
+		{
+			MethodName: "GetUrlsByUser",
+			Handler:    _AdminService_GetUrlsByUser_Handler,
+		},
+		{
+			MethodName: "DeleteUrlsOfUser",
+			Handler:    _AdminService_DeleteUrlsOfUser_Handler,
+		},
+		{
+			MethodName: "DeleteUrlsOfDomain",
+			Handler:    _AdminService_DeleteUrlsOfDomain_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "server.proto",
@@ -5,9 +5,10 @@ import (
 	"net"
 
 	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/config"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/models"
 	grpc_server "github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/server/grpc"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/token"
 	"github.com/stretchr/testify/mock"
-	"google.golang.org/grpc"
 )
 
 // Запуск тестового сервера для тестирования
@@ -17,14 +18,16 @@ func startTestServer(config config.Config) error {
 		return err
 	}
 
-	s := grpc.NewServer(grpc.UnaryInterceptor(grpc_server.UnarySecurityInterceptor))
-
+	tokens, err := token.NewManager(config)
+	if err != nil {
+		return err
+	}
 	mockService := new(grpc_server.MockShortenerService)
-	mockService.On("CreateShortURL", mock.Anything, mock.Anything, "http://example.com").Return("abc123", nil)
+	mockService.On("CreateUser", mock.Anything).Return(models.User{ID: 1}, nil)
+	mockService.On("CreateShortURL", mock.Anything, mock.Anything, models.Request{URL: "http://example.com"}).Return("abc123", nil)
 	mockService.On("GetByShortURL", mock.Anything, "abc123").Return("http://example.com", nil)
 
-	handler := grpc_server.NewShortenerHandler(config, mockService)
-	grpc_server.RegisterShortenerServiceServer(s, handler)
+	s := grpc_server.NewServer(config, mockService, tokens, nil, nil, nil)
 
 	log.Println("Starting gRPC server")
 	if err := s.Serve(listen); err != nil {
@@ -38,6 +41,7 @@ func main() {
 	config := config.Config{}
 	config.ServerURL = "localhost:50051"
 	config.BaseReturnURL = "http://short.url"
+	config.JWTKeys = "example:secret"
 	err := startTestServer(config)
 	if err != nil {
 		panic(err)
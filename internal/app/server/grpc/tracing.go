@@ -0,0 +1,90 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/tracing"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// traceparentKey ключ метаданных W3C с контекстом трассировки.
+const traceparentKey = "traceparent"
+
+// TracingInterceptor создает серверный спан для каждого вызова gRPC.
+// Контекст трассировки клиента принимается из метаданных traceparent, а контекст созданного спана
+// возвращается в метаданных заголовка ответа traceparent.
+type TracingInterceptor struct{}
+
+// NewTracingInterceptor создает новый экземпляр TracingInterceptor.
+func NewTracingInterceptor() *TracingInterceptor {
+	return &TracingInterceptor{}
+}
+
+// Unary обрабатывает унарный вызов в серверном спане.
+func (i *TracingInterceptor) Unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, span := startServerSpan(ctx, info.FullMethod)
+	if span == nil {
+		return handler(ctx, req)
+	}
+	defer span.End()
+	if err := grpc.SetHeader(ctx, metadata.Pairs(traceparentKey, span.SpanContext().Traceparent())); err != nil {
+		return nil, err
+	}
+	resp, err := handler(ctx, req)
+	endServerSpan(span, err)
+	return resp, err
+}
+
+// Stream обрабатывает потоковый вызов в серверном спане.
+func (i *TracingInterceptor) Stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, span := startServerSpan(ss.Context(), info.FullMethod)
+	stream := &tracedStream{ServerStream: ss, ctx: ctx}
+	if span == nil {
+		return handler(srv, stream)
+	}
+	defer span.End()
+	if err := ss.SetHeader(metadata.Pairs(traceparentKey, span.SpanContext().Traceparent())); err != nil {
+		return err
+	}
+	err := handler(srv, stream)
+	endServerSpan(span, err)
+	return err
+}
+
+// startServerSpan создает серверный спан метода method, дочерний к контексту трассировки из метаданных вызова.
+func startServerSpan(ctx context.Context, method string) (context.Context, *tracing.Span) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(traceparentKey); len(values) > 0 {
+		if sc, ok := tracing.ParseTraceparent(values[0]); ok {
+			ctx = tracing.ContextWithSpanContext(ctx, sc)
+		}
+	}
+	return tracing.StartKind(ctx, tracing.KindServer, method,
+		tracing.String("rpc.system", "grpc"),
+		tracing.String("rpc.method", method),
+	)
+}
+
+// endServerSpan записывает код статуса вызова. Ошибками спана считаются только ошибки сервера.
+func endServerSpan(span *tracing.Span, err error) {
+	code := status.Code(err)
+	span.SetAttributes(tracing.Int("rpc.grpc.status_code", int(code)))
+	switch code {
+	case codes.Unknown, codes.Internal, codes.Unavailable, codes.DataLoss, codes.DeadlineExceeded, codes.Unimplemented:
+		span.RecordError(err)
+	}
+}
+
+// tracedStream подменяет контекст потока контекстом со спаном.
+type tracedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context возвращает контекст потока со спаном.
+func (s *tracedStream) Context() context.Context {
+	return s.ctx
+}
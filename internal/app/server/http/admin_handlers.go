@@ -0,0 +1,124 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/models"
+	"github.com/go-chi/chi/v5"
+)
+
+// AdminService определяет методы сервиса для административного API.
+type AdminService interface {
+	// AdminGetURL возвращает URL с владельцем и служебными данными.
+	AdminGetURL(ctx context.Context, shortURL string) (models.AdminURL, error)
+	// AdminSetURLDisabled отключает или включает URL.
+	AdminSetURLDisabled(ctx context.Context, shortURL string, disabled bool) (models.AdminURL, error)
+	// AdminSetURLOwner передает URL другому пользователю.
+	AdminSetURLOwner(ctx context.Context, shortURL string, userID int) (models.AdminURL, error)
+	// AdminGetUrlsByUser возвращает страницу URL пользователя со служебными данными.
+	AdminGetUrlsByUser(ctx context.Context, userID int, query models.URLQuery) (models.AdminURLPage, error)
+	// AdminDeleteUrlsOfUser помечает удаленными все URL пользователя.
+	AdminDeleteUrlsOfUser(ctx context.Context, userID int) (models.AdminDeleteResult, error)
+	// AdminDeleteUrlsOfDomain помечает удаленными все URL на домен и его поддомены.
+	AdminDeleteUrlsOfDomain(ctx context.Context, domain string) (models.AdminDeleteResult, error)
+}
+
+type adminHandler struct {
+	service AdminService
+}
+
+// NewAdminHandler создает новый экземпляр обработчика административного API.
+func NewAdminHandler(service AdminService) *adminHandler {
+	return &adminHandler{service: service}
+}
+
+// AdminURLHandler возвращает любой сокращенный URL с владельцем и служебными данными.
+func (handler *adminHandler) AdminURLHandler(res http.ResponseWriter, req *http.Request) {
+	url, err := handler.service.AdminGetURL(req.Context(), chi.URLParam(req, "short"))
+	writeAdminResult(res, url, err)
+}
+
+// AdminDisableURLHandler отключает сокращенный URL: переход по нему возвращает 410 Gone.
+func (handler *adminHandler) AdminDisableURLHandler(res http.ResponseWriter, req *http.Request) {
+	url, err := handler.service.AdminSetURLDisabled(req.Context(), chi.URLParam(req, "short"), true)
+	writeAdminResult(res, url, err)
+}
+
+// AdminEnableURLHandler включает отключенный ранее сокращенный URL.
+func (handler *adminHandler) AdminEnableURLHandler(res http.ResponseWriter, req *http.Request) {
+	url, err := handler.service.AdminSetURLDisabled(req.Context(), chi.URLParam(req, "short"), false)
+	writeAdminResult(res, url, err)
+}
+
+// AdminURLOwnerHandler передает сокращенный URL пользователю из тела запроса {"user_id": N}.
+func (handler *adminHandler) AdminURLOwnerHandler(res http.ResponseWriter, req *http.Request) {
+	var request models.AdminOwnerRequest
+	if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+		res.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	url, err := handler.service.AdminSetURLOwner(req.Context(), chi.URLParam(req, "short"), request.UserID)
+	writeAdminResult(res, url, err)
+}
+
+// AdminUrlsByUserHandler возвращает страницу URL пользователя со служебными данными.
+// Параметры выборки и курсор следующей страницы те же, что у /api/user/urls.
+func (handler *adminHandler) AdminUrlsByUserHandler(res http.ResponseWriter, req *http.Request) {
+	userID, err := strconv.Atoi(chi.URLParam(req, "id"))
+	if err != nil {
+		res.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	query, err := parseURLQuery(req)
+	if err != nil {
+		res.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	page, err := handler.service.AdminGetUrlsByUser(req.Context(), userID, query)
+	if writeServiceError(err, res) {
+		return
+	}
+	if len(page.URLs) == 0 {
+		res.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if page.NextCursor != "" {
+		res.Header().Add(nextCursorHeader, page.NextCursor)
+	}
+	writeAdminResult(res, page.URLs, nil)
+}
+
+// AdminDeleteUserUrlsHandler помечает удаленными все URL пользователя.
+func (handler *adminHandler) AdminDeleteUserUrlsHandler(res http.ResponseWriter, req *http.Request) {
+	userID, err := strconv.Atoi(chi.URLParam(req, "id"))
+	if err != nil {
+		res.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	result, err := handler.service.AdminDeleteUrlsOfUser(req.Context(), userID)
+	writeAdminResult(res, result, err)
+}
+
+// AdminDeleteDomainUrlsHandler помечает удаленными все URL, исходный URL которых ведет на домен или его поддомен.
+func (handler *adminHandler) AdminDeleteDomainUrlsHandler(res http.ResponseWriter, req *http.Request) {
+	result, err := handler.service.AdminDeleteUrlsOfDomain(req.Context(), chi.URLParam(req, "domain"))
+	writeAdminResult(res, result, err)
+}
+
+// writeAdminResult отвечает ошибкой сервиса err или телом body в формате JSON со статусом 200 OK.
+func writeAdminResult(res http.ResponseWriter, body any, err error) {
+	if writeServiceError(err, res) {
+		return
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		res.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	res.Header().Add("content-type", "application/json")
+	res.WriteHeader(http.StatusOK)
+	res.Write(data)
+}
@@ -8,7 +8,7 @@ import (
 	"strings"
 )
 
-func ExampleStartServer() {
+func ExampleNewServer() {
 	handler, _ := getHandler()
 
 	// Создание запроса на сокращение URL
@@ -32,5 +32,5 @@ func ExampleStartServer() {
 	resp = httptest.NewRecorder()
 	handler.ExpandHandler(resp, req)
 	fmt.Println(resp.Header().Get("Location"))
-	// Output: https://example.com
+	// Output: https://example.com/
 }
@@ -6,52 +6,77 @@ import (
 	"errors"
 	"io"
 	"net/http"
+	"strconv"
+	"time"
 
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/clientip"
 	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/config"
 	customerrors "github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/errors"
 	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/models"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/util"
+	"github.com/go-chi/chi/v5"
 )
 
 // ShortenerService определяет методы для взаимодействия с сервисом сокращения URL.
 type ShortenerService interface {
 	// CreateShortURL создает сокращенный URL на основе исходного URL.
-	CreateShortURL(ctx context.Context, userInfo models.UserInfo, shortURL string) (string, error)
+	CreateShortURL(ctx context.Context, userInfo models.UserInfo, request models.Request) (string, error)
 	// CreateBatchShortURL создает несколько сокращенных URL на основе списка исходных URL.
 	CreateBatchShortURL(ctx context.Context, userInfo models.UserInfo, arr []models.OriginalURLInfoBatch) ([]models.ShortURLInfoBatch, error)
 	// GetByShortURL возвращает исходный URL по сокращенному URL.
 	GetByShortURL(ctx context.Context, shortURL string) (string, error)
 	// PingStorage проверяет доступность хранилища данных.
 	PingStorage(ctx context.Context) bool
-	// GetUrlsByUser возвращает список URL, созданных пользователем.
-	GetUrlsByUser(ctx context.Context, userInfo models.UserInfo) ([]models.URLByUser, error)
-	// DeleteUrlsByUser удаляет список URL, созданных пользователем.
-	DeleteUrlsByUser(ctx context.Context, userInfo models.UserInfo, urls []string)
+	// GetUrlsByUser возвращает страницу URL, созданных пользователем.
+	GetUrlsByUser(ctx context.Context, userInfo models.UserInfo, query models.URLQuery) (models.URLPage, error)
+	// DeleteUrlsByUser создает задание на удаление списка URL, созданных пользователем.
+	DeleteUrlsByUser(ctx context.Context, userInfo models.UserInfo, urls []string) (models.DeletionJob, error)
+	// RestoreUrlsByUser восстанавливает удаленные URL, созданные пользователем.
+	RestoreUrlsByUser(ctx context.Context, userInfo models.UserInfo, urls []string) ([]models.RestoreResult, error)
+	// GetDeletionJob возвращает задание на удаление, созданное пользователем.
+	GetDeletionJob(ctx context.Context, userInfo models.UserInfo, id string) (models.DeletionJob, error)
 	// GetStats возвращающий в ответ объект статистики.
 	GetStats(ctx context.Context) (models.Stats, error)
+	// RecordClick сохраняет событие перехода по сокращенному URL.
+	RecordClick(ctx context.Context, click models.Click)
+	// GetURLStats возвращает статистику переходов по сокращенному URL, созданному пользователем.
+	GetURLStats(ctx context.Context, userInfo models.UserInfo, shortURL string) (models.LinkStats, error)
 }
 
 type shortenerHandler struct {
 	service      ShortenerService
 	serverConfig config.Config
+	clients      *clientip.Resolver
 }
 
-// NewShortenerHandler создает новый экземпляр обработчика
-func NewShortenerHandler(config config.Config, service ShortenerService) *shortenerHandler {
+// NewShortenerHandler создает новый экземпляр обработчика.
+// IP-адрес клиента для статистики переходов определяется clients с учетом доверенных прокси.
+func NewShortenerHandler(config config.Config, service ShortenerService, clients *clientip.Resolver) *shortenerHandler {
 	return &shortenerHandler{
 		service:      service,
 		serverConfig: config,
+		clients:      clients,
 	}
 }
 
 // ShortenHandler создает сокращенный URL на основе исходного URL
+// Параметр запроса dedupe=true включает дедупликацию: если пользователь уже сокращал этот URL, возвращается 409 с существующей ссылкой.
 func (handler *shortenerHandler) ShortenHandler(res http.ResponseWriter, req *http.Request) {
 	body, err := io.ReadAll(req.Body)
 	if err != nil {
 		res.WriteHeader(http.StatusBadRequest)
 		return
 	}
+	var dedupe bool
+	if value := req.URL.Query().Get("dedupe"); value != "" {
+		dedupe, err = strconv.ParseBool(value)
+		if err != nil {
+			res.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}
 	userInfo := handler.getUserInfo(req.Context())
-	shortURL, err := handler.service.CreateShortURL(req.Context(), userInfo, string(body))
+	shortURL, err := handler.service.CreateShortURL(req.Context(), userInfo, models.Request{URL: string(body), Dedupe: dedupe})
 	shouldReturn := handler.validateShortenHandlerResult(err, res)
 	if shouldReturn {
 		return
@@ -65,7 +90,7 @@ func (handler *shortenerHandler) validateShortenHandlerResult(err error, res htt
 	if err != nil {
 		var customerr *customerrors.CustomError
 		if errors.As(err, &customerr) {
-			if customerr.Status == http.StatusConflict {
+			if customerr.Status == http.StatusConflict && customerr.ShortURL != "" {
 				customerr.ContentType = "text/plain"
 				customerr.Body = []byte(handler.serverConfig.BaseReturnURL + "/" + customerr.ShortURL)
 			}
@@ -98,7 +123,7 @@ func (handler *shortenerHandler) ShortenJSONHandler(res http.ResponseWriter, req
 		return
 	}
 	userInfo := handler.getUserInfo(req.Context())
-	shortURL, err := handler.service.CreateShortURL(req.Context(), userInfo, reqModel.URL)
+	shortURL, err := handler.service.CreateShortURL(req.Context(), userInfo, reqModel)
 	shouldReturn := handler.validateShortenJSONHandlerResult(err, res)
 	if shouldReturn {
 		return
@@ -120,7 +145,7 @@ func (handler *shortenerHandler) validateShortenJSONHandlerResult(err error, res
 	if err != nil {
 		var customerr *customerrors.CustomError
 		if errors.As(err, &customerr) {
-			if customerr.Status == http.StatusConflict {
+			if customerr.Status == http.StatusConflict && customerr.ShortURL != "" {
 				customerr.ContentType = "application/json"
 				body, err := json.Marshal(&models.Response{
 					Result: handler.serverConfig.BaseReturnURL + "/" + customerr.ShortURL,
@@ -148,16 +173,29 @@ func (handler *shortenerHandler) validateShortenJSONHandlerResult(err error, res
 
 // ExpandHandler возвращает исходный URL по сокращенному URL.
 func (handler *shortenerHandler) ExpandHandler(res http.ResponseWriter, req *http.Request) {
-	originalURL, err := handler.service.GetByShortURL(req.Context(), req.URL.Path[1:])
+	shortURL := req.URL.Path[1:]
+	originalURL, err := handler.service.GetByShortURL(req.Context(), shortURL)
 	shouldReturn := handler.validateExpandHandlerResult(err, res)
 	if shouldReturn {
 		return
 	}
+	handler.service.RecordClick(req.Context(), models.Click{
+		ShortURL:  shortURL,
+		Timestamp: time.Now(),
+		Referrer:  req.Referer(),
+		UserAgent: req.UserAgent(),
+		ClientIP:  handler.clients.FromRequest(req),
+	})
 	res.Header().Add("Location", originalURL)
 	res.WriteHeader(http.StatusTemporaryRedirect)
 }
 
 func (*shortenerHandler) validateExpandHandlerResult(err error, res http.ResponseWriter) bool {
+	return writeServiceError(err, res)
+}
+
+// writeServiceError отвечает статусом и телом ошибки сервиса, если она есть, и возвращает true, если ответ записан.
+func writeServiceError(err error, res http.ResponseWriter) bool {
 	if err != nil {
 		var customerr *customerrors.CustomError
 		if errors.As(err, &customerr) {
@@ -186,7 +224,8 @@ func (handler *shortenerHandler) PingStorageHandler(res http.ResponseWriter, req
 	res.WriteHeader(http.StatusOK)
 }
 
-// ShortenJSONBatchHandler создает сокращенный URL на основе исход
+// ShortenJSONBatchHandler создает сокращенные URL на основе списка исходных URL.
+// Если все URL сокращены, возвращается 201, иначе 207 с результатом и причиной ошибки для каждого URL.
 func (handler *shortenerHandler) ShortenJSONBatchHandler(res http.ResponseWriter, req *http.Request) {
 	body, err := io.ReadAll(req.Body)
 	if err != nil {
@@ -210,8 +249,15 @@ func (handler *shortenerHandler) ShortenJSONBatchHandler(res http.ResponseWriter
 		res.WriteHeader(http.StatusInternalServerError)
 		return
 	}
+	status := http.StatusCreated
+	for _, url := range shortURLArray {
+		if url.Status != models.BatchItemCreated {
+			status = http.StatusMultiStatus
+			break
+		}
+	}
 	res.Header().Add("content-type", "application/json")
-	res.WriteHeader(http.StatusCreated)
+	res.WriteHeader(status)
 	res.Write(body)
 }
 
@@ -219,7 +265,7 @@ func (handler *shortenerHandler) validateShortenJSONBatchHandlerResult(err error
 	if err != nil {
 		var customerr *customerrors.CustomError
 		if errors.As(err, &customerr) {
-			if customerr.Status == http.StatusConflict {
+			if customerr.Status == http.StatusConflict && customerr.ShortURL != "" {
 				customerr.ContentType = "application/json"
 				body, err := json.Marshal(&models.Response{
 					Result: handler.serverConfig.BaseReturnURL + "/" + customerr.ShortURL,
@@ -245,19 +291,47 @@ func (handler *shortenerHandler) validateShortenJSONBatchHandlerResult(err error
 	return false
 }
 
-// UrlsByUserHandler возвращает все сокращенные URL для пользователя
+// UrlsByUserHandler возвращает страницу сокращенных URL для пользователя.
+// Параметры запроса limit, cursor, created_from, created_to, original_url, deleted, sort и order задают выборку,
+// курсор следующей страницы возвращается в заголовке X-Next-Cursor.
 func (handler *shortenerHandler) UrlsByUserHandler(res http.ResponseWriter, req *http.Request) {
+	query, err := parseURLQuery(req)
+	if err != nil {
+		res.WriteHeader(http.StatusBadRequest)
+		return
+	}
 	userInfo := handler.getUserInfo(req.Context())
-	urls, err := handler.service.GetUrlsByUser(req.Context(), userInfo)
+	page, err := handler.service.GetUrlsByUser(req.Context(), userInfo, query)
+	shouldReturn := handler.validateExpandHandlerResult(err, res)
+	if shouldReturn {
+		return
+	}
+	if len(page.URLs) == 0 {
+		res.WriteHeader(http.StatusNoContent)
+		return
+	}
+	body, err := json.Marshal(page.URLs)
 	if err != nil {
 		res.WriteHeader(http.StatusInternalServerError)
 		return
 	}
-	if len(urls) == 0 {
-		res.WriteHeader(http.StatusNoContent)
+	if page.NextCursor != "" {
+		res.Header().Add(nextCursorHeader, page.NextCursor)
+	}
+	res.Header().Add("content-type", "application/json")
+	res.WriteHeader(http.StatusOK)
+	res.Write(body)
+}
+
+// URLStatsHandler возвращает статистику переходов по сокращенному URL пользователя
+func (handler *shortenerHandler) URLStatsHandler(res http.ResponseWriter, req *http.Request) {
+	userInfo := handler.getUserInfo(req.Context())
+	stats, err := handler.service.GetURLStats(req.Context(), userInfo, chi.URLParam(req, "short"))
+	shouldReturn := handler.validateExpandHandlerResult(err, res)
+	if shouldReturn {
 		return
 	}
-	body, err := json.Marshal(urls)
+	body, err := json.Marshal(stats)
 	if err != nil {
 		res.WriteHeader(http.StatusInternalServerError)
 		return
@@ -267,7 +341,52 @@ func (handler *shortenerHandler) UrlsByUserHandler(res http.ResponseWriter, req
 	res.Write(body)
 }
 
-// DeleteUrlsHandler удаляет все сокращенные URL
+// nextCursorHeader заголовок ответа с курсором следующей страницы URL пользователя.
+const nextCursorHeader = "X-Next-Cursor"
+
+func parseURLQuery(req *http.Request) (models.URLQuery, error) {
+	params := req.URL.Query()
+	var query models.URLQuery
+	var err error
+	if limit := params.Get("limit"); limit != "" {
+		if query.Limit, err = strconv.Atoi(limit); err != nil {
+			return query, err
+		}
+	}
+	if query.Cursor, err = util.DecodeURLCursor(params.Get("cursor")); err != nil {
+		return query, err
+	}
+	if createdFrom := params.Get("created_from"); createdFrom != "" {
+		if query.CreatedFrom, err = time.Parse(time.RFC3339, createdFrom); err != nil {
+			return query, err
+		}
+	}
+	if createdTo := params.Get("created_to"); createdTo != "" {
+		if query.CreatedTo, err = time.Parse(time.RFC3339, createdTo); err != nil {
+			return query, err
+		}
+	}
+	query.OriginalURLContains = params.Get("original_url")
+	if deleted := params.Get("deleted"); deleted != "" {
+		isDeleted, err := strconv.ParseBool(deleted)
+		if err != nil {
+			return query, err
+		}
+		query.Deleted = &isDeleted
+	}
+	query.SortBy = params.Get("sort")
+	switch params.Get("order") {
+	case "", "asc":
+	case "desc":
+		query.SortDesc = true
+	default:
+		return query, errors.New("unknown sort order")
+	}
+	return query, nil
+}
+
+// DeleteUrlsHandler создает задание на удаление сокращенных URL пользователя.
+// Возвращает задание и его адрес в заголовке Location.
 func (handler *shortenerHandler) DeleteUrlsHandler(res http.ResponseWriter, req *http.Request) {
 	body, err := io.ReadAll(req.Body)
 	if err != nil {
@@ -280,8 +399,66 @@ func (handler *shortenerHandler) DeleteUrlsHandler(res http.ResponseWriter, req
 		return
 	}
 	userInfo := handler.getUserInfo(req.Context())
-	handler.service.DeleteUrlsByUser(req.Context(), userInfo, urls)
+	job, err := handler.service.DeleteUrlsByUser(req.Context(), userInfo, urls)
+	shouldReturn := handler.validateExpandHandlerResult(err, res)
+	if shouldReturn {
+		return
+	}
+	body, err = json.Marshal(job)
+	if err != nil {
+		res.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	res.Header().Add("Location", "/api/user/deletions/"+job.ID)
+	res.Header().Add("content-type", "application/json")
 	res.WriteHeader(http.StatusAccepted)
+	res.Write(body)
+}
+
+// RestoreUrlsHandler восстанавливает удаленные сокращенные URL пользователя и возвращает результат по каждому URL.
+func (handler *shortenerHandler) RestoreUrlsHandler(res http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		res.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	var urls []string
+	if err := json.Unmarshal(body, &urls); err != nil {
+		res.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	userInfo := handler.getUserInfo(req.Context())
+	results, err := handler.service.RestoreUrlsByUser(req.Context(), userInfo, urls)
+	shouldReturn := handler.validateExpandHandlerResult(err, res)
+	if shouldReturn {
+		return
+	}
+	body, err = json.Marshal(results)
+	if err != nil {
+		res.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	res.Header().Add("content-type", "application/json")
+	res.WriteHeader(http.StatusOK)
+	res.Write(body)
+}
+
+// DeletionJobHandler возвращает состояние задания на удаление и результаты по каждому URL.
+func (handler *shortenerHandler) DeletionJobHandler(res http.ResponseWriter, req *http.Request) {
+	userInfo := handler.getUserInfo(req.Context())
+	job, err := handler.service.GetDeletionJob(req.Context(), userInfo, chi.URLParam(req, "id"))
+	shouldReturn := handler.validateExpandHandlerResult(err, res)
+	if shouldReturn {
+		return
+	}
+	body, err := json.Marshal(job)
+	if err != nil {
+		res.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	res.Header().Add("content-type", "application/json")
+	res.WriteHeader(http.StatusOK)
+	res.Write(body)
 }
 
 // GetStats возвращающий в ответ объект статистики.
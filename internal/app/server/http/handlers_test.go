@@ -5,6 +5,7 @@ import (
 	"compress/gzip"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -12,14 +13,18 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/health"
 	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/logger"
 	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/models"
 	gzipreq "github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/server/http/middlewares/gzip"
 	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/service"
 	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/storage"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/token"
 
 	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/config"
+	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -33,11 +38,12 @@ func TestShortenHandler(t *testing.T) {
 	require.NoError(t, err)
 	service, err := service.NewShortenerService(ctx, config, storage)
 	require.NoError(t, err)
-	handler := NewShortenerHandler(config, service)
+	handler := NewShortenerHandler(config, service, nil)
 	require.NoError(t, err)
 	tests := []struct {
 		name           string
 		url            string
+		target         string
 		expectedStatus int
 	}{
 		{
@@ -50,16 +56,38 @@ func TestShortenHandler(t *testing.T) {
 			url:            "",
 			expectedStatus: 400,
 		},
+		{
+			name:           "repeated url without dedupe",
+			url:            "https://practicum.yandex.ru/",
+			expectedStatus: 201,
+		},
+		{
+			name:           "repeated url with dedupe",
+			url:            "https://practicum.yandex.ru/",
+			target:         "/?dedupe=true",
+			expectedStatus: 409,
+		},
+		{
+			name:           "invalid dedupe",
+			url:            "https://practicum.yandex.ru/",
+			target:         "/?dedupe=maybe",
+			expectedStatus: 400,
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			request := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(test.url)))
+			target := test.target
+			if target == "" {
+				target = "/"
+			}
+			request := httptest.NewRequest(http.MethodPost, target, bytes.NewReader([]byte(test.url)))
+			request = request.WithContext(context.WithValue(request.Context(), models.UserID, 1))
 			w := httptest.NewRecorder()
 			handler.ShortenHandler(w, request)
 			res := w.Result()
 			defer res.Body.Close()
 			equals := assert.Equal(t, test.expectedStatus, res.StatusCode)
-			if equals && test.expectedStatus == 201 {
+			if equals && (test.expectedStatus == 201 || test.expectedStatus == 409) {
 				resBody, err := io.ReadAll(res.Body)
 				require.NoError(t, err)
 				partsURL := strings.Split(string(resBody), "/")
@@ -79,7 +107,7 @@ func TestExpandHandler(t *testing.T) {
 	require.NoError(t, err)
 	service, err := service.NewShortenerService(ctx, config, storage)
 	require.NoError(t, err)
-	handler := NewShortenerHandler(config, service)
+	handler := NewShortenerHandler(config, service, nil)
 	require.NoError(t, err)
 	originalURL := "https://gophercises.com/#signup"
 	savedShortURL := prepareShortURL(handler, originalURL)
@@ -112,6 +140,347 @@ func TestExpandHandler(t *testing.T) {
 	}
 }
 
+func TestExpandHandlerExpired(t *testing.T) {
+	err := logger.Init(slog.LevelInfo)
+	require.NoError(t, err)
+	config := config.GetDefault()
+	ctx := context.Background()
+	storage, err := storage.NewShortenerStorage(storage.GetStorageTypeByConfig(config), config)
+	require.NoError(t, err)
+	service, err := service.NewShortenerService(ctx, config, storage)
+	require.NoError(t, err)
+	handler := NewShortenerHandler(config, service, nil)
+	err = storage.Save(ctx, models.URL{
+		ShortURL:    "expired",
+		OriginalURL: "https://practicum.yandex.ru/",
+		ExpiresAt:   time.Now().Add(-time.Minute),
+	})
+	require.NoError(t, err)
+	request := httptest.NewRequest(http.MethodGet, "/expired", nil)
+	w := httptest.NewRecorder()
+	handler.ExpandHandler(w, request)
+	res := w.Result()
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusGone, res.StatusCode)
+}
+
+func TestShortenJSONHandlerExpiration(t *testing.T) {
+	err := logger.Init(slog.LevelInfo)
+	require.NoError(t, err)
+	handler, err := getHandler()
+	require.NoError(t, err)
+	tests := []struct {
+		name           string
+		reqBody        []byte
+		expectedStatus int
+	}{
+		{
+			name:           "ttl",
+			reqBody:        []byte(`{"url":"https://practicum.yandex.ru/","ttl":3600}`),
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "expires_at",
+			reqBody:        []byte(fmt.Sprintf(`{"url":"https://practicum.yandex.ru/","expires_at":"%s"}`, time.Now().Add(time.Hour).Format(time.RFC3339))),
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "expires_at in the past",
+			reqBody:        []byte(`{"url":"https://practicum.yandex.ru/","expires_at":"2020-01-01T00:00:00Z"}`),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "negative ttl",
+			reqBody:        []byte(`{"url":"https://practicum.yandex.ru/","ttl":-1}`),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "both ttl and expires_at",
+			reqBody:        []byte(fmt.Sprintf(`{"url":"https://practicum.yandex.ru/","ttl":60,"expires_at":"%s"}`, time.Now().Add(time.Hour).Format(time.RFC3339))),
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodPost, "/api/shorten", bytes.NewReader(test.reqBody))
+			request.Header.Set("content-type", "application/json")
+			w := httptest.NewRecorder()
+			handler.ShortenJSONHandler(w, request)
+			res := w.Result()
+			defer res.Body.Close()
+			assert.Equal(t, test.expectedStatus, res.StatusCode)
+		})
+	}
+}
+
+func TestURLStatsHandler(t *testing.T) {
+	err := logger.Init(slog.LevelInfo)
+	require.NoError(t, err)
+	config := config.GetDefault()
+	ctx := context.Background()
+	storage, err := storage.NewShortenerStorage(storage.GetStorageTypeByConfig(config), config)
+	require.NoError(t, err)
+	service, err := service.NewShortenerService(ctx, config, storage)
+	require.NoError(t, err)
+	handler := NewShortenerHandler(config, service, nil)
+	err = storage.Save(ctx, models.URL{ShortURL: "stats", OriginalURL: "https://practicum.yandex.ru/", CreatedBy: 1})
+	require.NoError(t, err)
+	now := time.Now()
+	err = storage.SaveClicks(ctx, []models.Click{
+		{ShortURL: "stats", Timestamp: now, Referrer: "https://ya.ru", UserAgent: "curl", ClientIP: "10.0.0.1"},
+		{ShortURL: "stats", Timestamp: now, Referrer: "https://ya.ru", UserAgent: "curl", ClientIP: "10.0.0.2"},
+		{ShortURL: "stats", Timestamp: now, UserAgent: "wget", ClientIP: "10.0.0.1"},
+	})
+	require.NoError(t, err)
+	tests := []struct {
+		name           string
+		userID         int
+		shortURL       string
+		expectedStatus int
+	}{
+		{
+			name:           "owner",
+			userID:         1,
+			shortURL:       "stats",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "not owner",
+			userID:         2,
+			shortURL:       "stats",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "not found",
+			userID:         1,
+			shortURL:       "unknown",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "/api/user/urls/"+test.shortURL+"/stats", nil)
+			routeCtx := chi.NewRouteContext()
+			routeCtx.URLParams.Add("short", test.shortURL)
+			reqCtx := context.WithValue(request.Context(), chi.RouteCtxKey, routeCtx)
+			reqCtx = context.WithValue(reqCtx, models.UserID, test.userID)
+			w := httptest.NewRecorder()
+			handler.URLStatsHandler(w, request.WithContext(reqCtx))
+			res := w.Result()
+			defer res.Body.Close()
+			statusValid := assert.Equal(t, test.expectedStatus, res.StatusCode)
+			if statusValid && test.expectedStatus == http.StatusOK {
+				var stats models.LinkStats
+				err := json.NewDecoder(res.Body).Decode(&stats)
+				require.NoError(t, err)
+				assert.Equal(t, 3, stats.TotalClicks)
+				assert.Equal(t, 2, stats.UniqueVisitors)
+				assert.Equal(t, []models.CountItem{{Value: "https://ya.ru", Count: 2}}, stats.TopReferrers)
+				assert.Equal(t, []models.CountItem{{Value: "curl", Count: 2}, {Value: "wget", Count: 1}}, stats.TopUserAgents)
+			}
+		})
+	}
+}
+
+func TestDeleteUrlsHandler(t *testing.T) {
+	err := logger.Init(slog.LevelInfo)
+	require.NoError(t, err)
+	config := config.GetDefault()
+	ctx := context.Background()
+	storage, err := storage.NewShortenerStorage(storage.GetStorageTypeByConfig(config), config)
+	require.NoError(t, err)
+	service, err := service.NewShortenerService(ctx, config, storage)
+	require.NoError(t, err)
+	handler := NewShortenerHandler(config, service, nil)
+	err = storage.Save(ctx, models.URL{ShortURL: "todelete", OriginalURL: "https://practicum.yandex.ru/", CreatedBy: 1})
+	require.NoError(t, err)
+
+	request := httptest.NewRequest(http.MethodDelete, "/api/user/urls", strings.NewReader(`["todelete"]`))
+	w := httptest.NewRecorder()
+	handler.DeleteUrlsHandler(w, request.WithContext(context.WithValue(request.Context(), models.UserID, 1)))
+	res := w.Result()
+	defer res.Body.Close()
+	require.Equal(t, http.StatusAccepted, res.StatusCode)
+	var job models.DeletionJob
+	err = json.NewDecoder(res.Body).Decode(&job)
+	require.NoError(t, err)
+	assert.Equal(t, models.DeletionJobPending, job.Status)
+	assert.Equal(t, "/api/user/deletions/"+job.ID, res.Header.Get("Location"))
+
+	tests := []struct {
+		name           string
+		userID         int
+		id             string
+		expectedStatus int
+	}{
+		{
+			name:           "owner",
+			userID:         1,
+			id:             job.ID,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "not owner",
+			userID:         2,
+			id:             job.ID,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "not found",
+			userID:         1,
+			id:             "unknown",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "/api/user/deletions/"+test.id, nil)
+			routeCtx := chi.NewRouteContext()
+			routeCtx.URLParams.Add("id", test.id)
+			reqCtx := context.WithValue(request.Context(), chi.RouteCtxKey, routeCtx)
+			reqCtx = context.WithValue(reqCtx, models.UserID, test.userID)
+			w := httptest.NewRecorder()
+			handler.DeletionJobHandler(w, request.WithContext(reqCtx))
+			res := w.Result()
+			defer res.Body.Close()
+			statusValid := assert.Equal(t, test.expectedStatus, res.StatusCode)
+			if statusValid && test.expectedStatus == http.StatusOK {
+				var got models.DeletionJob
+				err := json.NewDecoder(res.Body).Decode(&got)
+				require.NoError(t, err)
+				assert.Equal(t, job.ID, got.ID)
+				assert.Equal(t, []models.DeletionJobURL{{ShortURL: "todelete", Status: models.DeletionURLPending}}, got.URLs)
+			}
+		})
+	}
+}
+
+func TestRestoreUrlsHandler(t *testing.T) {
+	err := logger.Init(slog.LevelInfo)
+	require.NoError(t, err)
+	config := config.GetDefault()
+	ctx := context.Background()
+	storage, err := storage.NewShortenerStorage(storage.GetStorageTypeByConfig(config), config)
+	require.NoError(t, err)
+	service, err := service.NewShortenerService(ctx, config, storage)
+	require.NoError(t, err)
+	handler := NewShortenerHandler(config, service, nil)
+	err = storage.Save(ctx, models.URL{ShortURL: "torestore", OriginalURL: "https://practicum.yandex.ru/", CreatedBy: 1})
+	require.NoError(t, err)
+	_, err = storage.DeleteUrls(ctx, []models.URLToDelete{{UserID: 1, ShortURL: "torestore"}})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "restored",
+			body:           `["torestore", "unknown"]`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[{"short_url":"torestore","status":"restored"},{"short_url":"unknown","status":"not_restored"}]`,
+		},
+		{
+			name:           "empty list",
+			body:           `[]`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid body",
+			body:           `{`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodPost, "/api/user/urls/restore", strings.NewReader(test.body))
+			w := httptest.NewRecorder()
+			handler.RestoreUrlsHandler(w, request.WithContext(context.WithValue(request.Context(), models.UserID, 1)))
+			res := w.Result()
+			defer res.Body.Close()
+			assert.Equal(t, test.expectedStatus, res.StatusCode)
+			if test.expectedBody != "" {
+				body, err := io.ReadAll(res.Body)
+				require.NoError(t, err)
+				assert.JSONEq(t, test.expectedBody, string(body))
+			}
+		})
+	}
+}
+
+func TestUrlsByUserHandler(t *testing.T) {
+	err := logger.Init(slog.LevelInfo)
+	require.NoError(t, err)
+	config := config.GetDefault()
+	ctx := context.Background()
+	storage, err := storage.NewShortenerStorage(storage.GetStorageTypeByConfig(config), config)
+	require.NoError(t, err)
+	service, err := service.NewShortenerService(ctx, config, storage)
+	require.NoError(t, err)
+	handler := NewShortenerHandler(config, service, nil)
+	now := time.Now()
+	for _, url := range []models.URL{
+		{ShortURL: "ccc", OriginalURL: "https://example.com/1", CreatedBy: 1, CreatedTS: now},
+		{ShortURL: "aaa", OriginalURL: "https://example.com/2", CreatedBy: 1, CreatedTS: now.Add(time.Second)},
+		{ShortURL: "bbb", OriginalURL: "https://example.org/3", CreatedBy: 1, CreatedTS: now.Add(2 * time.Second)},
+		{ShortURL: "ddd", OriginalURL: "https://example.com/4", CreatedBy: 2, CreatedTS: now},
+	} {
+		require.NoError(t, storage.Save(ctx, url))
+	}
+	getUrls := func(query string) *http.Response {
+		request := httptest.NewRequest(http.MethodGet, "/api/user/urls?"+query, nil)
+		w := httptest.NewRecorder()
+		handler.UrlsByUserHandler(w, request.WithContext(context.WithValue(request.Context(), models.UserID, 1)))
+		return w.Result()
+	}
+	decodeUrls := func(res *http.Response) []string {
+		var urls []models.URLByUser
+		err := json.NewDecoder(res.Body).Decode(&urls)
+		require.NoError(t, err)
+		shortURLs := make([]string, 0, len(urls))
+		for _, url := range urls {
+			shortURLs = append(shortURLs, strings.TrimPrefix(url.ShortURL, config.BaseReturnURL+"/"))
+		}
+		return shortURLs
+	}
+
+	res := getUrls("")
+	defer res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, []string{"ccc", "aaa", "bbb"}, decodeUrls(res))
+	assert.Empty(t, res.Header.Get(nextCursorHeader))
+
+	res = getUrls("limit=2&sort=short_url&order=desc")
+	defer res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, []string{"ccc", "bbb"}, decodeUrls(res))
+	cursor := res.Header.Get(nextCursorHeader)
+	require.NotEmpty(t, cursor)
+
+	res = getUrls("limit=2&sort=short_url&order=desc&cursor=" + cursor)
+	defer res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, []string{"aaa"}, decodeUrls(res))
+	assert.Empty(t, res.Header.Get(nextCursorHeader))
+
+	res = getUrls("original_url=example.org")
+	defer res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, []string{"bbb"}, decodeUrls(res))
+
+	res = getUrls("original_url=unknown")
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusNoContent, res.StatusCode)
+
+	for _, query := range []string{"limit=abc", "limit=-1", "cursor=abc", "sort=unknown", "order=up", "created_from=yesterday"} {
+		res = getUrls(query)
+		defer res.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode, query)
+	}
+}
+
 func prepareShortURL(handlers *shortenerHandler, originalURL string) string {
 	request := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(originalURL)))
 	w := httptest.NewRecorder()
@@ -135,7 +504,7 @@ func TestShortenJSONHandler(t *testing.T) {
 	require.NoError(t, err)
 	service, err := service.NewShortenerService(ctx, config, storage)
 	require.NoError(t, err)
-	handler := NewShortenerHandler(config, service)
+	handler := NewShortenerHandler(config, service, nil)
 	require.NoError(t, err)
 	tests := []struct {
 		name            string
@@ -183,6 +552,85 @@ func TestShortenJSONHandler(t *testing.T) {
 	}
 }
 
+func TestShortenJSONHandlerInvalidURL(t *testing.T) {
+	err := logger.Init(slog.LevelInfo)
+	require.NoError(t, err)
+	handler, err := getHandler()
+	require.NoError(t, err)
+	request := httptest.NewRequest(http.MethodPost, "/api/shorten", strings.NewReader(`{"url":"javascript:alert(1)"}`))
+	w := httptest.NewRecorder()
+	handler.ShortenJSONHandler(w, request)
+	res := w.Result()
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	assert.Equal(t, "application/json", res.Header.Get("content-type"))
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"field":"url","code":"scheme_not_allowed","message":"original url scheme must be http or https"}`, string(body))
+}
+
+func TestShortenJSONHandlerCustomAlias(t *testing.T) {
+	err := logger.Init(slog.LevelInfo)
+	require.NoError(t, err)
+	handler, err := getHandler()
+	require.NoError(t, err)
+	tests := []struct {
+		name           string
+		reqBody        []byte
+		expectedResult string
+		expectedStatus int
+	}{
+		{
+			name:           "created",
+			reqBody:        []byte(`{"url":"https://practicum.yandex.ru/","custom_alias":"spring-sale"}`),
+			expectedResult: config.GetDefault().BaseReturnURL + "/spring-sale",
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "already taken",
+			reqBody:        []byte(`{"url":"https://yandex.ru/","custom_alias":"spring-sale"}`),
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "reserved",
+			reqBody:        []byte(`{"url":"https://yandex.ru/","custom_alias":"ping"}`),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "reserved metrics path",
+			reqBody:        []byte(`{"url":"https://yandex.ru/","custom_alias":"Metrics"}`),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid characters",
+			reqBody:        []byte(`{"url":"https://yandex.ru/","custom_alias":"spring sale"}`),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "too short",
+			reqBody:        []byte(`{"url":"https://yandex.ru/","custom_alias":"ab"}`),
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodPost, "/api/shorten", bytes.NewReader(test.reqBody))
+			request.Header.Set("content-type", "application/json")
+			w := httptest.NewRecorder()
+			handler.ShortenJSONHandler(w, request)
+			res := w.Result()
+			defer res.Body.Close()
+			statusValid := assert.Equal(t, test.expectedStatus, res.StatusCode)
+			if statusValid && test.expectedStatus == http.StatusCreated {
+				var resModel models.Response
+				err := json.NewDecoder(res.Body).Decode(&resModel)
+				require.NoError(t, err)
+				assert.Equal(t, test.expectedResult, resModel.Result)
+			}
+		})
+	}
+}
+
 func TestGzipCompression(t *testing.T) {
 	err := logger.Init(slog.LevelInfo)
 	require.NoError(t, err)
@@ -192,7 +640,7 @@ func TestGzipCompression(t *testing.T) {
 	require.NoError(t, err)
 	service, err := service.NewShortenerService(ctx, config, storage)
 	require.NoError(t, err)
-	handler := NewShortenerHandler(config, service)
+	handler := NewShortenerHandler(config, service, nil)
 	require.NoError(t, err)
 	gzipM := gzipreq.NewCompressionMiddleware()
 	gzipH := gzipM.Compression(http.HandlerFunc(handler.ShortenJSONHandler))
@@ -239,7 +687,7 @@ func TestGzipDecompression(t *testing.T) {
 	require.NoError(t, err)
 	service, err := service.NewShortenerService(ctx, config, storage)
 	require.NoError(t, err)
-	handler := NewShortenerHandler(config, service)
+	handler := NewShortenerHandler(config, service, nil)
 	require.NoError(t, err)
 	gzipM := gzipreq.NewCompressionMiddleware()
 	gzipH := gzipM.Compression(http.HandlerFunc(handler.ShortenJSONHandler))
@@ -282,7 +730,7 @@ func TestShortenJSONBatchHandler(t *testing.T) {
 	require.NoError(t, err)
 	service, err := service.NewShortenerService(ctx, config, storage)
 	require.NoError(t, err)
-	handler := NewShortenerHandler(config, service)
+	handler := NewShortenerHandler(config, service, nil)
 	require.NoError(t, err)
 	tests := []struct {
 		name            string
@@ -297,11 +745,27 @@ func TestShortenJSONBatchHandler(t *testing.T) {
     					{"correlation_id":"4cb58319-4431-496b-b193-e68006a3bc2c","original_url":"https://habr.com/ru/companies/nixys/articles/461723/"}
 			]`),
 			expectedResBody: `[
-				{"correlation_id":"59080686-9e69-4a5b-a8df-9d0b30c14131","short_url":"%s"},
-				{"correlation_id":"4cb58319-4431-496b-b193-e68006a3bc2c","short_url":"%s"}
+				{"correlation_id":"59080686-9e69-4a5b-a8df-9d0b30c14131","short_url":"%s","status":"created"},
+				{"correlation_id":"4cb58319-4431-496b-b193-e68006a3bc2c","short_url":"%s","status":"created"}
 			]`,
 			expectedStatus: 201,
 		},
+		{
+			name: "partial success",
+			reqBody: []byte(`[
+						{"correlation_id":"1","original_url":"https://uptrace.dev/blog/"},
+						{"correlation_id":"2","original_url":""},
+						{"correlation_id":"3","original_url":"https://habr.com/","ttl":-1},
+						{"correlation_id":"4","original_url":"https://uptrace.dev/blog/","dedupe":true}
+			]`),
+			expectedResBody: `[
+				{"correlation_id":"1","short_url":"%s","status":"created"},
+				{"correlation_id":"2","status":"invalid","error":"original url is empty"},
+				{"correlation_id":"3","status":"invalid","error":"ttl must be positive"},
+				{"correlation_id":"4","short_url":"%s","status":"existing"}
+			]`,
+			expectedStatus: 207,
+		},
 		{
 			name:           "test#3",
 			reqBody:        []byte(``),
@@ -317,13 +781,19 @@ func TestShortenJSONBatchHandler(t *testing.T) {
 			res := w.Result()
 			defer res.Body.Close()
 			statusValid := assert.Equal(t, test.expectedStatus, res.StatusCode)
-			if statusValid && test.expectedStatus == http.StatusCreated {
+			if statusValid && (test.expectedStatus == http.StatusCreated || test.expectedStatus == http.StatusMultiStatus) {
 				var urls []models.ShortURLInfoBatch
 				body, err := io.ReadAll(res.Body)
 				require.NoError(t, err)
 				err = json.Unmarshal(body, &urls)
 				require.NoError(t, err)
-				test.expectedResBody = fmt.Sprintf(test.expectedResBody, urls[0].ShortURL, urls[1].ShortURL)
+				var shortURLs []any
+				for _, url := range urls {
+					if url.ShortURL != "" {
+						shortURLs = append(shortURLs, url.ShortURL)
+					}
+				}
+				test.expectedResBody = fmt.Sprintf(test.expectedResBody, shortURLs...)
 				assert.JSONEq(t, test.expectedResBody, string(body))
 			}
 		})
@@ -412,6 +882,171 @@ func getHandler() (*shortenerHandler, error) {
 	if err != nil {
 		return nil, err
 	}
-	handler := NewShortenerHandler(config, service)
+	handler := NewShortenerHandler(config, service, nil)
 	return handler, nil
 }
+
+func TestHealthHandlers(t *testing.T) {
+	require.NoError(t, logger.Init(slog.LevelInfo))
+	config := config.GetDefault()
+	config.JWTKeys = "k1:secret"
+	storage, err := storage.NewShortenerStorage(storage.GetStorageTypeByConfig(config), config)
+	require.NoError(t, err)
+	service, err := service.NewShortenerService(context.Background(), config, storage)
+	require.NoError(t, err)
+	tokens, err := token.NewManager(config)
+	require.NoError(t, err)
+	checker := health.NewChecker(time.Second, health.Component{Name: "storage", Check: func(ctx context.Context) error {
+		if !service.PingStorage(ctx) {
+			return errors.New("storage is unavailable")
+		}
+		return nil
+	}})
+	handler := NewServer(config, service, tokens, nil, nil, checker).Handler
+
+	for path, wantCode := range map[string]int{"/healthz": http.StatusOK, "/readyz": http.StatusOK} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, wantCode, w.Code, path)
+		assert.Empty(t, w.Header().Get("Set-Cookie"), "health checks must not register users")
+	}
+	stats, err := storage.GetStats(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, stats.Users)
+
+	checker.SetShuttingDown()
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	var report health.Report
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, health.StatusShuttingDown, report.Status)
+	assert.Equal(t, health.ComponentUp, report.Components["storage"].Status)
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, w.Code, "shutting down server is still alive")
+}
+
+func TestAnonymousRequestsDoNotRegisterUsers(t *testing.T) {
+	require.NoError(t, logger.Init(slog.LevelInfo))
+	config := config.GetDefault()
+	config.JWTKeys = "k1:secret"
+	storage, err := storage.NewShortenerStorage(storage.GetStorageTypeByConfig(config), config)
+	require.NoError(t, err)
+	service, err := service.NewShortenerService(context.Background(), config, storage)
+	require.NoError(t, err)
+	tokens, err := token.NewManager(config)
+	require.NoError(t, err)
+	handler := NewServer(config, service, tokens, nil, nil, nil).Handler
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader("https://example.com")))
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.Len(t, w.Result().Cookies(), 1, "creating a url registers the owner")
+	shortURL := strings.TrimPrefix(w.Body.String(), config.BaseReturnURL+"/")
+
+	for _, path := range []string{"/" + shortURL, "/ping", "/api/user/urls"} {
+		w = httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Empty(t, w.Result().Cookies(), path)
+	}
+	stats, err := storage.GetStats(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.Users)
+}
+
+func TestAdminHandlers(t *testing.T) {
+	require.NoError(t, logger.Init(slog.LevelInfo))
+	config := config.GetDefault()
+	config.JWTKeys = "k1:secret"
+	config.TrustedSubnet = "10.0.0.0/8"
+	config.AdminToken = "admin-secret"
+	ctx := context.Background()
+	storage, err := storage.NewShortenerStorage(storage.GetStorageTypeByConfig(config), config)
+	require.NoError(t, err)
+	service, err := service.NewShortenerService(ctx, config, storage)
+	require.NoError(t, err)
+	tokens, err := token.NewManager(config)
+	require.NoError(t, err)
+	owner, err := storage.CreateUser(ctx)
+	require.NoError(t, err)
+	other, err := storage.CreateUser(ctx)
+	require.NoError(t, err)
+	for _, url := range []models.URL{
+		{ShortURL: "abuse", OriginalURL: "https://evil.example.com/x", CreatedBy: owner.ID},
+		{ShortURL: "good", OriginalURL: "https://good.org", CreatedBy: owner.ID},
+		{ShortURL: "other", OriginalURL: "https://example.com/y", CreatedBy: other.ID},
+	} {
+		require.NoError(t, storage.Save(ctx, url))
+	}
+	handler := NewServer(config, service, tokens, nil, nil, nil).Handler
+
+	do := func(method, path, body string, headers map[string]string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(method, path, strings.NewReader(body))
+		for key, value := range headers {
+			request.Header.Set(key, value)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, request)
+		return w
+	}
+	admin := map[string]string{"X-Real-IP": "10.1.2.3", "X-Admin-Token": "admin-secret"}
+	decodeURL := func(w *httptest.ResponseRecorder) models.AdminURL {
+		var url models.AdminURL
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &url))
+		return url
+	}
+
+	w := do(http.MethodGet, "/api/admin/urls/abuse", "", map[string]string{"X-Real-IP": "10.1.2.3"})
+	assert.Equal(t, http.StatusUnauthorized, w.Code, "request without admin token")
+	w = do(http.MethodGet, "/api/admin/urls/abuse", "", map[string]string{"X-Real-IP": "192.168.0.1", "X-Admin-Token": "admin-secret"})
+	assert.Equal(t, http.StatusForbidden, w.Code, "request from untrusted subnet")
+
+	w = do(http.MethodGet, "/api/admin/urls/abuse", "", admin)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Set-Cookie"), "admin requests must not register users")
+	url := decodeURL(w)
+	assert.Equal(t, owner.ID, url.UserID)
+	assert.Equal(t, "https://evil.example.com/x", url.OriginalURL)
+	assert.False(t, url.IsDisabled)
+	w = do(http.MethodGet, "/api/admin/urls/unknown", "", admin)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = do(http.MethodPost, "/api/admin/urls/abuse/disable", "", admin)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, decodeURL(w).IsDisabled)
+	assert.Equal(t, http.StatusGone, do(http.MethodGet, "/abuse", "", nil).Code)
+	w = do(http.MethodPost, "/api/admin/urls/abuse/enable", "", admin)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.False(t, decodeURL(w).IsDisabled)
+	assert.Equal(t, http.StatusTemporaryRedirect, do(http.MethodGet, "/abuse", "", nil).Code)
+
+	w = do(http.MethodPut, "/api/admin/urls/abuse/owner", fmt.Sprintf(`{"user_id":%d}`, other.ID), admin)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, other.ID, decodeURL(w).UserID)
+	w = do(http.MethodPut, "/api/admin/urls/abuse/owner", `{"user_id":1000}`, admin)
+	assert.Equal(t, http.StatusNotFound, w.Code, "unknown user")
+
+	w = do(http.MethodGet, fmt.Sprintf("/api/admin/users/%d/urls?sort=short_url", other.ID), "", admin)
+	require.Equal(t, http.StatusOK, w.Code)
+	var urls []models.AdminURL
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &urls))
+	require.Len(t, urls, 2)
+	assert.Equal(t, "abuse", urls[0].ShortURL)
+	assert.Equal(t, "other", urls[1].ShortURL)
+
+	w = do(http.MethodDelete, "/api/admin/domains/Example.COM/urls", "", admin)
+	require.Equal(t, http.StatusOK, w.Code)
+	var result models.AdminDeleteResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, models.AdminDeleteResult{Deleted: 2, ShortURLs: []string{"abuse", "other"}}, result)
+	assert.Equal(t, http.StatusGone, do(http.MethodGet, "/other", "", nil).Code)
+
+	w = do(http.MethodDelete, fmt.Sprintf("/api/admin/users/%d/urls", owner.ID), "", admin)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, models.AdminDeleteResult{Deleted: 1, ShortURLs: []string{"good"}}, result)
+	w = do(http.MethodDelete, "/api/admin/domains/evil.com%2Fx/urls", "", admin)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,40 @@
+// Пакет admin предоставляет middleware, который открывает доступ к административному API только по токену администратора.
+package admin
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/config"
+)
+
+// TokenHeader заголовок запроса с токеном администратора.
+const TokenHeader = "X-Admin-Token"
+
+// AdminMiddleware представляет middleware, который проверяет токен администратора в заголовке TokenHeader.
+// Если токен администратора не задан в конфигурации, административное API отключено и запрос отклоняется с кодом "Forbidden".
+// Если токен в запросе отсутствует или не совпадает, запрос отклоняется с кодом "Unauthorized".
+type AdminMiddleware struct {
+	token []byte
+}
+
+// NewAdminMiddleware создает новый экземпляр AdminMiddleware с токеном администратора из конфигурации.
+func NewAdminMiddleware(config config.Config) *AdminMiddleware {
+	return &AdminMiddleware{token: []byte(config.AdminToken)}
+}
+
+// Admin проверяет токен администратора и вызывает следующий обработчик, только если токен совпадает.
+// Токены сравниваются за постоянное время, чтобы время ответа не раскрывало токен.
+func (m *AdminMiddleware) Admin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(m.token) == 0 {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get(TokenHeader)), m.token) != 1 {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,40 @@
+package admin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdmin(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	tests := []struct {
+		name         string
+		adminToken   string
+		requestToken string
+		expected     int
+	}{
+		{name: "valid token", adminToken: "secret", requestToken: "secret", expected: http.StatusOK},
+		{name: "invalid token", adminToken: "secret", requestToken: "secret2", expected: http.StatusUnauthorized},
+		{name: "no token", adminToken: "secret", expected: http.StatusUnauthorized},
+		{name: "admin api disabled", expected: http.StatusForbidden},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := config.GetDefault()
+			config.AdminToken = test.adminToken
+			request := httptest.NewRequest(http.MethodGet, "/api/admin/urls/abc", nil)
+			if test.requestToken != "" {
+				request.Header.Set(TokenHeader, test.requestToken)
+			}
+			rr := httptest.NewRecorder()
+			NewAdminMiddleware(config).Admin(ok).ServeHTTP(rr, request)
+			assert.Equal(t, test.expected, rr.Code)
+		})
+	}
+}
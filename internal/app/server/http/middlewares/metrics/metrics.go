@@ -0,0 +1,75 @@
+// Package metrics предоставляет middleware, который собирает метрики HTTP-запросов:
+// количество запросов и их длительность по методу и шаблону маршрута chi.
+//
+// Шаблон маршрута, а не путь запроса, используется как метка, чтобы количество рядов метрик
+// не зависело от количества сокращенных URL.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/metrics"
+	"github.com/go-chi/chi/v5"
+)
+
+// unmatchedRoute значение метки route для запросов, которые не совпали ни с одним маршрутом.
+const unmatchedRoute = "unmatched"
+
+type metricsMiddleware struct{}
+
+// NewMetricsMiddleware создает новый экземпляр middleware метрик HTTP-запросов.
+func NewMetricsMiddleware() *metricsMiddleware {
+	return &metricsMiddleware{}
+}
+
+// Metrics возвращает обработчик HTTP, который считает запросы и измеряет их длительность.
+// Middleware должен быть подключен к маршрутизатору chi, чтобы шаблон маршрута был известен после обработки запроса.
+func (*metricsMiddleware) Metrics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		sw := &statusWriter{ResponseWriter: w}
+		next.ServeHTTP(sw, r)
+		route := unmatchedRoute
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+			route = rctx.RoutePattern()
+		}
+		metrics.HTTPRequests.With(r.Method, route, strconv.Itoa(sw.code())).Inc()
+		metrics.HTTPRequestDuration.With(r.Method, route).Observe(time.Since(start).Seconds())
+	})
+}
+
+// statusWriter запоминает код ответа.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader записывает код ответа.
+func (w *statusWriter) WriteHeader(statusCode int) {
+	if w.status == 0 {
+		w.status = statusCode
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write записывает тело ответа. Если код ответа не был записан, он равен 200.
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap возвращает исходный http.ResponseWriter для http.ResponseController.
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *statusWriter) code() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
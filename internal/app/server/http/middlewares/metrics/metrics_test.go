@@ -0,0 +1,31 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/metrics"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	r := chi.NewRouter()
+	r.Use(NewMetricsMiddleware().Metrics)
+	r.Get("/{shorturl}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTemporaryRedirect)
+	})
+	found := metrics.HTTPRequests.With(http.MethodGet, "/{shorturl}", "307")
+	notFound := metrics.HTTPRequests.With(http.MethodGet, unmatchedRoute, "404")
+	duration := metrics.HTTPRequestDuration.With(http.MethodGet, "/{shorturl}")
+	foundBefore, notFoundBefore, durationBefore := found.Value(), notFound.Value(), duration.Count()
+
+	for _, path := range []string{"/abc", "/def", "/a/b"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	assert.Equal(t, foundBefore+2, found.Value(), "requests must be counted by route pattern, not path")
+	assert.Equal(t, notFoundBefore+1, notFound.Value())
+	assert.Equal(t, durationBefore+2, duration.Count())
+}
@@ -0,0 +1,62 @@
+// Package ratelimit предоставляет middleware, который ограничивает частоту HTTP-запросов клиента.
+package ratelimit
+
+import (
+	"net/http"
+
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/clientip"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/models"
+	ratelimiter "github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/ratelimit"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/token"
+)
+
+// RateLimitMiddleware ограничивает частоту запросов клиента к группе маршрутов.
+// Клиент определяется по пользователю из JWT в cookie, а если cookie нет или токен недействителен — по IP-адресу,
+// который учитывает заголовки прокси, только если запрос пришел от доверенного прокси.
+// Каждый ответ ограниченной группы содержит заголовки RateLimit-*, отклоненный запрос получает
+// ответ 429 (слишком много запросов) с заголовком Retry-After.
+type RateLimitMiddleware struct {
+	limiters *ratelimiter.Limiters
+	tokens   *token.Manager
+	clients  *clientip.Resolver
+}
+
+// NewRateLimitMiddleware создает новый экземпляр RateLimitMiddleware. Если limiters равен nil, запросы не ограничиваются.
+func NewRateLimitMiddleware(limiters *ratelimiter.Limiters, tokens *token.Manager, clients *clientip.Resolver) *RateLimitMiddleware {
+	return &RateLimitMiddleware{
+		limiters: limiters,
+		tokens:   tokens,
+		clients:  clients,
+	}
+}
+
+// RateLimit возвращает middleware, который ограничивает частоту запросов к группе маршрутов group.
+func (m *RateLimitMiddleware) RateLimit(group string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			result, limited := m.limiters.Allow(group, m.clientKey(r))
+			if !limited {
+				next.ServeHTTP(w, r)
+				return
+			}
+			for name, value := range result.Headers() {
+				w.Header().Set(name, value)
+			}
+			if !result.Allowed {
+				http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// clientKey возвращает ключ клиента: пользователь из действительного JWT или IP-адрес клиента.
+func (m *RateLimitMiddleware) clientKey(r *http.Request) string {
+	if cookie, err := r.Cookie(string(models.UserID)); err == nil && m.tokens != nil {
+		if claims, err := m.tokens.Parse(cookie.Value); err == nil {
+			return ratelimiter.UserKey(claims.UserID)
+		}
+	}
+	return ratelimiter.IPKey(m.clients.FromRequest(r))
+}
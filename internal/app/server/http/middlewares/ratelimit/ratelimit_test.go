@@ -0,0 +1,83 @@
+package ratelimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/clientip"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/config"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/models"
+	ratelimiter "github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/ratelimit"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/token"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimit(t *testing.T) {
+	config := config.GetDefault()
+	config.JWTKeys = "k1:secret"
+	config.RateLimitCreate = "2/1m"
+	clients, err := clientip.New("192.0.2.0/24")
+	require.NoError(t, err)
+	tokens, err := token.NewManager(config)
+	require.NoError(t, err)
+	limiters, err := ratelimiter.New(config)
+	require.NoError(t, err)
+	middleware := NewRateLimitMiddleware(limiters, tokens, clients)
+	handler := middleware.RateLimit(ratelimiter.GroupCreate)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+	userToken, _, err := tokens.Build(1)
+	require.NoError(t, err)
+
+	// запросы приходят от доверенного прокси с адресом httptest.DefaultRemoteAddr
+	do := func(ip string, cookie string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodPost, "/", nil)
+		request.Header.Add("X-Real-IP", ip)
+		if cookie != "" {
+			request.AddCookie(&http.Cookie{Name: string(models.UserID), Value: cookie})
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, request)
+		return rr
+	}
+
+	rr := do("10.0.0.1", "")
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Equal(t, "2", rr.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", rr.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, http.StatusCreated, do("10.0.0.1", "").Code)
+
+	rr = do("10.0.0.1", "")
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "30", rr.Header().Get("Retry-After"))
+	assert.Equal(t, "0", rr.Header().Get("RateLimit-Remaining"))
+
+	// Недействительный токен не дает обойти лимит IP-адреса
+	assert.Equal(t, http.StatusTooManyRequests, do("10.0.0.1", "invalid").Code)
+	// Пользователь с действительным токеном и другой IP-адрес учитываются отдельно
+	assert.Equal(t, http.StatusCreated, do("10.0.0.1", userToken).Code)
+	assert.Equal(t, http.StatusCreated, do("10.0.0.2", "").Code)
+
+	// Клиент не от доверенного прокси не может подменить IP-адрес заголовком X-Real-IP
+	direct := func(ip string) int {
+		request := httptest.NewRequest(http.MethodPost, "/", nil)
+		request.RemoteAddr = "198.51.100.1:5000"
+		request.Header.Add("X-Real-IP", ip)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, request)
+		return rr.Code
+	}
+	assert.Equal(t, http.StatusCreated, direct("10.0.0.3"))
+	assert.Equal(t, http.StatusCreated, direct("10.0.0.4"))
+	assert.Equal(t, http.StatusTooManyRequests, direct("10.0.0.5"))
+
+	// Группа без лимита не ограничивается
+	handler = middleware.RateLimit(ratelimiter.GroupExpand)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTemporaryRedirect)
+	}))
+	rr = do("10.0.0.1", "")
+	assert.Equal(t, http.StatusTemporaryRedirect, rr.Code)
+	assert.Empty(t, rr.Header().Get("RateLimit-Limit"))
+}
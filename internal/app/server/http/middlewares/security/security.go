@@ -3,96 +3,105 @@ package security
 
 import (
 	"context"
-	"errors"
 	"net/http"
 
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/logger"
 	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/models"
-	"github.com/golang-jwt/jwt/v4"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/token"
 )
 
-// Claims определяет структуру для хранения JWT.
-type Claims struct {
-	jwt.RegisteredClaims
-	UserID int
-}
-
 // ShortenerService определяет методы, необходимые для работы с сервисом сокращения URL.
 type ShortenerService interface {
-	GetUserID(context.Context) int
+	CreateUser(context.Context) (models.User, error)
 }
 
 // securityJWT определяет middleware для обеспечения безопасности с использованием JWT.
 type securityJWT struct {
 	ShortenerService
+	tokens *token.Manager
 }
 
 // NewSecurityMiddleware создает новый экземпляр middleware для обеспечения безопасности.
-func NewSecurityMiddleware(service ShortenerService) *securityJWT {
+func NewSecurityMiddleware(tokens *token.Manager, service ShortenerService) *securityJWT {
 	return &securityJWT{
-		service,
+		ShortenerService: service,
+		tokens:           tokens,
 	}
 }
 
 // RequiredUserID проверяет наличие идентификатора пользователя в запросе.
+// Идентификатор пользователя помещается в контекст запроса middleware Security.
 func (security *securityJWT) RequiredUserID(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		cookie, err := r.Cookie(string(models.UserID))
-		if err != nil {
-			w.WriteHeader(http.StatusUnauthorized)
-			return
-		}
-		userID, err := getUserIDFromToken(cookie.Value)
-		if err != nil || userID == 0 {
+		if _, ok := r.Context().Value(models.UserID).(int); !ok {
 			w.WriteHeader(http.StatusUnauthorized)
 			return
 		}
-		h.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), models.UserID, userID)))
+		h.ServeHTTP(w, r)
 	})
 }
 
 // Security обеспечивает безопасность обработки HTTP-запросов с использованием JWT.
+// Если токен действителен, идентификатор пользователя помещается в контекст запроса,
+// а если до истечения токена осталось меньше JWTRefreshBefore, токен перевыпускается для того же пользователя.
+// Если токен отсутствует или недействителен, запрос обрабатывается анонимно: пользователь регистрируется
+// только middleware RegisterUser на маршрутах, которым нужен владелец.
 func (security *securityJWT) Security(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		cookie, err := r.Cookie(string(models.UserID))
-		if errors.Is(err, http.ErrNoCookie) {
-			newUserID := security.GetUserID(r.Context())
-			token, _ := buildJWTString(newUserID)
-			cookie = &http.Cookie{
-				Name:  string(models.UserID),
-				Value: token,
+		if err != nil {
+			h.ServeHTTP(w, r)
+			return
+		}
+		claims, err := security.tokens.Parse(cookie.Value)
+		if err != nil {
+			logger.Logger.Info("invalid jwt", "error", err.Error())
+			h.ServeHTTP(w, r)
+			return
+		}
+		if security.tokens.ShouldRefresh(claims) {
+			if err := security.setTokenCookie(w, claims.UserID); err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
 			}
-			http.SetCookie(w, cookie)
 		}
-		userID, err := getUserIDFromToken(cookie.Value)
+		h.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), models.UserID, claims.UserID)))
+	})
+}
+
+// RegisterUser регистрирует нового пользователя и выдает ему токен, если запрос анонимный.
+// Используется после Security на маршрутах, которым нужен владелец, — при создании сокращенных URL.
+func (security *securityJWT) RegisterUser(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.Context().Value(models.UserID).(int); ok {
+			h.ServeHTTP(w, r)
+			return
+		}
+		user, err := security.CreateUser(r.Context())
 		if err != nil {
+			logger.Logger.Error("failed to create user", "error", err.Error())
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
-		h.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), models.UserID, userID)))
+		if err := security.setTokenCookie(w, user.ID); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		h.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), models.UserID, user.ID)))
 	})
 }
 
-func buildJWTString(userID int) (string, error) {
-	token := jwt.NewWithClaims(
-		jwt.SigningMethodHS256, &Claims{
-			RegisteredClaims: jwt.RegisteredClaims{},
-			UserID:           userID,
-		},
-	)
-	tokenString, err := token.SignedString([]byte("secretkey"))
+func (security *securityJWT) setTokenCookie(w http.ResponseWriter, userID int) error {
+	token, expiresAt, err := security.tokens.Build(userID)
 	if err != nil {
-		return "", err
+		return err
 	}
-	return tokenString, nil
-}
-
-func getUserIDFromToken(token string) (int, error) {
-	claims := &Claims{}
-	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
-		return []byte("secretkey"), nil
+	http.SetCookie(w, &http.Cookie{
+		Name:     string(models.UserID),
+		Value:    token,
+		Path:     "/",
+		Expires:  expiresAt,
+		HttpOnly: true,
 	})
-	if err != nil {
-		return 0, err
-	}
-	return claims.UserID, nil
+	return nil
 }
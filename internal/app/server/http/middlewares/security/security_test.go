@@ -2,20 +2,38 @@ package security
 
 import (
 	"context"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
+
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/config"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/logger"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/models"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/token"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type mockShortenerService struct{}
 
-func (m *mockShortenerService) GetUserID(context.Context) int {
-	return 666
+func (m *mockShortenerService) CreateUser(context.Context) (models.User, error) {
+	return models.User{ID: 666}, nil
+}
+
+func newTestTokens(t *testing.T, keys string, ttl time.Duration) *token.Manager {
+	config := config.GetDefault()
+	config.JWTKeys = keys
+	config.JWTTTL.Duration = ttl
+	tokens, err := token.NewManager(config)
+	require.NoError(t, err)
+	return tokens
 }
 
 func TestRequiredUserID(t *testing.T) {
 	service := &mockShortenerService{}
-	securityMiddleware := NewSecurityMiddleware(service)
+	securityMiddleware := NewSecurityMiddleware(newTestTokens(t, "k1:secret", 0), service)
 
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
 
@@ -31,9 +49,12 @@ func TestRequiredUserID(t *testing.T) {
 
 func TestSecurity(t *testing.T) {
 	service := &mockShortenerService{}
-	securityMiddleware := NewSecurityMiddleware(service)
+	securityMiddleware := NewSecurityMiddleware(newTestTokens(t, "k1:secret", 0), service)
 
-	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	var userID any
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID = r.Context().Value(models.UserID)
+	})
 
 	req := httptest.NewRequest("GET", "/", nil)
 	rr := httptest.NewRecorder()
@@ -43,4 +64,95 @@ func TestSecurity(t *testing.T) {
 	if rr.Code != http.StatusOK {
 		t.Errorf("Expected status %d, got %d", http.StatusOK, rr.Code)
 	}
+	// анонимный запрос не регистрирует пользователя
+	assert.Nil(t, userID)
+	assert.Empty(t, rr.Result().Cookies())
+}
+
+func TestRegisterUser(t *testing.T) {
+	service := &mockShortenerService{}
+	tokens := newTestTokens(t, "k1:secret", 0)
+	securityMiddleware := NewSecurityMiddleware(tokens, service)
+
+	var userID int
+	handler := securityMiddleware.Security(securityMiddleware.RegisterUser(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID, _ = r.Context().Value(models.UserID).(int)
+	})))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("POST", "/", nil))
+	assert.Equal(t, 666, userID)
+	cookies := rr.Result().Cookies()
+	require.Len(t, cookies, 1)
+	claims, err := tokens.Parse(cookies[0].Value)
+	require.NoError(t, err)
+	assert.Equal(t, 666, claims.UserID)
+
+	value, _, err := tokens.Build(1)
+	require.NoError(t, err)
+	req := httptest.NewRequest("POST", "/", nil)
+	req.AddCookie(&http.Cookie{Name: string(models.UserID), Value: value})
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, 1, userID, "authenticated user must not be registered again")
+	assert.Empty(t, rr.Result().Cookies())
+}
+
+func TestSecurityToken(t *testing.T) {
+	err := logger.Init(slog.LevelInfo)
+	require.NoError(t, err)
+	service := &mockShortenerService{}
+	tokens := newTestTokens(t, "new:new-secret,old:old-secret", 0)
+	securityMiddleware := NewSecurityMiddleware(tokens, service)
+
+	tests := []struct {
+		name            string
+		tokens          *token.Manager
+		expectedUserID  int
+		expectNewCookie bool
+	}{
+		{
+			name:           "valid token signed with rotated key",
+			tokens:         newTestTokens(t, "old:old-secret", 0),
+			expectedUserID: 1,
+		},
+		{
+			name:            "token close to expiration is reissued",
+			tokens:          newTestTokens(t, "new:new-secret", time.Hour),
+			expectedUserID:  1,
+			expectNewCookie: true,
+		},
+		{
+			name:   "token signed with unknown key is treated as anonymous",
+			tokens: newTestTokens(t, "other:other-secret", 0),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var userID int
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				userID, _ = r.Context().Value(models.UserID).(int)
+			})
+			value, _, err := test.tokens.Build(1)
+			require.NoError(t, err)
+			req := httptest.NewRequest("GET", "/", nil)
+			req.AddCookie(&http.Cookie{Name: string(models.UserID), Value: value})
+			rr := httptest.NewRecorder()
+
+			securityMiddleware.Security(handler).ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.Equal(t, test.expectedUserID, userID)
+			cookies := rr.Result().Cookies()
+			if !test.expectNewCookie {
+				assert.Empty(t, cookies)
+				return
+			}
+			require.Len(t, cookies, 1)
+			claims, err := tokens.Parse(cookies[0].Value)
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedUserID, claims.UserID)
+			assert.False(t, tokens.ShouldRefresh(claims))
+		})
+	}
 }
@@ -0,0 +1,96 @@
+// Package tracing предоставляет middleware, который создает серверный спан для каждого HTTP-запроса.
+//
+// Контекст трассировки клиента принимается из заголовка W3C traceparent, а контекст созданного спана
+// возвращается клиенту в том же заголовке ответа, чтобы запрос можно было найти в трассировке.
+package tracing
+
+import (
+	"net/http"
+
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/tracing"
+	"github.com/go-chi/chi/v5"
+)
+
+// traceparentHeader заголовок W3C с контекстом трассировки.
+const traceparentHeader = "traceparent"
+
+type tracingMiddleware struct{}
+
+// NewTracingMiddleware создает новый экземпляр middleware трассировки HTTP-запросов.
+func NewTracingMiddleware() *tracingMiddleware {
+	return &tracingMiddleware{}
+}
+
+// Tracing возвращает обработчик HTTP, который обрабатывает запрос в серверном спане.
+// Спан называется по методу и шаблону маршрута chi, который становится известен после маршрутизации.
+func (*tracingMiddleware) Tracing(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if sc, ok := tracing.ParseTraceparent(r.Header.Get(traceparentHeader)); ok {
+			ctx = tracing.ContextWithSpanContext(ctx, sc)
+		}
+		ctx, span := tracing.StartKind(ctx, tracing.KindServer, r.Method,
+			tracing.String("http.request.method", r.Method),
+			tracing.String("url.path", r.URL.Path),
+		)
+		if span == nil {
+			next.ServeHTTP(w, r.WithContext(ctx))
+			return
+		}
+		defer span.End()
+		w.Header().Set(traceparentHeader, span.SpanContext().Traceparent())
+		sw := &statusWriter{ResponseWriter: w}
+		next.ServeHTTP(sw, r.WithContext(ctx))
+		if rctx := chi.RouteContext(ctx); rctx != nil && rctx.RoutePattern() != "" {
+			span.SetName(r.Method + " " + rctx.RoutePattern())
+			span.SetAttributes(tracing.String("http.route", rctx.RoutePattern()))
+		}
+		status := sw.code()
+		span.SetAttributes(tracing.Int("http.response.status_code", status))
+		if status >= http.StatusInternalServerError {
+			span.RecordError(errorStatus(status))
+		}
+	})
+}
+
+// errorStatus ошибка серверного спана по коду ответа.
+type errorStatus int
+
+// Error возвращает текст кода ответа.
+func (status errorStatus) Error() string {
+	return http.StatusText(int(status))
+}
+
+// statusWriter запоминает код ответа.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader записывает код ответа.
+func (w *statusWriter) WriteHeader(statusCode int) {
+	if w.status == 0 {
+		w.status = statusCode
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write записывает тело ответа. Если код ответа не был записан, он равен 200.
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap возвращает исходный http.ResponseWriter для http.ResponseController.
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *statusWriter) code() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
@@ -0,0 +1,65 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/tracing"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingExporter struct {
+	mu    sync.Mutex
+	spans []tracing.SpanData
+}
+
+func (exporter *recordingExporter) Export(_ context.Context, spans []tracing.SpanData) error {
+	exporter.mu.Lock()
+	defer exporter.mu.Unlock()
+	exporter.spans = append(exporter.spans, spans...)
+	return nil
+}
+
+func (exporter *recordingExporter) Shutdown(context.Context) error {
+	return nil
+}
+
+func TestTracing(t *testing.T) {
+	exporter := &recordingExporter{}
+	tracer := tracing.NewTracer(exporter, nil)
+	tracing.SetTracer(tracer)
+	defer tracing.SetTracer(nil)
+
+	var handlerSpan tracing.SpanContext
+	r := chi.NewRouter()
+	r.Use(NewTracingMiddleware().Tracing)
+	r.Get("/{shorturl}", func(w http.ResponseWriter, r *http.Request) {
+		handlerSpan = tracing.SpanContextFromContext(r.Context())
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/abc", nil)
+	req.Header.Set(traceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	response, ok := tracing.ParseTraceparent(w.Header().Get(traceparentHeader))
+	require.True(t, ok, "response must carry traceparent")
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", response.TraceID.String())
+	assert.Equal(t, handlerSpan.SpanID, response.SpanID, "handler must run in the server span")
+
+	require.NoError(t, tracer.Flush(context.Background()))
+	require.Len(t, exporter.spans, 1)
+	span := exporter.spans[0]
+	assert.Equal(t, "GET /{shorturl}", span.Name)
+	assert.Equal(t, tracing.KindServer, span.Kind)
+	assert.Equal(t, "00f067aa0ba902b7", span.ParentSpanID.String())
+	assert.Contains(t, span.Attributes, tracing.String("http.route", "/{shorturl}"))
+	assert.Contains(t, span.Attributes, tracing.Int("http.response.status_code", http.StatusInternalServerError))
+	assert.Equal(t, http.StatusText(http.StatusInternalServerError), span.Error)
+}
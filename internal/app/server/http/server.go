@@ -5,38 +5,31 @@
 //
 // Пример использования:
 //
-//	import "context"
-//	import "github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/config"
-//	import "github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/server"
-//
-//	func main() {
-//		ctx := context.Background()
-//		config := config.GetDefaultConfig() // Получение конфигурации приложения
-//		err := server.StartServer(ctx, config)
-//		if err != nil {
-//			log.Fatal("Server startup failed: ", err)
-//		}
+//	srv := http.NewServer(config, service, tokens, limiters, clients, checker)
+//	if err := srv.ListenAndServe(); err != nil {
+//		log.Fatal("Server startup failed: ", err)
 //	}
+//
+// Запуск HTTP и gRPC серверов вместе выполняет пакет server.
 package http
 
 import (
-	"context"
-	"log"
-	"log/slog"
 	"net/http"
-	"os"
-	"os/signal"
-	"sync"
-	"syscall"
 
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/clientip"
 	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/config"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/health"
 	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/logger"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/metrics"
+	ratelimiter "github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/ratelimit"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/server/http/middlewares/admin"
 	gzipreq "github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/server/http/middlewares/gzip"
+	httpmetrics "github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/server/http/middlewares/metrics"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/server/http/middlewares/ratelimit"
 	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/server/http/middlewares/security"
+	httptracing "github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/server/http/middlewares/tracing"
 	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/server/http/middlewares/trustedsubnet"
-
-	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/service"
-	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/storage"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/token"
 	"github.com/go-chi/chi/middleware"
 	"github.com/go-chi/chi/v5"
 )
@@ -57,16 +50,42 @@ type ShortenerHandler interface {
 	UrlsByUserHandler(res http.ResponseWriter, req *http.Request)
 	// DeleteUrlsHandler обрабатывает запрос на удаление списка URL, созданных пользователем.
 	DeleteUrlsHandler(res http.ResponseWriter, req *http.Request)
+	// RestoreUrlsHandler обрабатывает запрос на восстановление удаленных URL, созданных пользователем.
+	RestoreUrlsHandler(res http.ResponseWriter, req *http.Request)
+	// DeletionJobHandler обрабатывает запрос на получение состояния задания на удаление.
+	DeletionJobHandler(res http.ResponseWriter, req *http.Request)
+	// URLStatsHandler обрабатывает запрос на получение статистики переходов по URL, созданному пользователем.
+	URLStatsHandler(res http.ResponseWriter, req *http.Request)
 	// StatsHandler возвращающий в ответ объект статистики
 	StatsHandler(res http.ResponseWriter, req *http.Request)
 }
 
+// AdminHandler определяет методы для обработки запросов административного API.
+type AdminHandler interface {
+	// AdminURLHandler обрабатывает запрос на получение любого URL с владельцем и служебными данными.
+	AdminURLHandler(res http.ResponseWriter, req *http.Request)
+	// AdminDisableURLHandler обрабатывает запрос на отключение URL.
+	AdminDisableURLHandler(res http.ResponseWriter, req *http.Request)
+	// AdminEnableURLHandler обрабатывает запрос на включение URL.
+	AdminEnableURLHandler(res http.ResponseWriter, req *http.Request)
+	// AdminURLOwnerHandler обрабатывает запрос на передачу URL другому пользователю.
+	AdminURLOwnerHandler(res http.ResponseWriter, req *http.Request)
+	// AdminUrlsByUserHandler обрабатывает запрос на получение списка URL пользователя.
+	AdminUrlsByUserHandler(res http.ResponseWriter, req *http.Request)
+	// AdminDeleteUserUrlsHandler обрабатывает запрос на удаление всех URL пользователя.
+	AdminDeleteUserUrlsHandler(res http.ResponseWriter, req *http.Request)
+	// AdminDeleteDomainUrlsHandler обрабатывает запрос на удаление всех URL на домен.
+	AdminDeleteDomainUrlsHandler(res http.ResponseWriter, req *http.Request)
+}
+
 // SecurityMiddleware определяет middleware для обеспечения безопасности.
 type SecurityMiddleware interface {
 	// RequiredUserID проверяет наличие идентификатора пользователя в запросе.
 	RequiredUserID(h http.Handler) http.Handler
 	// Security обеспечивает безопасность обработки HTTP-запросов.
 	Security(h http.Handler) http.Handler
+	// RegisterUser регистрирует нового пользователя для анонимного запроса.
+	RegisterUser(h http.Handler) http.Handler
 }
 
 // CompressionMiddleware определяет middleware для decode/encode HTTP-ответов
@@ -82,95 +101,153 @@ type TrustedSubnetMiddleware interface {
 	TrustedSubnet(h http.Handler) http.Handler
 }
 
-// StartServer запускает веб-сервер для обработки http запросов.
-// Он инициализирует необходимые хранилища, сервисы и middleware, а также определяет маршруты для хендлеров.
-func StartServer(ctx context.Context, config config.Config) error {
-	if err := logger.Init(slog.LevelInfo); err != nil {
-		return err
-	}
+// AdminMiddleware определяет middleware для проверки токена администратора.
+type AdminMiddleware interface {
+	// Admin ограничивает обработку запросов по токену администратора.
+	Admin(h http.Handler) http.Handler
+}
 
-	storage, err := storage.NewShortenerStorage(storage.GetStorageTypeByConfig(config), config)
-	if err != nil {
-		return err
-	}
-	ctx, cancel := context.WithCancel(ctx)
-	var wg sync.WaitGroup
-	service, err := service.NewShortenerServiceWithWorkers(ctx, config, storage, &wg)
-	if err != nil {
-		cancel()
-		return err
-	}
-	compress := gzipreq.NewCompressionMiddleware()
-	security := security.NewSecurityMiddleware(service)
-	subnet := trustedsubnet.NewTrustedSubnetMiddleware(config)
-	handler := NewShortenerHandler(config, service)
+// RateLimitMiddleware определяет middleware для ограничения частоты запросов.
+type RateLimitMiddleware interface {
+	// RateLimit ограничивает частоту запросов клиента к группе маршрутов group.
+	RateLimit(group string) func(http.Handler) http.Handler
+}
+
+// MetricsMiddleware определяет middleware для сбора метрик HTTP-запросов.
+type MetricsMiddleware interface {
+	// Metrics считает запросы и измеряет их длительность по шаблону маршрута.
+	Metrics(h http.Handler) http.Handler
+}
+
+// TracingMiddleware определяет middleware для трассировки HTTP-запросов.
+type TracingMiddleware interface {
+	// Tracing обрабатывает запрос в серверном спане.
+	Tracing(h http.Handler) http.Handler
+}
+
+// Service объединяет методы сервиса, необходимые HTTP-серверу.
+type Service interface {
+	ShortenerService
+	AdminService
+	security.ShortenerService
+}
+
+// NewServer создает веб-сервер для обработки http запросов.
+// Он инициализирует middleware и определяет маршруты для хендлеров; запуск и остановка сервера выполняются вызывающей стороной.
+// Если limiters равен nil, частота запросов не ограничивается. IP-адрес клиента определяется clients с учетом доверенных прокси.
+// Если в конфигурации не задан отдельный адрес метрик MetricsAddress, метрики отдаются по /metrics из доверенной подсети.
+// Если checker не равен nil, проверки живости и готовности отдаются по /healthz и /readyz.
+// Административное API /api/admin доступно из доверенной подсети с токеном администратора AdminToken.
+func NewServer(config config.Config, service Service, tokens *token.Manager, limiters *ratelimiter.Limiters, clients *clientip.Resolver, checker *health.Checker) *http.Server {
 	handlersAndMiddlewares := handlersAndMiddlewares{
-		handler,
-		security,
-		compress,
-		subnet,
+		NewShortenerHandler(config, service, clients),
+		NewAdminHandler(service),
+		security.NewSecurityMiddleware(tokens, service),
+		gzipreq.NewCompressionMiddleware(),
+		trustedsubnet.NewTrustedSubnetMiddleware(config),
+		admin.NewAdminMiddleware(config),
+		ratelimit.NewRateLimitMiddleware(limiters, tokens, clients),
+		httpmetrics.NewMetricsMiddleware(),
+		httptracing.NewTracingMiddleware(),
+		nil,
+		checker,
 	}
-
-	mux := getMux(handlersAndMiddlewares)
-	srv := &http.Server{Handler: mux, Addr: config.ServerURL}
-	sigs := make(chan os.Signal, 1)
-	signal.Notify(sigs, syscall.SIGTERM, syscall.SIGINT, syscall.SIGQUIT)
-	idleConnsClosed := make(chan struct{})
-	go func() {
-		<-sigs
-		err := srv.Shutdown(ctx)
-		if err != nil {
-			log.Fatal(err)
-		}
-		close(idleConnsClosed)
-	}()
-
-	if config.EnableHTTPS {
-		err = srv.ListenAndServeTLS("server.crt", "server.key")
-		<-idleConnsClosed
-		cancel()
-		wg.Wait()
-		return err
+	if config.MetricsAddress == "" {
+		handlersAndMiddlewares.metrics = metrics.Default.Handler()
 	}
-	err = srv.ListenAndServe()
-	<-idleConnsClosed
-	cancel()
-	wg.Wait()
-	return err
+	return &http.Server{Handler: getMux(handlersAndMiddlewares), Addr: config.ServerURL}
 }
 
 type handlersAndMiddlewares struct {
 	ShortenerHandler
+	AdminHandler
 	SecurityMiddleware
 	CompressionMiddleware
 	TrustedSubnetMiddleware
+	AdminMiddleware
+	RateLimitMiddleware
+	MetricsMiddleware
+	TracingMiddleware
+	// metrics отдает метрики, nil если метрики отдаются на отдельном адресе.
+	metrics http.Handler
+	// health проверяет живость и готовность сервиса, nil если проверки не отдаются.
+	health *health.Checker
 }
 
 func getMux(ham handlersAndMiddlewares) *chi.Mux {
 	r := chi.NewRouter()
 
-	r.Use(ham.Security)
-	r.Use(ham.Compression)
-	r.Use(logger.RequestLogger)
+	r.Use(ham.Metrics)
+	r.Use(ham.Tracing)
 
+	// Проверки состояния обрабатываются без аутентификации и журналирования запросов:
+	// они не должны регистрировать пользователей и зависеть от хранилища.
+	if ham.health != nil {
+		r.Method(http.MethodGet, "/healthz", ham.health.LivenessHandler())
+		r.Method(http.MethodGet, "/readyz", ham.health.ReadinessHandler())
+	}
+
+	// Административное API обрабатывается без аутентификации пользователя,
+	// доступ к нему дают доверенная подсеть и токен администратора.
+	r.Route("/api/admin", func(r chi.Router) {
+		r.Use(ham.TrustedSubnet)
+		r.Use(ham.Admin)
+		r.Use(ham.Compression)
+		r.Use(logger.RequestLogger)
+		adminRoutes(r, ham)
+	})
+
+	r.Group(func(r chi.Router) {
+		r.Use(ham.Security)
+		r.Use(ham.Compression)
+		r.Use(logger.RequestLogger)
+		routes(r, ham)
+	})
+
+	return r
+}
+
+func adminRoutes(r chi.Router, ham handlersAndMiddlewares) {
+	r.Get("/urls/{short}", ham.AdminURLHandler)
+	r.Post("/urls/{short}/disable", ham.AdminDisableURLHandler)
+	r.Post("/urls/{short}/enable", ham.AdminEnableURLHandler)
+	r.Put("/urls/{short}/owner", ham.AdminURLOwnerHandler)
+	r.Get("/users/{id}/urls", ham.AdminUrlsByUserHandler)
+	r.Delete("/users/{id}/urls", ham.AdminDeleteUserUrlsHandler)
+	r.Delete("/domains/{domain}/urls", ham.AdminDeleteDomainUrlsHandler)
+}
+
+func routes(r chi.Router, ham handlersAndMiddlewares) {
 	r.Mount("/", middleware.Profiler())
 
-	r.Post("/", ham.ShortenHandler)
-	r.Get("/{shorturl}", ham.ExpandHandler)
-	r.Post("/api/shorten", ham.ShortenJSONHandler)
-	r.Post("/api/shorten/batch", ham.ShortenJSONBatchHandler)
+	// Пользователь регистрируется только при создании сокращенных URL и после ограничения частоты запросов,
+	// чтобы анонимные переходы не создавали пользователей.
+	r.Group(func(r chi.Router) {
+		r.Use(ham.RateLimit(ratelimiter.GroupCreate))
+		r.Use(ham.RegisterUser)
+		r.Post("/", ham.ShortenHandler)
+		r.Post("/api/shorten", ham.ShortenJSONHandler)
+		r.Post("/api/shorten/batch", ham.ShortenJSONBatchHandler)
+	})
+
+	r.With(ham.RateLimit(ratelimiter.GroupExpand)).Get("/{shorturl}", ham.ExpandHandler)
 	r.Get("/ping", ham.PingStorageHandler)
 
 	r.Group(func(r chi.Router) {
 		r.Use(ham.TrustedSubnet)
 		r.Get("/api/internal/stats", ham.StatsHandler)
+		if ham.metrics != nil {
+			r.Method(http.MethodGet, "/metrics", ham.metrics)
+		}
 	})
 
 	r.Group(func(r chi.Router) {
+		r.Use(ham.RateLimit(ratelimiter.GroupUser))
 		r.Use(ham.RequiredUserID)
 		r.Get("/api/user/urls", ham.UrlsByUserHandler)
 		r.Delete("/api/user/urls", ham.DeleteUrlsHandler)
+		r.Post("/api/user/urls/restore", ham.RestoreUrlsHandler)
+		r.Get("/api/user/deletions/{id}", ham.DeletionJobHandler)
+		r.Get("/api/user/urls/{short}/stats", ham.URLStatsHandler)
 	})
-
-	return r
 }
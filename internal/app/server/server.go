@@ -0,0 +1,224 @@
+// Package server запускает HTTP и gRPC серверы сервиса сокращения URL в одном процессе.
+//
+// Оба сервера используют общее хранилище, общий сервис с фоновыми workers, общий менеджер JWT
+// и общие ограничители частоты запросов.
+// По сигналу SIGTERM, SIGINT или SIGQUIT сервис сначала сообщает о неготовности в проверках готовности
+// и через ShutdownDrainDelay серверы останавливаются вместе, после чего workers дорабатывают накопленные очереди,
+// хранилище закрывается и накопленные спаны трассировки отправляются.
+//
+// Пример использования:
+//
+//	func main() {
+//		config, err := config.New()
+//		if err != nil {
+//			log.Fatal(err)
+//		}
+//		if err := server.StartServer(context.Background(), config); err != nil {
+//			log.Fatal("Server startup failed: ", err)
+//		}
+//	}
+package server
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/clientip"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/config"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/health"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/logger"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/metrics"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/ratelimit"
+	grpc_server "github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/server/grpc"
+	http_server "github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/server/http"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/service"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/storage"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/storage/cache"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/storage/instrumented"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/token"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/tracing"
+	"google.golang.org/grpc"
+)
+
+// shutdownTimeout время ожидания завершения обработки текущих запросов при остановке серверов.
+const shutdownTimeout = 30 * time.Second
+
+// StartServer запускает HTTP сервер, а также gRPC сервер, если задан GRPCServerURL, и сервер метрик, если задан MetricsAddress.
+// Функция блокируется до получения сигнала остановки, отмены ctx или ошибки одного из серверов.
+func StartServer(ctx context.Context, config config.Config) error {
+	if err := logger.Init(slog.LevelInfo); err != nil {
+		return err
+	}
+	shutdownTracing, err := tracing.Init(config, func(err error) {
+		logger.Logger.Warn("trace export error", "error", err)
+	})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			logger.Logger.Error("tracing shutdown error", "error", err)
+		}
+	}()
+	storageType := storage.GetStorageTypeByConfig(config)
+	backend, err := storage.NewShortenerStorage(storageType, config)
+	if err != nil {
+		return err
+	}
+	storage, err := cache.New(instrumented.New(backend, string(storageType)), config)
+	if err != nil {
+		backend.Close()
+		return err
+	}
+	defer func() {
+		if err := storage.Close(); err != nil {
+			logger.Logger.Error("storage close error", "error", err)
+		}
+	}()
+	registerCacheMetrics(storage)
+	tokens, err := token.NewManager(config)
+	if err != nil {
+		return err
+	}
+	limiters, err := ratelimit.New(config)
+	if err != nil {
+		return err
+	}
+	clients, err := clientip.New(config.TrustedProxies)
+	if err != nil {
+		return err
+	}
+	workersCtx, stopWorkers := context.WithCancel(context.WithoutCancel(ctx))
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer stopWorkers()
+	service, err := service.NewShortenerServiceWithWorkers(workersCtx, config, storage, &wg)
+	if err != nil {
+		return err
+	}
+
+	checker := newHealthChecker(config, storage, service.CheckDeletionWorker)
+
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGTERM, syscall.SIGINT, syscall.SIGQUIT)
+	defer stop()
+	errs := make(chan error, 3)
+
+	httpServer := http_server.NewServer(config, service, tokens, limiters, clients, checker)
+	go func() {
+		logger.Logger.Info("starting http server", "address", config.ServerURL)
+		if config.EnableHTTPS {
+			errs <- httpServer.ListenAndServeTLS("server.crt", "server.key")
+			return
+		}
+		errs <- httpServer.ListenAndServe()
+	}()
+
+	var metricsServer *http.Server
+	if config.MetricsAddress != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics.Default.Handler())
+		metricsServer = &http.Server{Handler: mux, Addr: config.MetricsAddress}
+		go func() {
+			logger.Logger.Info("starting metrics server", "address", config.MetricsAddress)
+			errs <- metricsServer.ListenAndServe()
+		}()
+	}
+
+	var grpcServer *grpc.Server
+	if config.GRPCServerURL != "" {
+		listener, err := net.Listen("tcp", config.GRPCServerURL)
+		if err != nil {
+			httpServer.Close()
+			if metricsServer != nil {
+				metricsServer.Close()
+			}
+			return err
+		}
+		grpcServer = grpc_server.NewServer(config, service, tokens, limiters, clients, checker)
+		go func() {
+			logger.Logger.Info("starting grpc server", "address", config.GRPCServerURL)
+			errs <- grpcServer.Serve(listener)
+		}()
+	}
+
+	select {
+	case <-ctx.Done():
+		logger.Logger.Info("shutting down servers")
+		err = nil
+		checker.SetShuttingDown()
+		if delay := config.ShutdownDrainDelay.Duration; delay > 0 {
+			logger.Logger.Info("waiting for load balancers to stop sending requests", "delay", delay)
+			time.Sleep(delay)
+		}
+	case err = <-errs:
+		logger.Logger.Error("server stopped", "error", err)
+		checker.SetShuttingDown()
+	}
+	return errors.Join(err, shutdown(httpServer, grpcServer, metricsServer))
+}
+
+// newHealthChecker создает проверку готовности хранилища, удаленного кеша и worker заданий на удаление.
+func newHealthChecker(config config.Config, storage *cache.Storage, checkDeletionWorker health.CheckFunc) *health.Checker {
+	return health.NewChecker(config.HealthCheckTimeout.Duration,
+		health.Component{Name: "storage", Check: func(ctx context.Context) error {
+			if !storage.Ping(ctx) {
+				return errors.New("storage is unavailable")
+			}
+			return nil
+		}},
+		health.Component{Name: "cache", Check: storage.PingCache},
+		health.Component{Name: "deletion_worker", Check: checkDeletionWorker},
+	)
+}
+
+// registerCacheMetrics регистрирует счетчики обращений к кешу редиректов.
+func registerCacheMetrics(storage *cache.Storage) {
+	metrics.Default.Register(
+		metrics.NewCounterFunc("shortener_cache_requests_total", "Total number of redirect cache lookups by layer and result.", func() []metrics.Sample {
+			m := storage.Metrics()
+			return []metrics.Sample{
+				{LabelValues: []string{"local", "hit"}, Value: float64(m.LocalHits)},
+				{LabelValues: []string{"local", "miss"}, Value: float64(m.LocalMisses)},
+				{LabelValues: []string{"remote", "hit"}, Value: float64(m.RemoteHits)},
+				{LabelValues: []string{"remote", "miss"}, Value: float64(m.RemoteMisses)},
+			}
+		}, "layer", "result"),
+		metrics.NewCounterFunc("shortener_cache_remote_errors_total", "Total number of failed remote cache calls.", func() []metrics.Sample {
+			return []metrics.Sample{{Value: float64(storage.Metrics().RemoteErrors)}}
+		}),
+	)
+}
+
+// shutdown останавливает серверы, дожидаясь завершения обработки текущих запросов.
+// Сервер метрик останавливается последним, чтобы метрики были доступны во время остановки.
+func shutdown(httpServer *http.Server, grpcServer *grpc.Server, metricsServer *http.Server) error {
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if metricsServer != nil {
+		defer metricsServer.Shutdown(ctx)
+	}
+	if grpcServer == nil {
+		return httpServer.Shutdown(ctx)
+	}
+	grpcStopped := make(chan struct{})
+	go func() {
+		defer close(grpcStopped)
+		grpcServer.GracefulStop()
+	}()
+	err := httpServer.Shutdown(ctx)
+	select {
+	case <-grpcStopped:
+	case <-ctx.Done():
+		grpcServer.Stop()
+	}
+	return err
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	customerrors "github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/errors"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/logger"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/models"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/tracing"
+	"golang.org/x/net/idna"
+)
+
+// invalidUserID код ошибки проверки идентификатора пользователя.
+const invalidUserID = "invalid_user"
+
+// AdminGetURL возвращает URL с владельцем, служебными данными и количеством переходов.
+// URL возвращается независимо от владельца, удаления, отключения и срока действия.
+func (service *shortenerService) AdminGetURL(ctx context.Context, shortURL string) (_ models.AdminURL, err error) {
+	ctx, span := tracing.Start(ctx, "service.AdminGetURL", tracing.String("short_url", shortURL))
+	defer span.EndWithError(&err)
+	url, err := service.storage.FindByShortURL(ctx, shortURL)
+	if err != nil {
+		return models.AdminURL{}, notFoundError(err)
+	}
+	stats, err := service.storage.GetClickStats(ctx, shortURL, 0)
+	if err != nil {
+		return models.AdminURL{}, err
+	}
+	adminURL := newAdminURL(*url)
+	adminURL.TotalClicks = stats.TotalClicks
+	return adminURL, nil
+}
+
+// AdminSetURLDisabled отключает или включает URL и возвращает его после изменения.
+// Отключенный URL не открывается, но остается у владельца и может быть включен обратно.
+func (service *shortenerService) AdminSetURLDisabled(ctx context.Context, shortURL string, disabled bool) (_ models.AdminURL, err error) {
+	ctx, span := tracing.Start(ctx, "service.AdminSetURLDisabled", tracing.String("short_url", shortURL))
+	defer span.EndWithError(&err)
+	if err := service.storage.SetURLDisabled(ctx, shortURL, disabled); err != nil {
+		return models.AdminURL{}, notFoundError(err)
+	}
+	logger.Logger.InfoContext(ctx, "admin changed url state", "short_url", shortURL, "disabled", disabled)
+	return service.AdminGetURL(ctx, shortURL)
+}
+
+// AdminSetURLOwner передает URL зарегистрированному пользователю userID и возвращает его после изменения.
+func (service *shortenerService) AdminSetURLOwner(ctx context.Context, shortURL string, userID int) (_ models.AdminURL, err error) {
+	ctx, span := tracing.Start(ctx, "service.AdminSetURLOwner", tracing.String("short_url", shortURL), tracing.Int("user_id", userID))
+	defer span.EndWithError(&err)
+	if userID <= 0 {
+		return models.AdminURL{}, customerrors.NewCustomErrorValidation("user_id", invalidUserID, "user id must be positive")
+	}
+	if err := service.storage.SetURLOwner(ctx, shortURL, userID); err != nil {
+		return models.AdminURL{}, notFoundError(err)
+	}
+	logger.Logger.InfoContext(ctx, "admin changed url owner", "short_url", shortURL, "user_id", userID)
+	return service.AdminGetURL(ctx, shortURL)
+}
+
+// AdminGetUrlsByUser возвращает страницу URL пользователя userID со служебными данными, в том числе удаленных.
+// Фильтры, сортировка и курсор запроса работают так же, как в GetUrlsByUser.
+func (service *shortenerService) AdminGetUrlsByUser(ctx context.Context, userID int, query models.URLQuery) (_ models.AdminURLPage, err error) {
+	ctx, span := tracing.Start(ctx, "service.AdminGetUrlsByUser", tracing.Int("user_id", userID))
+	defer span.EndWithError(&err)
+	urls, nextCursor, err := service.findURLPage(ctx, userID, query)
+	if err != nil {
+		return models.AdminURLPage{}, err
+	}
+	page := models.AdminURLPage{URLs: make([]models.AdminURL, len(urls)), NextCursor: nextCursor}
+	for i, url := range urls {
+		page.URLs[i] = newAdminURL(url)
+	}
+	return page, nil
+}
+
+// AdminDeleteUrlsOfUser помечает удаленными все URL пользователя userID.
+// В отличие от DeleteUrlsByUser удаление выполняется сразу, без задания на удаление.
+func (service *shortenerService) AdminDeleteUrlsOfUser(ctx context.Context, userID int) (_ models.AdminDeleteResult, err error) {
+	ctx, span := tracing.Start(ctx, "service.AdminDeleteUrlsOfUser", tracing.Int("user_id", userID))
+	defer span.EndWithError(&err)
+	if userID <= 0 {
+		return models.AdminDeleteResult{}, customerrors.NewCustomErrorValidation("user_id", invalidUserID, "user id must be positive")
+	}
+	deleted, err := service.storage.DeleteUrlsOfUser(ctx, userID)
+	if err != nil {
+		return models.AdminDeleteResult{}, err
+	}
+	logger.Logger.InfoContext(ctx, "admin deleted urls of user", "user_id", userID, "deleted", len(deleted))
+	return models.AdminDeleteResult{Deleted: len(deleted), ShortURLs: deleted}, nil
+}
+
+// AdminDeleteUrlsOfDomain помечает удаленными все URL, исходный URL которых ведет на домен domain или его поддомен.
+// Домен сравнивается без учета регистра, интернационализированный домен переводится в punycode.
+func (service *shortenerService) AdminDeleteUrlsOfDomain(ctx context.Context, domain string) (_ models.AdminDeleteResult, err error) {
+	ctx, span := tracing.Start(ctx, "service.AdminDeleteUrlsOfDomain", tracing.String("domain", domain))
+	defer span.EndWithError(&err)
+	domain, err = normalizeDomain(domain)
+	if err != nil {
+		return models.AdminDeleteResult{}, customerrors.NewCustomErrorValidation("domain", invalidURLHost, err.Error())
+	}
+	deleted, err := service.storage.DeleteUrlsOfDomain(ctx, domain)
+	if err != nil {
+		return models.AdminDeleteResult{}, err
+	}
+	logger.Logger.InfoContext(ctx, "admin deleted urls of domain", "domain", domain, "deleted", len(deleted))
+	return models.AdminDeleteResult{Deleted: len(deleted), ShortURLs: deleted}, nil
+}
+
+// normalizeDomain приводит домен к виду, в котором хранятся хосты исходных URL.
+func normalizeDomain(domain string) (string, error) {
+	domain = strings.TrimSuffix(strings.TrimSpace(domain), ".")
+	if domain == "" || strings.ContainsAny(domain, "/:*@ ") {
+		return "", errors.New("domain is invalid")
+	}
+	ascii, err := idna.Lookup.ToASCII(domain)
+	if err != nil {
+		return "", errors.New("domain is invalid")
+	}
+	return strings.ToLower(ascii), nil
+}
+
+// notFoundError заменяет ошибки хранилища о ненайденном URL или пользователе ошибкой 404 Not Found.
+func notFoundError(err error) error {
+	for _, target := range []error{customerrors.ErrURLNotFound, customerrors.ErrUserNotFound} {
+		if errors.Is(err, target) {
+			notFound := customerrors.NewCustomError(target)
+			notFound.Status = http.StatusNotFound
+			return notFound
+		}
+	}
+	return err
+}
+
+func newAdminURL(url models.URL) models.AdminURL {
+	adminURL := models.AdminURL{
+		ShortURL:    url.ShortURL,
+		OriginalURL: url.OriginalURL,
+		UserID:      url.CreatedBy,
+		CreatedAt:   url.CreatedTS,
+		IsDeleted:   url.IsDeleted,
+		IsDisabled:  url.IsDisabled,
+	}
+	if !url.ExpiresAt.IsZero() {
+		expiresAt := url.ExpiresAt
+		adminURL.ExpiresAt = &expiresAt
+	}
+	if !url.DeletedAt.IsZero() {
+		deletedAt := url.DeletedAt
+		adminURL.DeletedAt = &deletedAt
+	}
+	return adminURL
+}
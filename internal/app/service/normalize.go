@@ -0,0 +1,127 @@
+package service
+
+import (
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	customerrors "github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/errors"
+	"golang.org/x/net/idna"
+)
+
+// maxOriginalURLLength максимальная длина исходного URL.
+const maxOriginalURLLength = 2048
+
+// allowedSchemes содержит схемы, которые разрешено сокращать, и их порты по умолчанию.
+var allowedSchemes = map[string]string{
+	"http":  "80",
+	"https": "443",
+}
+
+// Коды ошибок проверки исходного URL.
+const (
+	invalidURLEmpty   = "empty"
+	invalidURLTooLong = "too_long"
+	invalidURLSyntax  = "invalid_syntax"
+	invalidURLScheme  = "scheme_not_allowed"
+	invalidURLHost    = "invalid_host"
+	invalidURLPort    = "invalid_port"
+	invalidURLQuery   = "invalid_query"
+)
+
+// normalizeURL проверяет исходный URL поля field и приводит его к каноническому виду,
+// чтобы один и тот же адрес, записанный по-разному, сохранялся одинаково:
+// схема и хост приводятся к нижнему регистру, IDN-хост переводится в punycode, порт по умолчанию отбрасывается,
+// пустой путь заменяется на "/", завершающие "/" непустого пути отбрасываются, параметры запроса сортируются по имени.
+// Ошибка проверки возвращается как CustomError со статусом 400 и описанием в теле.
+func normalizeURL(field, rawURL string) (string, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	if rawURL == "" {
+		return "", customerrors.NewCustomErrorValidation(field, invalidURLEmpty, "original url is empty")
+	}
+	if len(rawURL) > maxOriginalURLLength {
+		return "", customerrors.NewCustomErrorValidation(field, invalidURLTooLong, "original url is longer than 2048 characters")
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return "", customerrors.NewCustomErrorValidation(field, invalidURLSyntax, "original url is malformed")
+	}
+	parsed.Scheme = strings.ToLower(parsed.Scheme)
+	defaultPort, ok := allowedSchemes[parsed.Scheme]
+	if !ok {
+		return "", customerrors.NewCustomErrorValidation(field, invalidURLScheme, "original url scheme must be http or https")
+	}
+	if parsed.Opaque != "" || parsed.Hostname() == "" {
+		return "", customerrors.NewCustomErrorValidation(field, invalidURLHost, "original url must have a host")
+	}
+	host, err := normalizeHost(parsed.Hostname())
+	if err != nil {
+		return "", customerrors.NewCustomErrorValidation(field, invalidURLHost, "original url host is invalid")
+	}
+	port := parsed.Port()
+	if port != "" {
+		number, err := strconv.Atoi(port)
+		if err != nil || number <= 0 || number > 65535 {
+			return "", customerrors.NewCustomErrorValidation(field, invalidURLPort, "original url port is invalid")
+		}
+		port = strconv.Itoa(number)
+	}
+	if port == defaultPort {
+		port = ""
+	}
+	if port != "" {
+		parsed.Host = net.JoinHostPort(host, port)
+	} else if strings.Contains(host, ":") {
+		parsed.Host = "[" + host + "]"
+	} else {
+		parsed.Host = host
+	}
+	if parsed.Path == "" {
+		parsed.Path = "/"
+	} else if trimmed := strings.TrimRight(parsed.Path, "/"); trimmed != "" {
+		parsed.Path = trimmed
+	} else {
+		parsed.Path = "/"
+	}
+	parsed.RawPath = ""
+	query, err := sortQuery(parsed.RawQuery)
+	if err != nil {
+		return "", customerrors.NewCustomErrorValidation(field, invalidURLQuery, "original url query is malformed")
+	}
+	parsed.RawQuery = query
+	parsed.ForceQuery = false
+	return parsed.String(), nil
+}
+
+// sortQuery сортирует параметры запроса по имени, сохраняя порядок значений одного параметра, и отбрасывает пустые параметры.
+// Параметры не декодируются и не кодируются заново, поэтому параметры без значения и разделитель ";" сохраняются как есть.
+// Возвращает ошибку, если параметр содержит некорректную escape-последовательность.
+func sortQuery(rawQuery string) (string, error) {
+	params := strings.FieldsFunc(rawQuery, func(r rune) bool { return r == '&' })
+	for _, param := range params {
+		if _, err := url.QueryUnescape(param); err != nil {
+			return "", err
+		}
+	}
+	sort.SliceStable(params, func(i, j int) bool {
+		return queryParamName(params[i]) < queryParamName(params[j])
+	})
+	return strings.Join(params, "&"), nil
+}
+
+// queryParamName возвращает имя параметра запроса в исходной записи.
+func queryParamName(param string) string {
+	name, _, _ := strings.Cut(param, "=")
+	return name
+}
+
+// normalizeHost приводит хост к нижнему регистру и переводит интернационализированное доменное имя в punycode.
+// IP-адреса возвращаются в каноническом виде.
+func normalizeHost(host string) (string, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.String(), nil
+	}
+	return idna.Lookup.ToASCII(strings.TrimSuffix(host, "."))
+}
@@ -3,37 +3,118 @@ package service
 
 import (
 	"context"
-	"encoding/json"
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
+	"fmt"
 	"net/http"
+	"regexp"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/config"
 	customerrors "github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/errors"
 	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/logger"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/metrics"
 	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/models"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/screening"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/shortcode"
 	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/storage"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/storage/urlquery"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/tracing"
 	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/util"
 )
 
+const (
+	minCustomAliasLength = 3
+	maxCustomAliasLength = 32
+)
+
+// maxGenerateAttempts количество попыток сохранить URL со сгенерированным сокращенным URL, если он оказался занят.
+const maxGenerateAttempts = 10
+
+// topStatsLimit количество самых частых значений в статистике переходов.
+const topStatsLimit = 10
+
+const (
+	defaultURLPageLimit = 100
+	maxURLPageLimit     = 1000
+)
+
+var customAliasPattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// reservedAliases содержит пути, которые обслуживаются сервером и не могут быть заняты пользовательскими сокращенными URL.
+var reservedAliases = map[string]struct{}{
+	"api":     {},
+	"debug":   {},
+	"healthz": {},
+	"metrics": {},
+	"ping":    {},
+	"readyz":  {},
+}
+
+// Параметры обработки заданий на удаление.
+const (
+	deletionPollPeriod      = 5 * time.Second
+	deletionBatchSize       = 100
+	deletionMaxAttempts     = 5
+	deletionRetryBaseDelay  = time.Second
+	deletionRetryMaxDelay   = 5 * time.Minute
+	deletionJobIDByteLength = 16
+	// deletionWorkerStallTimeout время без опроса заданий, после которого worker считается зависшим.
+	deletionWorkerStallTimeout = 10 * deletionPollPeriod
+	// deletionJobRetention время после завершения задания, в течение которого пользователь может узнать его результат.
+	deletionJobRetention = 7 * 24 * time.Hour
+)
+
 type shortenerService struct {
 	config  config.Config
 	storage storage.ShortenerStorage
-	ch      chan models.URLToDelete
+	clicks  chan models.Click
+	// deletions будит worker заданий на удаление, не дожидаясь очередного опроса хранилища.
+	deletions chan struct{}
+	// domains список запрещенных и разрешенных доменов, nil если не задан.
+	domains *screening.DomainList
+	// screener проверяет исходные URL перед сокращением, nil если проверки не заданы.
+	screener screening.Screener
+	// codes генерирует сокращенные URL.
+	codes shortcode.Generator
+	// deletionWorkerRunning признак того, что worker заданий на удаление запущен и не остановлен.
+	deletionWorkerRunning atomic.Bool
+	// deletionWorkerPolled время последнего опроса заданий на удаление в наносекундах Unix.
+	deletionWorkerPolled atomic.Int64
 }
 
 // NewShortenerService создает новый экземпляр сервиса для работы с URL с workers.
 func NewShortenerServiceWithWorkers(ctx context.Context, config config.Config, storage storage.ShortenerStorage, wg *sync.WaitGroup) (*shortenerService, error) {
-	service := &shortenerService{
-		config:  config,
-		storage: storage,
-		ch:      make(chan models.URLToDelete, 1024),
+	service, err := NewShortenerService(ctx, config, storage)
+	if err != nil {
+		return nil, err
+	}
+	if service.domains != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			service.domains.Watch(ctx, config.ScreeningReloadInterval.Duration)
+		}()
 	}
-	wg.Add(1)
+	wg.Add(3)
+	service.deletionWorkerRunning.Store(true)
+	service.deletionWorkerPolled.Store(time.Now().UnixNano())
 	go func() {
 		defer wg.Done()
-		service.deleteURLBatch(ctx)
+		defer service.deletionWorkerRunning.Store(false)
+		service.processDeletionJobs(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		service.deleteExpiredURLs(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		service.saveClickBatch(ctx)
 	}()
 	return service, nil
 }
@@ -41,48 +122,175 @@ func NewShortenerServiceWithWorkers(ctx context.Context, config config.Config, s
 // NewShortenerService создает новый экземпляр сервиса для работы с URL без workers.
 func NewShortenerService(ctx context.Context, config config.Config, storage storage.ShortenerStorage) (*shortenerService, error) {
 	service := &shortenerService{
-		config:  config,
-		storage: storage,
-		ch:      make(chan models.URLToDelete, 1024),
+		config:    config,
+		storage:   storage,
+		clicks:    make(chan models.Click, 1024),
+		deletions: make(chan struct{}, 1),
+	}
+	codes, err := shortcode.New(config, storage)
+	if err != nil {
+		return nil, err
+	}
+	service.codes = codes
+	var chain screening.Chain
+	if config.ScreeningDomainsFile != "" {
+		domains, err := screening.NewDomainList(config.ScreeningDomainsFile)
+		if err != nil {
+			return nil, err
+		}
+		service.domains = domains
+		chain = append(chain, domains)
+	}
+	if config.ScreeningServiceURL != "" {
+		chain = append(chain, screening.NewServiceScreener(config.ScreeningServiceURL, screening.DefaultServiceTimeout))
+	}
+	if len(chain) > 0 {
+		service.screener = chain
 	}
 	return service, nil
 }
 
 // CreateShortURL создает короткую ссылку на основе переданного URL.
-func (service *shortenerService) CreateShortURL(ctx context.Context, userInfo models.UserInfo, originalURL string) (string, error) {
-	if originalURL == "" {
-		return "", customerrors.NewCustomErrorBadRequest(errors.New("original url is empty"))
+// Если в запросе указан пользовательский сокращенный URL, он используется вместо сгенерированного.
+// Исходный URL проверяется и нормализуется перед сохранением, отклоненный проверкой URL не сохраняется.
+// Если запрос требует дедупликации и пользователь уже сокращал этот URL, возвращается конфликт с существующим сокращенным URL.
+func (service *shortenerService) CreateShortURL(ctx context.Context, userInfo models.UserInfo, request models.Request) (_ string, err error) {
+	ctx, span := tracing.Start(ctx, "service.CreateShortURL")
+	defer span.EndWithError(&err)
+	originalURL, err := normalizeURL("url", request.URL)
+	if err != nil {
+		return "", err
+	}
+	if err := service.screenURL(ctx, "url", originalURL); err != nil {
+		return "", err
+	}
+	expiresAt, err := getExpiresAt(request.ExpiresAt, request.TTL)
+	if err != nil {
+		return "", err
 	}
-	shortURL, err := service.generateShortURL(ctx)
+	shortURL, err := service.getShortURL(ctx, request.CustomAlias)
 	if err != nil {
 		return "", err
 	}
-	err = service.storage.Save(ctx, models.URL{
+	return service.saveURL(ctx, models.URL{
 		ShortURL:    shortURL,
 		OriginalURL: originalURL,
 		CreatedBy:   userInfo.UserID,
-	})
-	return shortURL, err
+		CreatedTS:   time.Now(),
+		ExpiresAt:   expiresAt,
+		Dedupe:      request.Dedupe,
+	}, request.CustomAlias == "")
 }
 
-func (service *shortenerService) generateShortURL(ctx context.Context) (string, error) {
-	shortURL := util.GenerateShortURL()
-	ok, err := service.storage.IsShortURLExists(ctx, shortURL)
+// saveURL сохраняет URL и возвращает его сокращенный URL. Если сокращенный URL сгенерирован и оказался занят,
+// сохранение повторяется с новым сгенерированным сокращенным URL, не более maxGenerateAttempts раз.
+func (service *shortenerService) saveURL(ctx context.Context, url models.URL, generated bool) (string, error) {
+	for attempt := 1; ; attempt++ {
+		err := service.storage.Save(ctx, url)
+		if !generated || !errors.Is(err, customerrors.ErrShortURLAlreadyExists) {
+			if err != nil {
+				return "", err
+			}
+			metrics.LinksCreated.Inc()
+			return url.ShortURL, nil
+		}
+		service.codes.Collided(url.ShortURL)
+		if attempt == maxGenerateAttempts {
+			return "", customerrors.NewCustomErrorInternal(errors.New("failed to generate unique short url"))
+		}
+		url.ShortURL, err = service.generateShortURL(ctx)
+		if err != nil {
+			return "", err
+		}
+	}
+}
+
+// screenURL проверяет исходный URL поля field и возвращает ошибку 422 с причиной, если URL отклонен.
+// Если проверка недоступна, URL пропускается, чтобы сбой сервиса проверки не останавливал сокращение.
+func (service *shortenerService) screenURL(ctx context.Context, field, originalURL string) error {
+	if service.screener == nil {
+		return nil
+	}
+	verdict, err := service.screener.Screen(ctx, originalURL)
+	if err != nil {
+		logger.Logger.WarnContext(ctx, "url screening failed", "url", originalURL, "error", err)
+	}
+	if verdict.Blocked {
+		return customerrors.NewCustomErrorRejected(field, verdict.Reason)
+	}
+	return nil
+}
+
+func (service *shortenerService) getShortURL(ctx context.Context, customAlias string) (string, error) {
+	if customAlias == "" {
+		return service.generateShortURL(ctx)
+	}
+	if err := validateCustomAlias(customAlias); err != nil {
+		return "", err
+	}
+	ok, err := service.storage.IsShortURLExists(ctx, customAlias)
 	if err != nil {
 		return "", err
 	}
-	for ok {
-		shortURL := util.GenerateShortURL()
-		ok, err = service.storage.IsShortURLExists(ctx, shortURL)
+	if ok {
+		return "", customerrors.NewCustomErrorConflict(customerrors.ErrShortURLAlreadyExists)
+	}
+	return customAlias, nil
+}
+
+// getExpiresAt вычисляет время истечения срока действия URL по абсолютному времени или TTL в секундах.
+// Нулевое значение означает, что срок действия не ограничен.
+func getExpiresAt(expiresAt *time.Time, ttl int64) (time.Time, error) {
+	if expiresAt != nil && ttl != 0 {
+		return time.Time{}, customerrors.NewCustomErrorBadRequest(errors.New("only one of expires_at and ttl can be set"))
+	}
+	if ttl < 0 {
+		return time.Time{}, customerrors.NewCustomErrorBadRequest(errors.New("ttl must be positive"))
+	}
+	if ttl > 0 {
+		return time.Now().Add(time.Duration(ttl) * time.Second), nil
+	}
+	if expiresAt != nil {
+		if !expiresAt.After(time.Now()) {
+			return time.Time{}, customerrors.NewCustomErrorBadRequest(errors.New("expires_at must be in the future"))
+		}
+		return *expiresAt, nil
+	}
+	return time.Time{}, nil
+}
+
+func validateCustomAlias(customAlias string) error {
+	if len(customAlias) < minCustomAliasLength || len(customAlias) > maxCustomAliasLength {
+		return customerrors.NewCustomErrorBadRequest(errors.New("custom alias length must be between 3 and 32"))
+	}
+	if !customAliasPattern.MatchString(customAlias) {
+		return customerrors.NewCustomErrorBadRequest(errors.New("custom alias contains invalid characters"))
+	}
+	if _, ok := reservedAliases[strings.ToLower(customAlias)]; ok {
+		return customerrors.NewCustomErrorBadRequest(errors.New("custom alias is reserved"))
+	}
+	return nil
+}
+
+// generateShortURL возвращает сокращенный URL от генератора, пропуская совпадающие с зарезервированными путями.
+// Занятость сокращенного URL проверяется хранилищем при сохранении.
+func (service *shortenerService) generateShortURL(ctx context.Context) (string, error) {
+	for {
+		shortURL, err := service.codes.Generate(ctx)
 		if err != nil {
-			return "", err
+			return "", customerrors.NewCustomErrorInternal(err)
+		}
+		if _, ok := reservedAliases[strings.ToLower(shortURL)]; !ok {
+			return shortURL, nil
 		}
 	}
-	return shortURL, nil
 }
 
 // GetByShortURL возвращает оригинальный URL по короткой ссылке.
-func (service *shortenerService) GetByShortURL(ctx context.Context, shortURL string) (string, error) {
+// Ссылки на домены, запрещенные списком доменов, не открываются, даже если были созданы до запрета.
+func (service *shortenerService) GetByShortURL(ctx context.Context, shortURL string) (_ string, err error) {
+	ctx, span := tracing.Start(ctx, "service.GetByShortURL", tracing.String("short_url", shortURL))
+	defer span.EndWithError(&err)
 	url, err := service.storage.FindByShortURL(ctx, shortURL)
 	if err != nil {
 		return "", err
@@ -92,133 +300,565 @@ func (service *shortenerService) GetByShortURL(ctx context.Context, shortURL str
 		err.Status = http.StatusGone
 		return "", err
 	}
+	if url.IsExpired(time.Now()) {
+		err := customerrors.NewCustomError(errors.New("original url is expired"))
+		err.Status = http.StatusGone
+		return "", err
+	}
+	if url.IsDisabled {
+		err := customerrors.NewCustomError(errors.New("original url is disabled"))
+		err.Status = http.StatusGone
+		return "", err
+	}
+	if service.domains != nil {
+		verdict, err := service.domains.Screen(ctx, url.OriginalURL)
+		if err != nil {
+			logger.Logger.WarnContext(ctx, "url screening failed", "url", url.OriginalURL, "error", err)
+		}
+		if verdict.Blocked {
+			err := customerrors.NewCustomError(errors.New("original url domain is blocked"))
+			err.Status = http.StatusGone
+			return "", err
+		}
+	}
+	metrics.Redirects.Inc()
 	return url.OriginalURL, nil
 }
 
+// RecordClick ставит событие перехода по сокращенному URL в очередь на сохранение.
+// Если очередь переполнена, событие отбрасывается, чтобы не задерживать редирект.
+func (service *shortenerService) RecordClick(ctx context.Context, click models.Click) {
+	_, span := tracing.Start(ctx, "service.RecordClick", tracing.String("short_url", click.ShortURL))
+	defer span.End()
+	select {
+	case service.clicks <- click:
+		metrics.ClickQueueDepth.Set(float64(len(service.clicks)))
+	default:
+		logger.Logger.WarnContext(ctx, "click queue is full, click is dropped", "short_url", click.ShortURL)
+	}
+}
+
 // PingStorage выполняет ping хранилища.
 func (service *shortenerService) PingStorage(ctx context.Context) bool {
+	ctx, span := tracing.Start(ctx, "service.PingStorage")
+	defer span.End()
 	return service.storage.Ping(ctx)
 }
 
 // CreateBatchShortURL создает короткие ссылки для массива URL-ов.
-func (service *shortenerService) CreateBatchShortURL(ctx context.Context, userInfo models.UserInfo, arr []models.OriginalURLInfoBatch) ([]models.ShortURLInfoBatch, error) {
+// URL обрабатываются независимо: для каждого возвращается результат created, existing с существующим сокращенным URL
+// или invalid с причиной. Ошибка возвращается, только если пакет пуст или хранилище недоступно.
+func (service *shortenerService) CreateBatchShortURL(ctx context.Context, userInfo models.UserInfo, arr []models.OriginalURLInfoBatch) (_ []models.ShortURLInfoBatch, err error) {
+	ctx, span := tracing.Start(ctx, "service.CreateBatchShortURL", tracing.Int("batch.size", len(arr)))
+	defer span.EndWithError(&err)
 	if len(arr) == 0 {
 		return nil, customerrors.NewCustomErrorBadRequest(errors.New("original url is empty"))
 	}
-	arrayToSave := make([]models.URL, len(arr))
-	arrayToReturn := make([]models.ShortURLInfoBatch, len(arr))
+	results := make([]models.ShortURLInfoBatch, len(arr))
+	arrayToSave := make([]models.URL, 0, len(arr))
+	generated := make([]bool, 0, len(arr))
+	indexes := make([]int, 0, len(arr))
+	aliases := make(map[string]struct{})
 	for i, url := range arr {
-		shortURL, err := service.generateShortURL(ctx)
+		results[i].CorrelationID = url.CorrelationID
+		urlToSave, err := service.newBatchURL(ctx, userInfo, url, aliases)
 		if err != nil {
-			return nil, customerrors.NewCustomErrorInternal(err)
-		}
-		arrayToSave[i] = models.URL{
-			ShortURL:    shortURL,
-			OriginalURL: url.OriginalURL,
-			CreatedBy:   userInfo.UserID,
+			var customErr *customerrors.CustomError
+			if !errors.As(err, &customErr) || customErr.Status == http.StatusInternalServerError {
+				return nil, err
+			}
+			results[i].Status = models.BatchItemInvalid
+			results[i].Error = customErr.Error()
+			continue
 		}
-		arrayToReturn[i] = models.ShortURLInfoBatch{
-			CorrelationID: url.CorrelationID,
-			ShortURL:      service.config.BaseReturnURL + "/" + shortURL,
+		arrayToSave = append(arrayToSave, urlToSave)
+		generated = append(generated, url.CustomAlias == "")
+		indexes = append(indexes, i)
+	}
+	errs, err := service.saveBatch(ctx, arrayToSave, generated)
+	if err != nil {
+		return nil, err
+	}
+	for j, i := range indexes {
+		result := &results[i]
+		var customErr *customerrors.CustomError
+		switch {
+		case errs[j] == nil:
+			metrics.LinksCreated.Inc()
+			result.Status = models.BatchItemCreated
+			result.ShortURL = service.config.BaseReturnURL + "/" + arrayToSave[j].ShortURL
+		case errors.As(errs[j], &customErr) && customErr.ShortURL != "":
+			result.Status = models.BatchItemExisting
+			result.ShortURL = service.config.BaseReturnURL + "/" + customErr.ShortURL
+		default:
+			result.Status = models.BatchItemInvalid
+			result.Error = errs[j].Error()
 		}
 	}
-	err := service.storage.SaveBatch(ctx, arrayToSave)
+	return results, nil
+}
+
+// saveBatch сохраняет URL пакета независимо друг от друга и возвращает ошибки сохранения отдельных URL.
+// URL со сгенерированным сокращенным URL, который оказался занят, сохраняются повторно с новым сгенерированным
+// сокращенным URL, не более maxGenerateAttempts раз; новые сокращенные URL записываются в urls.
+func (service *shortenerService) saveBatch(ctx context.Context, urls []models.URL, generated []bool) ([]error, error) {
+	errs, err := service.storage.SaveBatchPartial(ctx, urls)
 	if err != nil {
 		return nil, err
 	}
-	return arrayToReturn, nil
+	for attempt := 1; attempt < maxGenerateAttempts; attempt++ {
+		var retry []int
+		for i, err := range errs {
+			if generated[i] && errors.Is(err, customerrors.ErrShortURLAlreadyExists) {
+				service.codes.Collided(urls[i].ShortURL)
+				retry = append(retry, i)
+			}
+		}
+		if len(retry) == 0 {
+			break
+		}
+		urlsToRetry := make([]models.URL, len(retry))
+		for j, i := range retry {
+			urls[i].ShortURL, err = service.generateShortURL(ctx)
+			if err != nil {
+				return nil, err
+			}
+			urlsToRetry[j] = urls[i]
+		}
+		retryErrs, err := service.storage.SaveBatchPartial(ctx, urlsToRetry)
+		if err != nil {
+			return nil, err
+		}
+		for j, i := range retry {
+			errs[i] = retryErrs[j]
+		}
+	}
+	return errs, nil
+}
+
+// newBatchURL проверяет URL из пакета и возвращает URL для сохранения.
+// aliases содержит пользовательские сокращенные URL, уже занятые предыдущими URL пакета.
+func (service *shortenerService) newBatchURL(ctx context.Context, userInfo models.UserInfo, url models.OriginalURLInfoBatch, aliases map[string]struct{}) (models.URL, error) {
+	originalURL, err := normalizeURL("original_url", url.OriginalURL)
+	if err != nil {
+		return models.URL{}, err
+	}
+	if err := service.screenURL(ctx, "original_url", originalURL); err != nil {
+		return models.URL{}, err
+	}
+	expiresAt, err := getExpiresAt(url.ExpiresAt, url.TTL)
+	if err != nil {
+		return models.URL{}, err
+	}
+	if url.CustomAlias != "" {
+		if _, ok := aliases[url.CustomAlias]; ok {
+			return models.URL{}, customerrors.NewCustomErrorConflict(customerrors.ErrShortURLAlreadyExists)
+		}
+	}
+	shortURL, err := service.getShortURL(ctx, url.CustomAlias)
+	if err != nil {
+		var customErr *customerrors.CustomError
+		if errors.As(err, &customErr) {
+			return models.URL{}, err
+		}
+		return models.URL{}, customerrors.NewCustomErrorInternal(err)
+	}
+	if url.CustomAlias != "" {
+		aliases[url.CustomAlias] = struct{}{}
+	}
+	return models.URL{
+		ShortURL:    shortURL,
+		OriginalURL: originalURL,
+		CreatedBy:   userInfo.UserID,
+		CreatedTS:   time.Now(),
+		ExpiresAt:   expiresAt,
+		Dedupe:      url.Dedupe,
+	}, nil
 }
 
-// GetUserID возвращает идентификатор пользователя.
-func (service *shortenerService) GetUserID(ctx context.Context) int {
-	return service.storage.GetUserID(ctx)
+// CreateUser регистрирует нового пользователя.
+func (service *shortenerService) CreateUser(ctx context.Context) (_ models.User, err error) {
+	ctx, span := tracing.Start(ctx, "service.CreateUser")
+	defer span.EndWithError(&err)
+	return service.storage.CreateUser(ctx)
 }
 
-// GetUrlsByUser возвращает URL-ы, созданные пользователем.
-func (service *shortenerService) GetUrlsByUser(ctx context.Context, userInfo models.UserInfo) ([]models.URLByUser, error) {
-	urls, err := service.storage.FindByUser(ctx, userInfo.UserID)
+// GetUrlsByUser возвращает страницу URL-ов, созданных пользователем, с учетом фильтров и сортировки запроса.
+// Курсор следующей страницы возвращается, только если за текущей страницей есть еще URL.
+func (service *shortenerService) GetUrlsByUser(ctx context.Context, userInfo models.UserInfo, query models.URLQuery) (_ models.URLPage, err error) {
+	ctx, span := tracing.Start(ctx, "service.GetUrlsByUser")
+	defer span.EndWithError(&err)
+	urls, nextCursor, err := service.findURLPage(ctx, userInfo.UserID, query)
 	if err != nil {
-		return nil, err
+		return models.URLPage{}, err
 	}
-	urlsForUser := make([]models.URLByUser, len(urls))
+	page := models.URLPage{URLs: make([]models.URLByUser, len(urls)), NextCursor: nextCursor}
 	for i, el := range urls {
-		urlsForUser[i] = models.URLByUser{
+		page.URLs[i] = models.URLByUser{
 			ShortURL:    service.config.BaseReturnURL + "/" + el.ShortURL,
 			OriginalURL: el.OriginalURL,
 		}
 	}
-	return urlsForUser, nil
+	return page, nil
 }
 
-// DeleteUrlsByUser удаляет URL-ы, созданные пользователем.
-func (service *shortenerService) DeleteUrlsByUser(ctx context.Context, userInfo models.UserInfo, urls []string) {
-	go func() {
-		urlsToDelete := make([]models.URLToDelete, len(urls))
-		for i, el := range urls {
-			urlsToDelete[i].ShortURL = el
-			urlsToDelete[i].UserID = userInfo.UserID
+// findURLPage возвращает страницу URL пользователя userID и курсор следующей страницы, если за ней есть еще URL.
+func (service *shortenerService) findURLPage(ctx context.Context, userID int, query models.URLQuery) ([]models.URL, string, error) {
+	query, err := normalizeURLQuery(query)
+	if err != nil {
+		return nil, "", customerrors.NewCustomErrorBadRequest(err)
+	}
+	limit := query.Limit
+	query.Limit++
+	urls, err := service.storage.FindByUser(ctx, userID, query)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(urls) <= limit {
+		return urls, "", nil
+	}
+	urls = urls[:limit]
+	last := urls[len(urls)-1]
+	return urls, util.EncodeURLCursor(models.URLCursor{
+		Value:    urlquery.SortValue(last, query.SortBy),
+		ShortURL: last.ShortURL,
+	}), nil
+}
+
+func normalizeURLQuery(query models.URLQuery) (models.URLQuery, error) {
+	switch {
+	case query.Limit == 0:
+		query.Limit = defaultURLPageLimit
+	case query.Limit < 0:
+		return query, errors.New("limit must be positive")
+	case query.Limit > maxURLPageLimit:
+		query.Limit = maxURLPageLimit
+	}
+	switch query.SortBy {
+	case "":
+		query.SortBy = models.SortByCreatedTS
+	case models.SortByCreatedTS, models.SortByShortURL, models.SortByOriginalURL:
+	default:
+		return query, errors.New("unknown sort field: " + query.SortBy)
+	}
+	if !query.CreatedFrom.IsZero() && !query.CreatedTo.IsZero() && !query.CreatedFrom.Before(query.CreatedTo) {
+		return query, errors.New("created_from must be before created_to")
+	}
+	if query.Cursor != nil && query.SortBy == models.SortByCreatedTS {
+		if _, err := time.Parse(time.RFC3339Nano, query.Cursor.Value); err != nil {
+			return query, util.ErrInvalidCursor
 		}
-		for _, el := range urlsToDelete {
-			service.ch <- el
+	}
+	return query, nil
+}
+
+// DeleteUrlsByUser создает задание на удаление URL-ов, созданных пользователем.
+// Задание сохраняется в хранилище до начала удаления, поэтому оно будет выполнено и после перезапуска сервиса.
+func (service *shortenerService) DeleteUrlsByUser(ctx context.Context, userInfo models.UserInfo, urls []string) (_ models.DeletionJob, err error) {
+	ctx, span := tracing.Start(ctx, "service.DeleteUrlsByUser", tracing.Int("batch.size", len(urls)))
+	defer span.EndWithError(&err)
+	if len(urls) == 0 {
+		return models.DeletionJob{}, customerrors.NewCustomErrorBadRequest(errors.New("urls to delete are empty"))
+	}
+	id, err := newDeletionJobID()
+	if err != nil {
+		return models.DeletionJob{}, customerrors.NewCustomErrorInternal(err)
+	}
+	now := time.Now()
+	job := models.DeletionJob{
+		ID:            id,
+		UserID:        userInfo.UserID,
+		Status:        models.DeletionJobPending,
+		URLs:          make([]models.DeletionJobURL, 0, len(urls)),
+		CreatedAt:     now,
+		UpdatedAt:     now,
+		NextAttemptAt: now,
+	}
+	unique := make(map[string]struct{}, len(urls))
+	for _, url := range urls {
+		if _, ok := unique[url]; ok {
+			continue
 		}
-	}()
+		unique[url] = struct{}{}
+		job.URLs = append(job.URLs, models.DeletionJobURL{ShortURL: url, Status: models.DeletionURLPending})
+	}
+	if err := service.storage.SaveDeletionJob(ctx, job); err != nil {
+		return models.DeletionJob{}, err
+	}
+	select {
+	case service.deletions <- struct{}{}:
+	default:
+	}
+	return job, nil
+}
+
+func newDeletionJobID() (string, error) {
+	id := make([]byte, deletionJobIDByteLength)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
+
+// RestoreUrlsByUser восстанавливает удаленные URL-ы, созданные пользователем.
+// URL восстанавливается, только если с момента его удаления прошло не больше RestoreGracePeriod.
+func (service *shortenerService) RestoreUrlsByUser(ctx context.Context, userInfo models.UserInfo, urls []string) (_ []models.RestoreResult, err error) {
+	ctx, span := tracing.Start(ctx, "service.RestoreUrlsByUser", tracing.Int("batch.size", len(urls)))
+	defer span.EndWithError(&err)
+	if len(urls) == 0 {
+		return nil, customerrors.NewCustomErrorBadRequest(errors.New("urls to restore are empty"))
+	}
+	unique := make([]string, 0, len(urls))
+	seen := make(map[string]struct{}, len(urls))
+	for _, url := range urls {
+		if _, ok := seen[url]; !ok {
+			seen[url] = struct{}{}
+			unique = append(unique, url)
+		}
+	}
+	deletedAfter := time.Now().Add(-service.config.RestoreGracePeriod.Duration)
+	restored, err := service.storage.RestoreUrls(ctx, userInfo.UserID, unique, deletedAfter)
+	if err != nil {
+		return nil, err
+	}
+	restoredSet := make(map[string]struct{}, len(restored))
+	for _, shortURL := range restored {
+		restoredSet[shortURL] = struct{}{}
+	}
+	results := make([]models.RestoreResult, len(unique))
+	for i, shortURL := range unique {
+		results[i] = models.RestoreResult{ShortURL: shortURL, Status: models.RestoreURLNotRestored}
+		if _, ok := restoredSet[shortURL]; ok {
+			results[i].Status = models.RestoreURLRestored
+		}
+	}
+	return results, nil
+}
+
+// GetDeletionJob возвращает задание на удаление.
+// Задание доступно только пользователю, создавшему его.
+func (service *shortenerService) GetDeletionJob(ctx context.Context, userInfo models.UserInfo, id string) (_ models.DeletionJob, err error) {
+	ctx, span := tracing.Start(ctx, "service.GetDeletionJob")
+	defer span.EndWithError(&err)
+	job, err := service.storage.FindDeletionJob(ctx, id)
+	if errors.Is(err, customerrors.ErrDeletionJobNotFound) || (err == nil && job.UserID != userInfo.UserID) {
+		err := customerrors.NewCustomError(customerrors.ErrDeletionJobNotFound)
+		err.Status = http.StatusNotFound
+		return models.DeletionJob{}, err
+	}
+	if err != nil {
+		return models.DeletionJob{}, err
+	}
+	return *job, nil
+}
+
+// GetURLStats возвращает статистику переходов по сокращенному URL.
+// Статистика доступна только пользователю, создавшему URL.
+func (service *shortenerService) GetURLStats(ctx context.Context, userInfo models.UserInfo, shortURL string) (_ models.LinkStats, err error) {
+	ctx, span := tracing.Start(ctx, "service.GetURLStats", tracing.String("short_url", shortURL))
+	defer span.EndWithError(&err)
+	url, err := service.storage.FindByShortURL(ctx, shortURL)
+	if errors.Is(err, customerrors.ErrURLNotFound) || (err == nil && url.CreatedBy != userInfo.UserID) {
+		err := customerrors.NewCustomError(customerrors.ErrURLNotFound)
+		err.Status = http.StatusNotFound
+		return models.LinkStats{}, err
+	}
+	if err != nil {
+		return models.LinkStats{}, err
+	}
+	return service.storage.GetClickStats(ctx, shortURL, topStatsLimit)
 }
 
 // GetStats возвращает в ответ объект статистики.
-func (service *shortenerService) GetStats(ctx context.Context) (models.Stats, error) {
+func (service *shortenerService) GetStats(ctx context.Context) (_ models.Stats, err error) {
+	ctx, span := tracing.Start(ctx, "service.GetStats")
+	defer span.EndWithError(&err)
 	return service.storage.GetStats(ctx)
 }
 
-func (service *shortenerService) deleteURLBatch(ctx context.Context) {
-	tickerPeriod := 10 * time.Second
+// CheckDeletionWorker проверяет, что worker заданий на удаление запущен и регулярно опрашивает хранилище.
+func (service *shortenerService) CheckDeletionWorker(_ context.Context) error {
+	if !service.deletionWorkerRunning.Load() {
+		return errors.New("deletion worker isn't running")
+	}
+	if polled := time.Unix(0, service.deletionWorkerPolled.Load()); time.Since(polled) > deletionWorkerStallTimeout {
+		return fmt.Errorf("deletion worker hasn't polled jobs since %s", polled.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+// processDeletionJobs выполняет сохраненные задания на удаление.
+// Перед остановкой worker еще раз выполняет задания, попытка которых уже наступила.
+func (service *shortenerService) processDeletionJobs(ctx context.Context) {
+	ticker := time.NewTicker(deletionPollPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			service.runPendingDeletionJobs(context.WithoutCancel(ctx))
+			return
+		case <-service.deletions:
+			service.runPendingDeletionJobs(ctx)
+		case <-ticker.C:
+			service.runPendingDeletionJobs(ctx)
+		}
+	}
+}
+
+func (service *shortenerService) runPendingDeletionJobs(ctx context.Context) {
+	defer service.updateDeletionQueueDepth(ctx)
+	for {
+		service.deletionWorkerPolled.Store(time.Now().UnixNano())
+		jobs, err := service.storage.FindPendingDeletionJobs(ctx, time.Now(), deletionBatchSize)
+		if err != nil {
+			logger.Logger.Error("find pending deletion jobs error", "error", err)
+			return
+		}
+		for _, job := range jobs {
+			service.runDeletionJob(ctx, job)
+		}
+		if len(jobs) < deletionBatchSize {
+			return
+		}
+	}
+}
+
+// updateDeletionQueueDepth обновляет метрику количества незавершенных заданий на удаление по хранилищу.
+func (service *shortenerService) updateDeletionQueueDepth(ctx context.Context) {
+	count, err := service.storage.CountPendingDeletionJobs(ctx)
+	if err != nil {
+		logger.Logger.Error("count pending deletion jobs error", "error", err)
+		return
+	}
+	metrics.DeletionQueueDepth.Set(float64(count))
+}
+
+// runDeletionJob выполняет одну попытку удаления URL задания и сохраняет ее результат.
+// При ошибке следующая попытка откладывается с экспоненциальной задержкой, после deletionMaxAttempts попыток задание завершается с ошибкой.
+func (service *shortenerService) runDeletionJob(ctx context.Context, job models.DeletionJob) {
+	ctx, span := tracing.Start(ctx, "service.runDeletionJob", tracing.String("job.id", job.ID), tracing.Int("batch.size", len(job.URLs)))
+	defer span.End()
+	urlsToDelete := make([]models.URLToDelete, len(job.URLs))
+	for i, url := range job.URLs {
+		urlsToDelete[i] = models.URLToDelete{UserID: job.UserID, ShortURL: url.ShortURL}
+	}
+	metrics.DeletionBatchSize.Observe(float64(len(urlsToDelete)))
+	deleted, err := service.storage.DeleteUrls(ctx, urlsToDelete)
+	job.Attempts++
+	job.UpdatedAt = time.Now()
+	switch {
+	case err == nil:
+		deletedSet := make(map[string]struct{}, len(deleted))
+		for _, shortURL := range deleted {
+			deletedSet[shortURL] = struct{}{}
+		}
+		for i, url := range job.URLs {
+			job.URLs[i].Status = models.DeletionURLNotFound
+			if _, ok := deletedSet[url.ShortURL]; ok {
+				job.URLs[i].Status = models.DeletionURLDeleted
+			}
+		}
+		job.Status = models.DeletionJobDone
+		job.LastError = ""
+	case job.Attempts >= deletionMaxAttempts:
+		logger.Logger.Error("deletion job failed", "id", job.ID, "attempts", job.Attempts, "error", err)
+		for i := range job.URLs {
+			job.URLs[i].Status = models.DeletionURLFailed
+		}
+		job.Status = models.DeletionJobFailed
+		job.LastError = err.Error()
+	default:
+		logger.Logger.Warn("deletion job attempt failed", "id", job.ID, "attempts", job.Attempts, "error", err)
+		job.LastError = err.Error()
+		job.NextAttemptAt = job.UpdatedAt.Add(deletionRetryDelay(job.Attempts))
+	}
+	if err := service.storage.SaveDeletionJob(ctx, job); err != nil {
+		logger.Logger.Error("save deletion job error", "id", job.ID, "error", err)
+	}
+}
+
+// deletionRetryDelay возвращает задержку перед следующей попыткой после attempts неудачных попыток.
+func deletionRetryDelay(attempts int) time.Duration {
+	delay := deletionRetryBaseDelay
+	for i := 1; i < attempts && delay < deletionRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, deletionRetryMaxDelay)
+}
+
+func (service *shortenerService) saveClickBatch(ctx context.Context) {
+	tickerPeriod := time.Second
 	ticker := time.NewTicker(tickerPeriod)
+	defer ticker.Stop()
 	maxSizeArray := 1000
-	urlsToDelete := make([]models.URLToDelete, 0, maxSizeArray)
-	defer func() {
-		if len(urlsToDelete) > 0 {
-			err := service.storage.DeleteUrls(ctx, urlsToDelete)
-			if err != nil {
-				service.logErrorWhenDeleteUrls(err, urlsToDelete)
-			}
+	clicks := make([]models.Click, 0, maxSizeArray)
+	flush := func(ctx context.Context) {
+		metrics.ClickQueueDepth.Set(float64(len(service.clicks)))
+		if len(clicks) == 0 {
+			return
 		}
-	}()
+		metrics.ClickBatchSize.Observe(float64(len(clicks)))
+		if err := service.storage.SaveClicks(ctx, clicks); err != nil {
+			logger.Logger.Error("save clicks error", "error", err, "count", len(clicks))
+		}
+		clicks = clicks[:0]
+	}
 	for {
 		select {
-		case url := <-service.ch:
-			urlsToDelete = append(urlsToDelete, url)
-			if len(urlsToDelete) >= maxSizeArray {
-				err := service.storage.DeleteUrls(ctx, urlsToDelete)
-				if err != nil {
-					service.logErrorWhenDeleteUrls(err, urlsToDelete)
-					continue
-				}
-				urlsToDelete = urlsToDelete[:0]
+		case click := <-service.clicks:
+			clicks = append(clicks, click)
+			if len(clicks) >= maxSizeArray {
+				flush(ctx)
 			}
 		case <-ctx.Done():
-			if len(urlsToDelete) == 0 {
-				return
+			for {
+				select {
+				case click := <-service.clicks:
+					clicks = append(clicks, click)
+				default:
+					flush(context.WithoutCancel(ctx))
+					return
+				}
 			}
-			service.storage.DeleteUrls(ctx, urlsToDelete)
+		case <-ticker.C:
+			flush(ctx)
+		}
+	}
+}
+
+// deleteExpiredURLs периодически удаляет URL, срок действия которых истек больше ExpiredRetention назад,
+// и завершенные задания на удаление старше deletionJobRetention.
+// До удаления истекший URL отвечает 410 Gone, чтобы клиент мог отличить его от несуществующего.
+func (service *shortenerService) deleteExpiredURLs(ctx context.Context) {
+	tickerPeriod := time.Minute
+	ticker := time.NewTicker(tickerPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			if len(urlsToDelete) == 0 {
-				continue
-			}
-			err := service.storage.DeleteUrls(ctx, urlsToDelete)
+			service.deleteFinishedDeletionJobs(ctx)
+			count, err := service.storage.DeleteExpired(ctx, time.Now().Add(-service.config.ExpiredRetention.Duration))
 			if err != nil {
-				service.logErrorWhenDeleteUrls(err, urlsToDelete)
+				logger.Logger.Error("delete expired urls error", "error", err)
 				continue
 			}
-			urlsToDelete = urlsToDelete[:0]
+			if count > 0 {
+				logger.Logger.Info("expired urls deleted", "count", count)
+			}
 		}
 	}
 }
 
-func (*shortenerService) logErrorWhenDeleteUrls(err error, urlsToDelete []models.URLToDelete) {
-	urlsJSON, errJSON := json.Marshal(urlsToDelete)
-	if errJSON != nil {
-		logger.Logger.Error("failed to marshal urls to delete", errJSON)
+// deleteFinishedDeletionJobs удаляет завершенные задания на удаление, результат которых хранится дольше deletionJobRetention.
+func (service *shortenerService) deleteFinishedDeletionJobs(ctx context.Context) {
+	count, err := service.storage.DeleteFinishedDeletionJobs(ctx, time.Now().Add(-deletionJobRetention))
+	if err != nil {
+		logger.Logger.Error("delete finished deletion jobs error", "error", err)
 		return
 	}
-	logger.Logger.Error("delete urls error", err, urlsJSON)
+	if count > 0 {
+		logger.Logger.Info("finished deletion jobs deleted", "count", count)
+	}
 }
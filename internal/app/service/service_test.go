@@ -0,0 +1,468 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/config"
+	customerrors "github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/errors"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/logger"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/metrics"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/models"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/storage/inmemory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingDeleteStorage возвращает ошибку на первые failures вызовов DeleteUrls.
+type failingDeleteStorage struct {
+	*inmemory.StorageInMemory
+	failures int
+}
+
+func (storage *failingDeleteStorage) DeleteUrls(ctx context.Context, urls []models.URLToDelete) ([]string, error) {
+	if storage.failures > 0 {
+		storage.failures--
+		return nil, errors.New("storage is unavailable")
+	}
+	return storage.StorageInMemory.DeleteUrls(ctx, urls)
+}
+
+func newDeletionTestStorage(t *testing.T) *inmemory.StorageInMemory {
+	err := logger.Init(slog.LevelInfo)
+	require.NoError(t, err)
+	storage := inmemory.NewInMemoryStorage(config.GetDefault())
+	for _, url := range []models.URL{
+		{ShortURL: "abc", OriginalURL: "https://example.com", CreatedBy: 1},
+		{ShortURL: "def", OriginalURL: "https://example.org", CreatedBy: 1},
+		{ShortURL: "ghi", OriginalURL: "https://example.net", CreatedBy: 2},
+	} {
+		require.NoError(t, storage.Save(context.Background(), url))
+	}
+	return storage
+}
+
+func TestDeletionJobProcessedOnShutdown(t *testing.T) {
+	storage := newDeletionTestStorage(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	service, err := NewShortenerServiceWithWorkers(ctx, config.GetDefault(), storage, &wg)
+	require.NoError(t, err)
+
+	job, err := service.DeleteUrlsByUser(context.Background(), models.UserInfo{UserID: 1}, []string{"abc", "def", "ghi", "abc"})
+	require.NoError(t, err)
+	assert.Equal(t, models.DeletionJobPending, job.Status)
+	assert.NotEmpty(t, job.ID)
+	cancel()
+	wg.Wait()
+
+	job, err = service.GetDeletionJob(context.Background(), models.UserInfo{UserID: 1}, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.DeletionJobDone, job.Status)
+	assert.Equal(t, []models.DeletionJobURL{
+		{ShortURL: "abc", Status: models.DeletionURLDeleted},
+		{ShortURL: "def", Status: models.DeletionURLDeleted},
+		{ShortURL: "ghi", Status: models.DeletionURLNotFound},
+	}, job.URLs)
+	for shortURL, isDeleted := range map[string]bool{"abc": true, "def": true, "ghi": false} {
+		url, err := storage.FindByShortURL(context.Background(), shortURL)
+		require.NoError(t, err)
+		assert.Equal(t, isDeleted, url.IsDeleted, shortURL)
+	}
+}
+
+func TestDeletionJobRetries(t *testing.T) {
+	tests := []struct {
+		name       string
+		failures   int
+		wantStatus string
+		wantURL    string
+	}{
+		{name: "retry succeeds", failures: 2, wantStatus: models.DeletionJobDone, wantURL: models.DeletionURLDeleted},
+		{name: "attempts exhausted", failures: deletionMaxAttempts, wantStatus: models.DeletionJobFailed, wantURL: models.DeletionURLFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := &failingDeleteStorage{StorageInMemory: newDeletionTestStorage(t), failures: tt.failures}
+			service, err := NewShortenerService(context.Background(), config.GetDefault(), storage)
+			require.NoError(t, err)
+			job, err := service.DeleteUrlsByUser(context.Background(), models.UserInfo{UserID: 1}, []string{"abc"})
+			require.NoError(t, err)
+
+			for i := 0; i < deletionMaxAttempts; i++ {
+				jobs, err := storage.FindPendingDeletionJobs(context.Background(), time.Now().Add(deletionRetryMaxDelay), deletionBatchSize)
+				require.NoError(t, err)
+				for _, job := range jobs {
+					service.runDeletionJob(context.Background(), job)
+				}
+			}
+
+			job, err = service.GetDeletionJob(context.Background(), models.UserInfo{UserID: 1}, job.ID)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, job.Status)
+			assert.Equal(t, tt.wantURL, job.URLs[0].Status)
+			assert.Equal(t, min(tt.failures+1, deletionMaxAttempts), job.Attempts)
+		})
+	}
+}
+
+func TestDeletionQueueDepth(t *testing.T) {
+	storage := &failingDeleteStorage{StorageInMemory: newDeletionTestStorage(t), failures: 1}
+	service, err := NewShortenerService(context.Background(), config.GetDefault(), storage)
+	require.NoError(t, err)
+	_, err = service.DeleteUrlsByUser(context.Background(), models.UserInfo{UserID: 1}, []string{"abc"})
+	require.NoError(t, err)
+
+	// задание, ожидающее повторной попытки, остается в очереди, даже если при опросе нет наступивших заданий
+	service.runPendingDeletionJobs(context.Background())
+	assert.Equal(t, float64(1), metrics.DeletionQueueDepth.Value())
+	service.runPendingDeletionJobs(context.Background())
+	assert.Equal(t, float64(1), metrics.DeletionQueueDepth.Value())
+
+	jobs, err := storage.FindPendingDeletionJobs(context.Background(), time.Now().Add(deletionRetryMaxDelay), deletionBatchSize)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	service.runDeletionJob(context.Background(), jobs[0])
+	service.runPendingDeletionJobs(context.Background())
+	assert.Equal(t, float64(0), metrics.DeletionQueueDepth.Value())
+}
+
+func TestGetDeletionJobOfAnotherUser(t *testing.T) {
+	storage := newDeletionTestStorage(t)
+	service, err := NewShortenerService(context.Background(), config.GetDefault(), storage)
+	require.NoError(t, err)
+	job, err := service.DeleteUrlsByUser(context.Background(), models.UserInfo{UserID: 1}, []string{"abc"})
+	require.NoError(t, err)
+
+	for _, id := range []string{job.ID, "unknown"} {
+		_, err = service.GetDeletionJob(context.Background(), models.UserInfo{UserID: 2}, id)
+		var customErr *customerrors.CustomError
+		require.ErrorAs(t, err, &customErr)
+		assert.Equal(t, http.StatusNotFound, customErr.Status)
+	}
+}
+
+func TestCheckDeletionWorker(t *testing.T) {
+	storage := newDeletionTestStorage(t)
+	service, err := NewShortenerService(context.Background(), config.GetDefault(), storage)
+	require.NoError(t, err)
+	assert.Error(t, service.CheckDeletionWorker(context.Background()), "worker isn't started without workers")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	service, err = NewShortenerServiceWithWorkers(ctx, config.GetDefault(), storage, &wg)
+	require.NoError(t, err)
+	assert.NoError(t, service.CheckDeletionWorker(context.Background()))
+
+	service.deletionWorkerPolled.Store(time.Now().Add(-2 * deletionWorkerStallTimeout).UnixNano())
+	assert.Error(t, service.CheckDeletionWorker(context.Background()), "stalled worker must be reported")
+
+	cancel()
+	wg.Wait()
+	assert.Error(t, service.CheckDeletionWorker(context.Background()), "stopped worker must be reported")
+}
+
+func TestDeletionRetryDelay(t *testing.T) {
+	assert.Equal(t, time.Second, deletionRetryDelay(1))
+	assert.Equal(t, 4*time.Second, deletionRetryDelay(3))
+	assert.Equal(t, deletionRetryMaxDelay, deletionRetryDelay(100))
+}
+
+func TestRestoreUrlsByUser(t *testing.T) {
+	storage := newDeletionTestStorage(t)
+	config := config.GetDefault()
+	config.RestoreGracePeriod.Duration = time.Hour
+	service, err := NewShortenerService(context.Background(), config, storage)
+	require.NoError(t, err)
+	for _, url := range []models.URL{
+		{ShortURL: "old", OriginalURL: "https://example.com/old", CreatedBy: 1, IsDeleted: true, DeletedAt: time.Now().Add(-2 * time.Hour)},
+		{ShortURL: "legacy", OriginalURL: "https://example.com/legacy", CreatedBy: 1, IsDeleted: true},
+	} {
+		require.NoError(t, storage.Save(context.Background(), url))
+	}
+	_, err = storage.DeleteUrls(context.Background(), []models.URLToDelete{{UserID: 1, ShortURL: "abc"}, {UserID: 2, ShortURL: "ghi"}})
+	require.NoError(t, err)
+
+	results, err := service.RestoreUrlsByUser(context.Background(), models.UserInfo{UserID: 1},
+		[]string{"abc", "abc", "def", "ghi", "old", "legacy", "unknown"})
+	require.NoError(t, err)
+	assert.Equal(t, []models.RestoreResult{
+		{ShortURL: "abc", Status: models.RestoreURLRestored},
+		{ShortURL: "def", Status: models.RestoreURLNotRestored},
+		{ShortURL: "ghi", Status: models.RestoreURLNotRestored},
+		{ShortURL: "old", Status: models.RestoreURLNotRestored},
+		{ShortURL: "legacy", Status: models.RestoreURLNotRestored},
+		{ShortURL: "unknown", Status: models.RestoreURLNotRestored},
+	}, results)
+	originalURL, err := service.GetByShortURL(context.Background(), "abc")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com", originalURL)
+
+	_, err = service.RestoreUrlsByUser(context.Background(), models.UserInfo{UserID: 1}, nil)
+	var customErr *customerrors.CustomError
+	require.ErrorAs(t, err, &customErr)
+	assert.Equal(t, http.StatusBadRequest, customErr.Status)
+}
+
+func TestCreateShortURLDedupe(t *testing.T) {
+	storage := newDeletionTestStorage(t)
+	service, err := NewShortenerService(context.Background(), config.GetDefault(), storage)
+	require.NoError(t, err)
+	ctx := context.Background()
+	user := models.UserInfo{UserID: 1}
+
+	// без дедупликации на один URL создается новая ссылка
+	shortURL, err := service.CreateShortURL(ctx, user, models.Request{URL: "https://example.com"})
+	require.NoError(t, err)
+	assert.NotEqual(t, "abc", shortURL)
+
+	_, err = service.CreateShortURL(ctx, user, models.Request{URL: "https://example.com", Dedupe: true})
+	var customErr *customerrors.CustomError
+	require.ErrorAs(t, err, &customErr)
+	assert.Equal(t, http.StatusConflict, customErr.Status)
+	assert.Contains(t, []string{"abc", shortURL}, customErr.ShortURL)
+
+	// дедупликация выполняется в пределах пользователя
+	_, err = service.CreateShortURL(ctx, models.UserInfo{UserID: 2}, models.Request{URL: "https://example.com", Dedupe: true})
+	require.NoError(t, err)
+
+	// удаленные ссылки не считаются дубликатами
+	_, err = storage.DeleteUrls(ctx, []models.URLToDelete{{UserID: 1, ShortURL: "def"}})
+	require.NoError(t, err)
+	_, err = service.CreateShortURL(ctx, user, models.Request{URL: "https://example.org", Dedupe: true})
+	require.NoError(t, err)
+
+	results, err := service.CreateBatchShortURL(ctx, user, []models.OriginalURLInfoBatch{
+		{CorrelationID: "1", OriginalURL: "https://example.com/batch", Dedupe: true},
+		{CorrelationID: "2", OriginalURL: "https://example.com/batch", Dedupe: true},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, models.BatchItemCreated, results[0].Status)
+	assert.Equal(t, models.BatchItemExisting, results[1].Status)
+	assert.Equal(t, results[0].ShortURL, results[1].ShortURL)
+}
+
+func TestCreateBatchShortURLPartial(t *testing.T) {
+	storage := newDeletionTestStorage(t)
+	config := config.GetDefault()
+	service, err := NewShortenerService(context.Background(), config, storage)
+	require.NoError(t, err)
+
+	results, err := service.CreateBatchShortURL(context.Background(), models.UserInfo{UserID: 1}, []models.OriginalURLInfoBatch{
+		{CorrelationID: "1", OriginalURL: "https://example.com/1", CustomAlias: "alias"},
+		{CorrelationID: "2", OriginalURL: "https://example.com/2", CustomAlias: "alias"},
+		{CorrelationID: "3", OriginalURL: "https://example.com/3", CustomAlias: "abc"},
+		{CorrelationID: "4", OriginalURL: "https://example.com/4", CustomAlias: "a"},
+		{CorrelationID: "5", OriginalURL: "HTTPS://Example.com:443/1/", Dedupe: true},
+		{CorrelationID: "6", OriginalURL: ""},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []models.ShortURLInfoBatch{
+		{CorrelationID: "1", ShortURL: config.BaseReturnURL + "/alias", Status: models.BatchItemCreated},
+		{CorrelationID: "2", Status: models.BatchItemInvalid, Error: customerrors.ErrShortURLAlreadyExists.Error()},
+		{CorrelationID: "3", Status: models.BatchItemInvalid, Error: customerrors.ErrShortURLAlreadyExists.Error()},
+		{CorrelationID: "4", Status: models.BatchItemInvalid, Error: "custom alias length must be between 3 and 32"},
+		{CorrelationID: "5", ShortURL: config.BaseReturnURL + "/alias", Status: models.BatchItemExisting},
+		{CorrelationID: "6", Status: models.BatchItemInvalid, Error: "original url is empty"},
+	}, results)
+	url, err := storage.FindByShortURL(context.Background(), "alias")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/1", url.OriginalURL)
+
+	_, err = service.CreateBatchShortURL(context.Background(), models.UserInfo{UserID: 1}, nil)
+	var customErr *customerrors.CustomError
+	require.ErrorAs(t, err, &customErr)
+	assert.Equal(t, http.StatusBadRequest, customErr.Status)
+}
+
+func TestNormalizeURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		rawURL   string
+		want     string
+		wantCode string
+	}{
+		{name: "root path", rawURL: " https://example.com ", want: "https://example.com/"},
+		{name: "case and default port", rawURL: "HTTP://Example.COM:80/Path/", want: "http://example.com/Path"},
+		{name: "non-default port", rawURL: "https://example.com:8443//", want: "https://example.com:8443/"},
+		{name: "idn", rawURL: "https://пример.рф/путь", want: "https://xn--e1afmkfd.xn--p1ai/%D0%BF%D1%83%D1%82%D1%8C"},
+		{name: "query order", rawURL: "https://example.com/a?b=2&a=1&b=1#top", want: "https://example.com/a?a=1&b=2&b=1#top"},
+		{name: "empty query", rawURL: "https://example.com/a?", want: "https://example.com/a"},
+		{name: "semicolon query", rawURL: "https://example.com/?a=1;b=2", want: "https://example.com/?a=1;b=2"},
+		{name: "query without value", rawURL: "https://example.com/?debug", want: "https://example.com/?debug"},
+		{name: "query kept encoded", rawURL: "https://example.com/?q=a+b%2Fc&debug&a=%7E", want: "https://example.com/?a=%7E&debug&q=a+b%2Fc"},
+		{name: "ipv6", rawURL: "http://[0:0::1]:80/", want: "http://[::1]/"},
+		{name: "empty", rawURL: " ", wantCode: invalidURLEmpty},
+		{name: "javascript", rawURL: "javascript:alert(1)", wantCode: invalidURLScheme},
+		{name: "relative", rawURL: "example.com/a", wantCode: invalidURLScheme},
+		{name: "no host", rawURL: "https:///a", wantCode: invalidURLHost},
+		{name: "invalid host", rawURL: "https://exa mple.com", wantCode: invalidURLSyntax},
+		{name: "invalid port", rawURL: "https://example.com:99999", wantCode: invalidURLPort},
+		{name: "invalid query", rawURL: "https://example.com/?a=%zz", wantCode: invalidURLQuery},
+		{name: "too long", rawURL: "https://example.com/" + strings.Repeat("a", maxOriginalURLLength), wantCode: invalidURLTooLong},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeURL("url", tt.rawURL)
+			if tt.wantCode == "" {
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+				return
+			}
+			var customErr *customerrors.CustomError
+			require.ErrorAs(t, err, &customErr)
+			assert.Equal(t, http.StatusBadRequest, customErr.Status)
+			var validationErr customerrors.ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, "url", validationErr.Field)
+			assert.Equal(t, tt.wantCode, validationErr.Code)
+		})
+	}
+}
+
+func TestScreening(t *testing.T) {
+	storage := newDeletionTestStorage(t)
+	path := filepath.Join(t.TempDir(), "domains")
+	require.NoError(t, os.WriteFile(path, []byte("deny *.example.org\n"), 0o644))
+	config := config.GetDefault()
+	config.ScreeningDomainsFile = path
+	service, err := NewShortenerService(context.Background(), config, storage)
+	require.NoError(t, err)
+	userInfo := models.UserInfo{UserID: 1}
+
+	_, err = service.CreateShortURL(context.Background(), userInfo, models.Request{URL: "https://login.EXAMPLE.org/"})
+	var customErr *customerrors.CustomError
+	require.ErrorAs(t, err, &customErr)
+	assert.Equal(t, http.StatusUnprocessableEntity, customErr.Status)
+	assert.JSONEq(t, `{"field":"url","code":"url_rejected","message":"domain login.example.org is blocked by rule \"deny *.example.org\""}`, string(customErr.Body))
+
+	results, err := service.CreateBatchShortURL(context.Background(), userInfo, []models.OriginalURLInfoBatch{
+		{CorrelationID: "1", OriginalURL: "https://example.org/1"},
+		{CorrelationID: "2", OriginalURL: "https://example.com/2"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, models.BatchItemInvalid, results[0].Status)
+	assert.Contains(t, results[0].Error, "is blocked")
+	assert.Equal(t, models.BatchItemCreated, results[1].Status)
+
+	// Ссылка, созданная до запрета домена, перестает открываться.
+	originalURL, err := service.GetByShortURL(context.Background(), "ghi")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.net", originalURL)
+	require.NoError(t, os.WriteFile(path, []byte("deny *.example.org\ndeny example.net\n"), 0o644))
+	_, err = service.domains.Reload()
+	require.NoError(t, err)
+	_, err = service.GetByShortURL(context.Background(), "ghi")
+	require.ErrorAs(t, err, &customErr)
+	assert.Equal(t, http.StatusGone, customErr.Status)
+}
+
+// fixedCodes выдает заранее заданные сокращенные URL и запоминает коллизии.
+type fixedCodes struct {
+	codes    []string
+	collided []string
+}
+
+func (generator *fixedCodes) Generate(context.Context) (string, error) {
+	code := generator.codes[0]
+	generator.codes = generator.codes[1:]
+	return code, nil
+}
+
+func (generator *fixedCodes) Collided(code string) {
+	generator.collided = append(generator.collided, code)
+}
+
+func TestGeneratedShortURLCollision(t *testing.T) {
+	storage := newDeletionTestStorage(t)
+	service, err := NewShortenerService(context.Background(), config.GetDefault(), storage)
+	require.NoError(t, err)
+	codes := &fixedCodes{codes: []string{"abc", "api", "new", "def", "ghi", "batch"}}
+	service.codes = codes
+	userInfo := models.UserInfo{UserID: 1}
+
+	// Занятый код заменяется новым при сохранении, зарезервированный пропускается
+	shortURL, err := service.CreateShortURL(context.Background(), userInfo, models.Request{URL: "https://example.com/new"})
+	require.NoError(t, err)
+	assert.Equal(t, "new", shortURL)
+	assert.Equal(t, []string{"abc"}, codes.collided)
+
+	results, err := service.CreateBatchShortURL(context.Background(), userInfo, []models.OriginalURLInfoBatch{
+		{CorrelationID: "1", OriginalURL: "https://example.com/batch"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, models.BatchItemCreated, results[0].Status)
+	assert.Equal(t, "http://localhost:8080/batch", results[0].ShortURL)
+	assert.Equal(t, []string{"abc", "def", "ghi"}, codes.collided)
+}
+
+func TestAdminDisabledURL(t *testing.T) {
+	storage := newDeletionTestStorage(t)
+	service, err := NewShortenerService(context.Background(), config.GetDefault(), storage)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	url, err := service.AdminSetURLDisabled(ctx, "abc", true)
+	require.NoError(t, err)
+	assert.True(t, url.IsDisabled)
+	assert.Equal(t, 1, url.UserID)
+	_, err = service.GetByShortURL(ctx, "abc")
+	var customErr *customerrors.CustomError
+	require.ErrorAs(t, err, &customErr)
+	assert.Equal(t, http.StatusGone, customErr.Status)
+
+	// отключенные ссылки не считаются дубликатами
+	shortURL, err := service.CreateShortURL(ctx, models.UserInfo{UserID: 1}, models.Request{URL: "https://example.com", Dedupe: true})
+	require.NoError(t, err)
+	assert.NotEqual(t, "abc", shortURL)
+
+	_, err = service.AdminSetURLDisabled(ctx, "abc", false)
+	require.NoError(t, err)
+	originalURL, err := service.GetByShortURL(ctx, "abc")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com", originalURL)
+
+	_, err = service.AdminGetURL(ctx, "unknown")
+	require.ErrorAs(t, err, &customErr)
+	assert.Equal(t, http.StatusNotFound, customErr.Status)
+	_, err = service.AdminSetURLOwner(ctx, "abc", 100)
+	require.ErrorAs(t, err, &customErr)
+	assert.Equal(t, http.StatusNotFound, customErr.Status)
+	_, err = service.AdminSetURLOwner(ctx, "abc", 0)
+	require.ErrorAs(t, err, &customErr)
+	assert.Equal(t, http.StatusBadRequest, customErr.Status)
+}
+
+func TestNormalizeDomain(t *testing.T) {
+	tests := []struct {
+		domain   string
+		expected string
+		wantErr  bool
+	}{
+		{domain: "Example.COM.", expected: "example.com"},
+		{domain: " sub.example.com ", expected: "sub.example.com"},
+		{domain: "пример.рф", expected: "xn--e1afmkfd.xn--p1ai"},
+		{domain: "", wantErr: true},
+		{domain: "https://example.com", wantErr: true},
+		{domain: "*.example.com", wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.domain, func(t *testing.T) {
+			domain, err := normalizeDomain(test.domain)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, domain)
+		})
+	}
+}
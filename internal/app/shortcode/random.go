@@ -0,0 +1,83 @@
+package shortcode
+
+import (
+	"context"
+	"crypto/rand"
+	"sync"
+)
+
+// Параметры роста длины случайного кода.
+const (
+	// growWindow количество кодов, по которым оценивается доля коллизий.
+	growWindow = 1000
+	// growMinCollisions минимальное количество коллизий в окне, после которого длина может вырасти.
+	growMinCollisions = 3
+	// growRatio длина растет, если коллизией заканчивается хотя бы каждый growRatio-й код окна.
+	growRatio = 20
+)
+
+// Random генерирует случайные коды из криптографически стойкого источника.
+// Доля коллизий примерно равна заполненности пространства кодов текущей длины, поэтому,
+// когда она превышает 1/growRatio, длина кода увеличивается на единицу.
+type Random struct {
+	alphabet string
+
+	mu        sync.Mutex
+	length    int
+	generated int
+	collided  int
+}
+
+// NewRandom создает генератор случайных кодов длины length из символов alphabet.
+func NewRandom(alphabet string, length int) *Random {
+	return &Random{alphabet: alphabet, length: length}
+}
+
+// Generate возвращает случайный код текущей длины.
+func (generator *Random) Generate(context.Context) (string, error) {
+	generator.mu.Lock()
+	length := generator.length
+	generator.generated++
+	if generator.generated > growWindow {
+		generator.generated, generator.collided = 1, 0
+	}
+	generator.mu.Unlock()
+
+	// Байты не меньше limit отбрасываются, чтобы символы алфавита выпадали равновероятно.
+	limit := 256 - 256%len(generator.alphabet)
+	code := make([]byte, 0, length)
+	buf := make([]byte, 2*length)
+	for len(code) < length {
+		if _, err := rand.Read(buf); err != nil {
+			return "", err
+		}
+		for _, b := range buf {
+			if int(b) >= limit {
+				continue
+			}
+			code = append(code, generator.alphabet[int(b)%len(generator.alphabet)])
+			if len(code) == length {
+				break
+			}
+		}
+	}
+	return string(code), nil
+}
+
+// Collided учитывает коллизию и увеличивает длину кода, если коллизии стали частыми.
+func (generator *Random) Collided(string) {
+	generator.mu.Lock()
+	defer generator.mu.Unlock()
+	generator.collided++
+	if generator.collided >= growMinCollisions && generator.collided*growRatio >= generator.generated && generator.length < maxLength {
+		generator.length++
+		generator.generated, generator.collided = 0, 0
+	}
+}
+
+// Length возвращает текущую длину кода.
+func (generator *Random) Length() int {
+	generator.mu.Lock()
+	defer generator.mu.Unlock()
+	return generator.length
+}
@@ -0,0 +1,101 @@
+package shortcode
+
+import (
+	"context"
+	"errors"
+)
+
+// Sequential кодирует номера последовательности хранилища в системе счисления по алфавиту.
+type Sequential struct {
+	sequence Sequence
+	alphabet string
+	// offset прибавляется к номеру, чтобы код был не короче заданной длины.
+	offset uint64
+}
+
+// NewSequential создает генератор, который кодирует номера из sequence символами alphabet, с кодами не короче length.
+func NewSequential(sequence Sequence, alphabet string, length int) *Sequential {
+	offset, _ := minNumber(len(alphabet), length)
+	return &Sequential{sequence: sequence, alphabet: alphabet, offset: offset}
+}
+
+// Generate возвращает код следующего номера последовательности.
+func (generator *Sequential) Generate(ctx context.Context) (string, error) {
+	number, err := nextNumber(ctx, generator.sequence)
+	if err != nil {
+		return "", err
+	}
+	return encode(generator.offset+number, generator.alphabet), nil
+}
+
+// Collided ничего не делает: следующий номер последовательности дает другой код.
+// Коллизия возможна только с пользовательским сокращенным URL.
+func (generator *Sequential) Collided(string) {}
+
+// Hashids кодирует номера последовательности хранилища так, чтобы соседние номера давали непохожие коды,
+// по схеме hashids: первый символ кода выбирается по номеру и определяет перемешивание алфавита остальных символов.
+type Hashids struct {
+	sequence Sequence
+	// alphabet алфавит, перемешанный с солью.
+	alphabet string
+	salt     string
+	// offset прибавляется к номеру, чтобы код был не короче заданной длины.
+	offset uint64
+}
+
+// NewHashids создает генератор, который перемешивает номера из sequence с солью salt, с кодами не короче length.
+func NewHashids(sequence Sequence, alphabet, salt string, length int) *Hashids {
+	offset, _ := minNumber(len(alphabet), length-1)
+	return &Hashids{sequence: sequence, alphabet: shuffle(alphabet, salt), salt: salt, offset: offset}
+}
+
+// Generate возвращает код следующего номера последовательности.
+func (generator *Hashids) Generate(ctx context.Context) (string, error) {
+	number, err := nextNumber(ctx, generator.sequence)
+	if err != nil {
+		return "", err
+	}
+	return generator.encode(generator.offset + number), nil
+}
+
+// encode кодирует номер. Номера с одинаковым первым символом кодируются одним и тем же перемешанным алфавитом,
+// поэтому разные номера всегда дают разные коды.
+func (generator *Hashids) encode(number uint64) string {
+	lottery := generator.alphabet[number%uint64(len(generator.alphabet))]
+	buffer := string(lottery) + generator.salt + generator.alphabet
+	alphabet := shuffle(generator.alphabet, buffer[:len(generator.alphabet)])
+	return string(lottery) + encode(number, alphabet)
+}
+
+// Collided ничего не делает: следующий номер последовательности дает другой код.
+// Коллизия возможна только с пользовательским сокращенным URL.
+func (generator *Hashids) Collided(string) {}
+
+// nextNumber возвращает следующий номер последовательности.
+func nextNumber(ctx context.Context, sequence Sequence) (uint64, error) {
+	if sequence == nil {
+		return 0, errors.New("short code sequence isn't configured")
+	}
+	number, err := sequence.NextSequence(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(number), nil
+}
+
+// shuffle детерминированно перемешивает алфавит с солью salt так же, как это делает hashids.
+func shuffle(alphabet, salt string) string {
+	if salt == "" {
+		return alphabet
+	}
+	result := []byte(alphabet)
+	for i, v, p := len(result)-1, 0, 0; i > 0; i-- {
+		v %= len(salt)
+		n := int(salt[v])
+		p += n
+		j := (n + v + p) % i
+		result[i], result[j] = result[j], result[i]
+		v++
+	}
+	return string(result)
+}
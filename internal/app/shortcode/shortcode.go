@@ -0,0 +1,136 @@
+// Package shortcode предоставляет стратегии генерации сокращенных URL.
+//
+// Генератор только предлагает код: уникальность проверяется хранилищем атомарно при сохранении,
+// а при конфликте сервис сообщает генератору о коллизии и запрашивает новый код.
+//
+// Поддерживаются стратегии:
+//   - random — случайный код из криптографически стойкого источника; длина кода растет, когда коллизии
+//     учащаются, то есть когда пространство кодов текущей длины заполняется;
+//   - sequence — номер из последовательности хранилища в системе счисления по алфавиту (base62 для алфавита по умолчанию);
+//   - hashids — номер из последовательности хранилища, перемешанный с солью, чтобы соседние коды не были похожи.
+//
+// Для последовательных стратегий длина кода растет вместе с номером, а заданная длина служит минимальной.
+package shortcode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/config"
+)
+
+// Стратегии генерации сокращенных URL.
+const (
+	StrategyRandom   = "random"
+	StrategySequence = "sequence"
+	StrategyHashids  = "hashids"
+)
+
+// DefaultAlphabet алфавит сокращенных URL по умолчанию.
+const DefaultAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// defaultLength длина сокращенного URL, если она не задана в конфигурации.
+const defaultLength = config.DefaultShortCodeLength
+
+// maxLength максимальная длина сокращенного URL.
+const maxLength = 32
+
+// Generator предлагает коды сокращенных URL.
+type Generator interface {
+	// Generate возвращает новый код.
+	Generate(ctx context.Context) (string, error)
+	// Collided сообщает генератору, что выданный им код уже занят.
+	Collided(code string)
+}
+
+// Sequence выдает номера последовательности сокращенных URL.
+type Sequence interface {
+	NextSequence(ctx context.Context) (int64, error)
+}
+
+// New создает генератор стратегии из конфигурации. Последовательные стратегии берут номера из sequence.
+func New(config config.Config, sequence Sequence) (Generator, error) {
+	alphabet := config.ShortCodeAlphabet
+	if alphabet == "" {
+		alphabet = DefaultAlphabet
+	}
+	if err := validateAlphabet(alphabet); err != nil {
+		return nil, err
+	}
+	length := config.ShortCodeLength
+	if length == 0 {
+		length = defaultLength
+	}
+	if length < 1 || length > maxLength {
+		return nil, fmt.Errorf("short code length must be between 1 and %d", maxLength)
+	}
+	switch config.ShortCodeStrategy {
+	case "", StrategyRandom:
+		return NewRandom(alphabet, length), nil
+	case StrategySequence:
+		if _, ok := minNumber(len(alphabet), length); !ok {
+			return nil, errors.New("short code length is too large for sequence strategy")
+		}
+		return NewSequential(sequence, alphabet, length), nil
+	case StrategyHashids:
+		if _, ok := minNumber(len(alphabet), length-1); !ok {
+			return nil, errors.New("short code length is too large for hashids strategy")
+		}
+		return NewHashids(sequence, alphabet, config.ShortCodeSalt, length), nil
+	default:
+		return nil, errors.New("unknown short code strategy: " + config.ShortCodeStrategy)
+	}
+}
+
+// validateAlphabet проверяет, что алфавит состоит хотя бы из двух различных символов, допустимых в сокращенном URL.
+func validateAlphabet(alphabet string) error {
+	if len(alphabet) < 2 {
+		return errors.New("short code alphabet must contain at least 2 characters")
+	}
+	for i := 0; i < len(alphabet); i++ {
+		c := alphabet[i]
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_') {
+			return fmt.Errorf("short code alphabet contains invalid character %q", c)
+		}
+		if strings.IndexByte(alphabet[:i], c) >= 0 {
+			return fmt.Errorf("short code alphabet contains duplicate character %q", c)
+		}
+	}
+	return nil
+}
+
+// encode записывает number в системе счисления по алфавиту alphabet.
+func encode(number uint64, alphabet string) string {
+	base := uint64(len(alphabet))
+	var buf [64]byte
+	i := len(buf)
+	for {
+		i--
+		buf[i] = alphabet[number%base]
+		number /= base
+		if number == 0 {
+			break
+		}
+	}
+	return string(buf[i:])
+}
+
+// minNumber возвращает наименьшее число, запись которого в системе счисления с основанием base
+// содержит не меньше digits цифр, чтобы код не был короче заданной длины.
+// Возвращает false, если такое число не помещается в uint64.
+func minNumber(base, digits int) (uint64, bool) {
+	number := uint64(1)
+	for i := 1; i < digits; i++ {
+		if number > math.MaxUint64/uint64(base) {
+			return 0, false
+		}
+		number *= uint64(base)
+	}
+	if digits <= 1 {
+		return 0, true
+	}
+	return number, true
+}
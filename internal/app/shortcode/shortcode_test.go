@@ -0,0 +1,142 @@
+package shortcode
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// counter выдает номера последовательности подряд, начиная с 1.
+type counter struct {
+	next int64
+}
+
+func (c *counter) NextSequence(context.Context) (int64, error) {
+	c.next++
+	return c.next, nil
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*config.Config)
+		wantErr bool
+	}{
+		{name: "default", modify: func(*config.Config) {}},
+		{name: "zero length uses default", modify: func(c *config.Config) { c.ShortCodeLength = 0 }},
+		{name: "sequence", modify: func(c *config.Config) { c.ShortCodeStrategy = StrategySequence }},
+		{name: "hashids", modify: func(c *config.Config) { c.ShortCodeStrategy = StrategyHashids }},
+		{name: "unknown strategy", modify: func(c *config.Config) { c.ShortCodeStrategy = "uuid" }, wantErr: true},
+		{name: "negative length", modify: func(c *config.Config) { c.ShortCodeLength = -1 }, wantErr: true},
+		{name: "too long", modify: func(c *config.Config) { c.ShortCodeLength = 33 }, wantErr: true},
+		{name: "sequence too long", modify: func(c *config.Config) {
+			c.ShortCodeStrategy = StrategySequence
+			c.ShortCodeLength = 20
+		}, wantErr: true},
+		{name: "short alphabet", modify: func(c *config.Config) { c.ShortCodeAlphabet = "a" }, wantErr: true},
+		{name: "duplicate in alphabet", modify: func(c *config.Config) { c.ShortCodeAlphabet = "abca" }, wantErr: true},
+		{name: "invalid character in alphabet", modify: func(c *config.Config) { c.ShortCodeAlphabet = "ab/" }, wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := config.GetDefault()
+			test.modify(&config)
+			_, err := New(config, &counter{})
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestRandom(t *testing.T) {
+	generator := NewRandom("ab", 10)
+	codes := make(map[string]struct{})
+	for i := 0; i < 100; i++ {
+		code, err := generator.Generate(context.Background())
+		require.NoError(t, err)
+		assert.Len(t, code, 10)
+		assert.Empty(t, strings.Trim(code, "ab"))
+		codes[code] = struct{}{}
+	}
+	assert.Greater(t, len(codes), 90)
+}
+
+func TestRandomGrowsWhenCollisionsAreFrequent(t *testing.T) {
+	generator := NewRandom(DefaultAlphabet, 5)
+	// Редкие коллизии не увеличивают длину
+	for i := 0; i < 200; i++ {
+		_, err := generator.Generate(context.Background())
+		require.NoError(t, err)
+	}
+	generator.Collided("")
+	generator.Collided("")
+	generator.Collided("")
+	assert.Equal(t, 5, generator.Length())
+
+	// Каждая десятая коллизия означает, что пространство кодов заполнено
+	generator = NewRandom(DefaultAlphabet, 5)
+	for i := 0; i < 30; i++ {
+		code, err := generator.Generate(context.Background())
+		require.NoError(t, err)
+		if i%10 == 0 {
+			generator.Collided(code)
+		}
+	}
+	assert.Equal(t, 6, generator.Length())
+	code, err := generator.Generate(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, code, 6)
+}
+
+func TestSequential(t *testing.T) {
+	generator := NewSequential(&counter{}, "0123456789", 1)
+	for _, want := range []string{"1", "2", "3"} {
+		code, err := generator.Generate(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, want, code)
+	}
+
+	// Заданная длина служит минимальной, дальше длина растет вместе с номером
+	generator = NewSequential(&counter{next: 88}, "0123456789", 2)
+	for _, want := range []string{"99", "100"} {
+		code, err := generator.Generate(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, want, code)
+	}
+}
+
+func TestHashids(t *testing.T) {
+	generator := NewHashids(&counter{}, DefaultAlphabet, "salt", 6)
+	codes := make(map[string]struct{})
+	previous := ""
+	for i := 0; i < 20000; i++ {
+		code, err := generator.Generate(context.Background())
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, len(code), 6)
+		if _, ok := codes[code]; ok {
+			t.Fatalf("duplicate code %q at %d", code, i)
+		}
+		if previous != "" {
+			assert.NotEqual(t, previous[:len(previous)-1], code[:len(code)-1], "neighbour codes must differ not only in the last character")
+		}
+		codes[code] = struct{}{}
+		previous = code
+	}
+
+	// Коды зависят от соли и воспроизводимы
+	first, err := NewHashids(&counter{}, DefaultAlphabet, "salt", 6).Generate(context.Background())
+	require.NoError(t, err)
+	other, err := NewHashids(&counter{}, DefaultAlphabet, "pepper", 6).Generate(context.Background())
+	require.NoError(t, err)
+	again, err := NewHashids(&counter{}, DefaultAlphabet, "salt", 6).Generate(context.Background())
+	require.NoError(t, err)
+	assert.NotEqual(t, first, other)
+	assert.Equal(t, first, again)
+}
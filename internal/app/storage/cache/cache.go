@@ -0,0 +1,252 @@
+// Package cache предоставляет кеширующую обертку над хранилищем URL, которая ускоряет редиректы.
+//
+// Поиск по сокращенному URL сначала обращается к локальному LRU-кешу, затем к удаленному кешу,
+// совместимому с протоколом Redis, и только потом к хранилищу. Записи кеша сбрасываются, когда URL
+// сохраняется, помечается удаленным, восстанавливается, отключается или передается другому пользователю
+// через эту обертку. Изменения, сделанные другими экземплярами сервиса, видны в локальном кеше не позже,
+// чем истечет время жизни записи.
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/config"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/logger"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/models"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/storage"
+)
+
+// Metrics счетчики обращений к кешу.
+type Metrics struct {
+	LocalHits    uint64 // LocalHits количество URL, найденных в локальном кеше.
+	LocalMisses  uint64 // LocalMisses количество URL, не найденных в локальном кеше.
+	RemoteHits   uint64 // RemoteHits количество URL, найденных в удаленном кеше.
+	RemoteMisses uint64 // RemoteMisses количество URL, не найденных в удаленном кеше.
+	RemoteErrors uint64 // RemoteErrors количество ошибок обращения к удаленному кешу.
+}
+
+// Storage кеширующая обертка над хранилищем URL.
+type Storage struct {
+	storage.ShortenerStorage
+	// local локальный кеш, nil если отключен.
+	local *LRU
+	// remote удаленный кеш, nil если не задан.
+	remote *Redis
+
+	localHits    atomic.Uint64
+	localMisses  atomic.Uint64
+	remoteHits   atomic.Uint64
+	remoteMisses atomic.Uint64
+	remoteErrors atomic.Uint64
+}
+
+// New оборачивает хранилище backend кешем по конфигурации: локальный кеш включается при положительном CacheSize,
+// удаленный — если задан CacheRedisURL. Если оба отключены, обертка передает вызовы хранилищу без изменений.
+func New(backend storage.ShortenerStorage, config config.Config) (*Storage, error) {
+	cache := &Storage{ShortenerStorage: backend}
+	ttl := config.CacheTTL.Duration
+	if ttl <= 0 {
+		ttl = time.Minute
+	}
+	if config.CacheSize > 0 {
+		cache.local = NewLRU(config.CacheSize, ttl)
+	}
+	if config.CacheRedisURL != "" {
+		remote, err := NewRedis(config.CacheRedisURL, ttl)
+		if err != nil {
+			return nil, err
+		}
+		cache.remote = remote
+	}
+	return cache, nil
+}
+
+// FindByShortURL находит URL по сокращенному URL в кеше, а если его там нет — в хранилище, и кеширует результат.
+// Недоступность удаленного кеша не мешает поиску в хранилище.
+func (cache *Storage) FindByShortURL(ctx context.Context, shortURL string) (*models.URL, error) {
+	now := time.Now()
+	if cache.local != nil {
+		if url, ok := cache.local.Get(shortURL, now); ok {
+			cache.localHits.Add(1)
+			return &url, nil
+		}
+		cache.localMisses.Add(1)
+	}
+	if cache.remote != nil {
+		url, ok, err := cache.remote.Get(ctx, shortURL)
+		switch {
+		case err != nil:
+			cache.remoteError("get", err)
+		case ok:
+			cache.remoteHits.Add(1)
+			if cache.local != nil {
+				cache.local.Set(shortURL, url, now)
+			}
+			return &url, nil
+		default:
+			cache.remoteMisses.Add(1)
+		}
+	}
+	url, err := cache.ShortenerStorage.FindByShortURL(ctx, shortURL)
+	if err != nil {
+		return nil, err
+	}
+	if cache.local != nil {
+		cache.local.Set(shortURL, *url, now)
+	}
+	if cache.remote != nil {
+		if err := cache.remote.Set(ctx, *url); err != nil {
+			cache.remoteError("set", err)
+		}
+	}
+	return url, nil
+}
+
+// Save сохраняет URL в хранилище и сбрасывает запись его сокращенного URL,
+// которая могла остаться от удаленного из хранилища URL.
+func (cache *Storage) Save(ctx context.Context, url models.URL) error {
+	if err := cache.ShortenerStorage.Save(ctx, url); err != nil {
+		return err
+	}
+	cache.invalidate(ctx, url.ShortURL)
+	return nil
+}
+
+// SaveBatchPartial сохраняет URL из списка независимо друг от друга и сбрасывает записи сохраненных сокращенных URL.
+func (cache *Storage) SaveBatchPartial(ctx context.Context, urls []models.URL) ([]error, error) {
+	errs, err := cache.ShortenerStorage.SaveBatchPartial(ctx, urls)
+	if err != nil {
+		return nil, err
+	}
+	shortURLs := make([]string, 0, len(urls))
+	for i, url := range urls {
+		if errs[i] == nil {
+			shortURLs = append(shortURLs, url.ShortURL)
+		}
+	}
+	cache.invalidate(ctx, shortURLs...)
+	return errs, nil
+}
+
+// DeleteUrls помечает URL удаленными в хранилище и сбрасывает записи удаленных URL.
+func (cache *Storage) DeleteUrls(ctx context.Context, urls []models.URLToDelete) ([]string, error) {
+	deleted, err := cache.ShortenerStorage.DeleteUrls(ctx, urls)
+	if err != nil {
+		return nil, err
+	}
+	cache.invalidate(ctx, deleted...)
+	return deleted, nil
+}
+
+// RestoreUrls восстанавливает URL в хранилище и сбрасывает записи восстановленных URL.
+func (cache *Storage) RestoreUrls(ctx context.Context, userID int, shortURLs []string, deletedAfter time.Time) ([]string, error) {
+	restored, err := cache.ShortenerStorage.RestoreUrls(ctx, userID, shortURLs, deletedAfter)
+	if err != nil {
+		return nil, err
+	}
+	cache.invalidate(ctx, restored...)
+	return restored, nil
+}
+
+// SetURLDisabled отключает или включает URL в хранилище и сбрасывает его запись.
+func (cache *Storage) SetURLDisabled(ctx context.Context, shortURL string, disabled bool) error {
+	if err := cache.ShortenerStorage.SetURLDisabled(ctx, shortURL, disabled); err != nil {
+		return err
+	}
+	cache.invalidate(ctx, shortURL)
+	return nil
+}
+
+// SetURLOwner передает URL пользователю в хранилище и сбрасывает его запись.
+func (cache *Storage) SetURLOwner(ctx context.Context, shortURL string, userID int) error {
+	if err := cache.ShortenerStorage.SetURLOwner(ctx, shortURL, userID); err != nil {
+		return err
+	}
+	cache.invalidate(ctx, shortURL)
+	return nil
+}
+
+// DeleteUrlsOfUser помечает удаленными все URL пользователя в хранилище и сбрасывает записи удаленных URL.
+func (cache *Storage) DeleteUrlsOfUser(ctx context.Context, userID int) ([]string, error) {
+	deleted, err := cache.ShortenerStorage.DeleteUrlsOfUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	cache.invalidate(ctx, deleted...)
+	return deleted, nil
+}
+
+// DeleteUrlsOfDomain помечает удаленными все URL на домен в хранилище и сбрасывает записи удаленных URL.
+func (cache *Storage) DeleteUrlsOfDomain(ctx context.Context, domain string) ([]string, error) {
+	deleted, err := cache.ShortenerStorage.DeleteUrlsOfDomain(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+	cache.invalidate(ctx, deleted...)
+	return deleted, nil
+}
+
+// DeleteExpired удаляет из хранилища истекшие URL и очищает локальный кеш, если что-то было удалено.
+// Истекшие URL в удаленном кеше отбрасываются при чтении сервисом по сроку действия и вытесняются по времени жизни записи.
+func (cache *Storage) DeleteExpired(ctx context.Context, now time.Time) (int, error) {
+	count, err := cache.ShortenerStorage.DeleteExpired(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+	if count > 0 && cache.local != nil {
+		cache.local.Purge()
+	}
+	return count, nil
+}
+
+// Close закрывает соединения с удаленным кешем и хранилище.
+func (cache *Storage) Close() error {
+	var err error
+	if cache.remote != nil {
+		err = cache.remote.Close()
+	}
+	return errors.Join(err, cache.ShortenerStorage.Close())
+}
+
+// PingCache проверяет доступность удаленного кеша. Локальный кеш всегда доступен,
+// поэтому без удаленного кеша проверка всегда успешна.
+func (cache *Storage) PingCache(ctx context.Context) error {
+	if cache.remote == nil {
+		return nil
+	}
+	return cache.remote.Ping(ctx)
+}
+
+// Metrics возвращает значения счетчиков обращений к кешу.
+func (cache *Storage) Metrics() Metrics {
+	return Metrics{
+		LocalHits:    cache.localHits.Load(),
+		LocalMisses:  cache.localMisses.Load(),
+		RemoteHits:   cache.remoteHits.Load(),
+		RemoteMisses: cache.remoteMisses.Load(),
+		RemoteErrors: cache.remoteErrors.Load(),
+	}
+}
+
+// invalidate сбрасывает записи сокращенных URL в локальном и удаленном кешах.
+func (cache *Storage) invalidate(ctx context.Context, shortURLs ...string) {
+	if len(shortURLs) == 0 {
+		return
+	}
+	if cache.local != nil {
+		cache.local.Delete(shortURLs...)
+	}
+	if cache.remote != nil {
+		if err := cache.remote.Delete(ctx, shortURLs...); err != nil {
+			cache.remoteError("delete", err)
+		}
+	}
+}
+
+func (cache *Storage) remoteError(op string, err error) {
+	cache.remoteErrors.Add(1)
+	logger.Logger.Warn("remote cache error", "op", op, "error", err)
+}
@@ -0,0 +1,252 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/config"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/logger"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/models"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/storage/inmemory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRU(t *testing.T) {
+	now := time.Now()
+	lru := NewLRU(2, time.Minute)
+	lru.Set("a", models.URL{ShortURL: "a"}, now)
+	lru.Set("b", models.URL{ShortURL: "b"}, now)
+	_, ok := lru.Get("a", now)
+	assert.True(t, ok)
+
+	// "b" давно не запрашивался и вытесняется первым
+	lru.Set("c", models.URL{ShortURL: "c"}, now)
+	assert.Equal(t, 2, lru.Len())
+	_, ok = lru.Get("b", now)
+	assert.False(t, ok)
+	_, ok = lru.Get("a", now)
+	assert.True(t, ok)
+
+	_, ok = lru.Get("c", now.Add(time.Minute))
+	assert.False(t, ok, "entry must expire after ttl")
+
+	lru.Delete("a")
+	_, ok = lru.Get("a", now)
+	assert.False(t, ok)
+	lru.Set("d", models.URL{ShortURL: "d"}, now)
+	lru.Purge()
+	assert.Equal(t, 0, lru.Len())
+}
+
+func TestStorageLocal(t *testing.T) {
+	ctx := context.Background()
+	backend := inmemory.NewInMemoryStorage(config.Config{})
+	cache, err := New(backend, config.Config{CacheSize: 10})
+	require.NoError(t, err)
+	url := models.URL{ShortURL: "abc", OriginalURL: "https://example.com", CreatedBy: 1}
+	require.NoError(t, cache.Save(ctx, url))
+
+	found, err := cache.FindByShortURL(ctx, "abc")
+	require.NoError(t, err)
+	assert.False(t, found.IsDeleted)
+	found, err = cache.FindByShortURL(ctx, "abc")
+	require.NoError(t, err)
+	assert.Equal(t, url.OriginalURL, found.OriginalURL)
+	assert.Equal(t, Metrics{LocalHits: 1, LocalMisses: 1}, cache.Metrics())
+
+	deleted, err := cache.DeleteUrls(ctx, []models.URLToDelete{{UserID: 1, ShortURL: "abc"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"abc"}, deleted)
+	found, err = cache.FindByShortURL(ctx, "abc")
+	require.NoError(t, err)
+	assert.True(t, found.IsDeleted, "deletion must invalidate cached url")
+
+	restored, err := cache.RestoreUrls(ctx, 1, []string{"abc"}, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"abc"}, restored)
+	found, err = cache.FindByShortURL(ctx, "abc")
+	require.NoError(t, err)
+	assert.False(t, found.IsDeleted, "restore must invalidate cached url")
+
+	_, err = cache.FindByShortURL(ctx, "missing")
+	assert.Error(t, err)
+}
+
+func TestStorageRemote(t *testing.T) {
+	require.NoError(t, logger.Init(slog.LevelInfo))
+	ctx := context.Background()
+	server := newFakeRedis(t)
+	backend := inmemory.NewInMemoryStorage(config.Config{})
+	cache, err := New(backend, config.Config{CacheSize: -1, CacheRedisURL: "redis://:secret@" + server.addr + "/2"})
+	require.NoError(t, err)
+	defer cache.Close()
+	require.NoError(t, cache.PingCache(ctx))
+	require.NoError(t, cache.Save(ctx, models.URL{ShortURL: "abc", OriginalURL: "https://example.com", CreatedBy: 1}))
+
+	_, err = cache.FindByShortURL(ctx, "abc")
+	require.NoError(t, err)
+	assert.True(t, server.has(redisKeyPrefix+"abc"))
+
+	// второй экземпляр сервиса находит URL в удаленном кеше без обращения к хранилищу
+	other, err := New(inmemory.NewInMemoryStorage(config.Config{}), config.Config{CacheSize: -1, CacheRedisURL: "redis://:secret@" + server.addr + "/2"})
+	require.NoError(t, err)
+	defer other.Close()
+	found, err := other.FindByShortURL(ctx, "abc")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com", found.OriginalURL)
+	assert.Equal(t, Metrics{RemoteHits: 1}, other.Metrics())
+
+	_, err = cache.DeleteUrls(ctx, []models.URLToDelete{{UserID: 1, ShortURL: "abc"}})
+	require.NoError(t, err)
+	assert.False(t, server.has(redisKeyPrefix+"abc"))
+	assert.Equal(t, Metrics{RemoteMisses: 1}, cache.Metrics())
+}
+
+func TestStorageRemoteUnavailable(t *testing.T) {
+	require.NoError(t, logger.Init(slog.LevelInfo))
+	ctx := context.Background()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	listener.Close()
+
+	backend := inmemory.NewInMemoryStorage(config.Config{})
+	cache, err := New(backend, config.Config{CacheSize: -1, CacheRedisURL: "redis://" + addr})
+	require.NoError(t, err)
+	require.NoError(t, backend.Save(ctx, models.URL{ShortURL: "abc", OriginalURL: "https://example.com"}))
+
+	found, err := cache.FindByShortURL(ctx, "abc")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com", found.OriginalURL)
+	assert.Equal(t, uint64(2), cache.Metrics().RemoteErrors)
+	assert.Error(t, cache.PingCache(ctx))
+}
+
+func TestNewRedisInvalid(t *testing.T) {
+	for _, rawURL := range []string{"localhost:6379", "http://localhost:6379", "redis://localhost:6379/db"} {
+		_, err := NewRedis(rawURL, time.Minute)
+		assert.Error(t, err, rawURL)
+	}
+}
+
+// fakeRedis локальная замена Redis, которая понимает команды AUTH, SELECT, PING, GET, SET и DEL.
+type fakeRedis struct {
+	addr string
+	mu   sync.Mutex
+	data map[string]string
+}
+
+func newFakeRedis(t *testing.T) *fakeRedis {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+	server := &fakeRedis{addr: listener.Addr().String(), data: make(map[string]string)}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+	return server
+}
+
+func (server *fakeRedis) has(key string) bool {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	_, ok := server.data[key]
+	return ok
+}
+
+func (server *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	authorized := false
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+		var reply string
+		server.mu.Lock()
+		switch strings.ToUpper(args[0]) {
+		case "AUTH":
+			authorized = args[1] == "secret"
+			reply = "+OK\r\n"
+			if !authorized {
+				reply = "-WRONGPASS invalid password\r\n"
+			}
+		case "SELECT":
+			reply = "+OK\r\n"
+		case "PING":
+			reply = "+PONG\r\n"
+		case "GET":
+			value, ok := server.data[args[1]]
+			reply = "$-1\r\n"
+			if ok {
+				reply = fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+			}
+		case "SET":
+			server.data[args[1]] = args[2]
+			reply = "+OK\r\n"
+		case "DEL":
+			deleted := 0
+			for _, key := range args[1:] {
+				if _, ok := server.data[key]; ok {
+					delete(server.data, key)
+					deleted++
+				}
+			}
+			reply = fmt.Sprintf(":%d\r\n", deleted)
+		default:
+			reply = "-ERR unknown command\r\n"
+		}
+		if !authorized {
+			reply = "-NOAUTH Authentication required\r\n"
+		}
+		server.mu.Unlock()
+		if _, err := io.WriteString(conn, reply); err != nil {
+			return
+		}
+	}
+}
+
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "*")))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, count)
+	for i := range args {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "$")))
+		if err != nil {
+			return nil, err
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return nil, err
+		}
+		args[i] = string(data[:size])
+	}
+	return args, nil
+}
@@ -0,0 +1,102 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/models"
+)
+
+// lruEntry запись локального кеша.
+type lruEntry struct {
+	key       string
+	url       models.URL
+	expiresAt time.Time
+}
+
+// LRU локальный кеш URL с ограничением количества записей и временем жизни записи.
+// При переполнении вытесняется запись, к которой дольше всего не обращались.
+type LRU struct {
+	capacity int
+	ttl      time.Duration
+
+	mu    sync.Mutex
+	order *list.List
+	items map[string]*list.Element
+}
+
+// NewLRU создает локальный кеш не более чем на capacity записей со временем жизни записи ttl.
+func NewLRU(capacity int, ttl time.Duration) *LRU {
+	return &LRU{
+		capacity: capacity,
+		ttl:      ttl,
+		order:    list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+// Get возвращает URL по ключу, если запись есть и ее время жизни к моменту now не истекло.
+func (lru *LRU) Get(key string, now time.Time) (models.URL, bool) {
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+	element, ok := lru.items[key]
+	if !ok {
+		return models.URL{}, false
+	}
+	entry := element.Value.(*lruEntry)
+	if !now.Before(entry.expiresAt) {
+		lru.remove(element)
+		return models.URL{}, false
+	}
+	lru.order.MoveToFront(element)
+	return entry.url, true
+}
+
+// Set сохраняет URL по ключу.
+func (lru *LRU) Set(key string, url models.URL, now time.Time) {
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+	if element, ok := lru.items[key]; ok {
+		entry := element.Value.(*lruEntry)
+		entry.url = url
+		entry.expiresAt = now.Add(lru.ttl)
+		lru.order.MoveToFront(element)
+		return
+	}
+	lru.items[key] = lru.order.PushFront(&lruEntry{key: key, url: url, expiresAt: now.Add(lru.ttl)})
+	for lru.order.Len() > lru.capacity {
+		lru.remove(lru.order.Back())
+	}
+}
+
+// Delete удаляет записи по ключам.
+func (lru *LRU) Delete(keys ...string) {
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+	for _, key := range keys {
+		if element, ok := lru.items[key]; ok {
+			lru.remove(element)
+		}
+	}
+}
+
+// Purge удаляет все записи.
+func (lru *LRU) Purge() {
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+	lru.order.Init()
+	lru.items = make(map[string]*list.Element)
+}
+
+// Len возвращает количество записей.
+func (lru *LRU) Len() int {
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+	return lru.order.Len()
+}
+
+func (lru *LRU) remove(element *list.Element) {
+	lru.order.Remove(element)
+	delete(lru.items, element.Value.(*lruEntry).key)
+}
@@ -0,0 +1,267 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/models"
+)
+
+const (
+	// redisKeyPrefix префикс ключей URL в удаленном кеше.
+	redisKeyPrefix = "shortener:url:"
+	// redisTimeout время ожидания ответа удаленного кеша, если у контекста нет своего срока.
+	redisTimeout = 500 * time.Millisecond
+	// redisPoolSize количество соединений, которые удерживаются открытыми между запросами.
+	redisPoolSize = 8
+)
+
+// errRedisNil ответ удаленного кеша на запрос отсутствующего ключа.
+var errRedisNil = errors.New("redis: nil")
+
+// RedisError ошибка, которую вернул удаленный кеш.
+type RedisError string
+
+// Error возвращает текст ошибки удаленного кеша.
+func (err RedisError) Error() string {
+	return "redis: " + string(err)
+}
+
+// Redis удаленный кеш URL, совместимый с протоколом Redis (RESP).
+// Используются только команды AUTH, SELECT, PING, GET, SET с PX и DEL, поэтому подходит и любая совместимая замена Redis.
+type Redis struct {
+	addr     string
+	password string
+	db       int
+	ttl      time.Duration
+	pool     chan *redisConn
+}
+
+// redisConn соединение с удаленным кешем.
+type redisConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// NewRedis создает удаленный кеш по адресу в формате redis://[:password@]host:port[/db]
+// со временем жизни записи ttl. Соединения устанавливаются при первом обращении.
+func NewRedis(rawURL string, ttl time.Duration) (*Redis, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if parsed.Scheme != "redis" || parsed.Host == "" {
+		return nil, fmt.Errorf("cache redis url %q must be in format redis://[:password@]host:port[/db]", rawURL)
+	}
+	redis := &Redis{
+		addr: parsed.Host,
+		ttl:  ttl,
+		pool: make(chan *redisConn, redisPoolSize),
+	}
+	if _, _, err := net.SplitHostPort(redis.addr); err != nil {
+		redis.addr = net.JoinHostPort(redis.addr, "6379")
+	}
+	if password, ok := parsed.User.Password(); ok {
+		redis.password = password
+	}
+	if db := strings.TrimPrefix(parsed.Path, "/"); db != "" {
+		redis.db, err = strconv.Atoi(db)
+		if err != nil {
+			return nil, fmt.Errorf("cache redis db %q must be a number", db)
+		}
+	}
+	return redis, nil
+}
+
+// Get возвращает URL по сокращенному URL. Второе значение false означает, что записи нет.
+func (redis *Redis) Get(ctx context.Context, shortURL string) (models.URL, bool, error) {
+	reply, err := redis.do(ctx, "GET", redisKeyPrefix+shortURL)
+	if errors.Is(err, errRedisNil) {
+		return models.URL{}, false, nil
+	}
+	if err != nil {
+		return models.URL{}, false, err
+	}
+	data, ok := reply.(string)
+	if !ok {
+		return models.URL{}, false, fmt.Errorf("redis: unexpected reply %v", reply)
+	}
+	var url models.URL
+	if err := json.Unmarshal([]byte(data), &url); err != nil {
+		return models.URL{}, false, err
+	}
+	return url, true, nil
+}
+
+// Set сохраняет URL со временем жизни записи кеша.
+func (redis *Redis) Set(ctx context.Context, url models.URL) error {
+	data, err := json.Marshal(url)
+	if err != nil {
+		return err
+	}
+	_, err = redis.do(ctx, "SET", redisKeyPrefix+url.ShortURL, string(data), "PX", strconv.FormatInt(redis.ttl.Milliseconds(), 10))
+	return err
+}
+
+// Delete удаляет записи сокращенных URL.
+func (redis *Redis) Delete(ctx context.Context, shortURLs ...string) error {
+	if len(shortURLs) == 0 {
+		return nil
+	}
+	args := make([]string, 0, len(shortURLs)+1)
+	args = append(args, "DEL")
+	for _, shortURL := range shortURLs {
+		args = append(args, redisKeyPrefix+shortURL)
+	}
+	_, err := redis.do(ctx, args...)
+	return err
+}
+
+// Ping проверяет доступность удаленного кеша.
+func (redis *Redis) Ping(ctx context.Context) error {
+	reply, err := redis.do(ctx, "PING")
+	if err != nil {
+		return err
+	}
+	if reply != "PONG" {
+		return fmt.Errorf("redis: unexpected reply %v", reply)
+	}
+	return nil
+}
+
+// Close закрывает соединения с удаленным кешем.
+func (redis *Redis) Close() error {
+	var errs []error
+	for {
+		select {
+		case conn := <-redis.pool:
+			errs = append(errs, conn.conn.Close())
+		default:
+			return errors.Join(errs...)
+		}
+	}
+}
+
+// do выполняет команду и возвращает ответ: string для строк и int64 для чисел.
+// Соединение, на котором произошла ошибка ввода-вывода, закрывается.
+func (redis *Redis) do(ctx context.Context, args ...string) (any, error) {
+	conn, err := redis.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(redisTimeout)
+	}
+	if err := conn.conn.SetDeadline(deadline); err != nil {
+		conn.conn.Close()
+		return nil, err
+	}
+	reply, err := conn.command(args...)
+	var redisErr RedisError
+	if err != nil && !errors.Is(err, errRedisNil) && !errors.As(err, &redisErr) {
+		conn.conn.Close()
+		return nil, err
+	}
+	redis.put(conn)
+	return reply, err
+}
+
+// get берет соединение из пула или устанавливает новое.
+func (redis *Redis) get(ctx context.Context) (*redisConn, error) {
+	select {
+	case conn := <-redis.pool:
+		return conn, nil
+	default:
+	}
+	ctx, cancel := context.WithTimeout(ctx, redisTimeout)
+	defer cancel()
+	var dialer net.Dialer
+	netConn, err := dialer.DialContext(ctx, "tcp", redis.addr)
+	if err != nil {
+		return nil, err
+	}
+	conn := &redisConn{conn: netConn, reader: bufio.NewReader(netConn)}
+	if deadline, ok := ctx.Deadline(); ok {
+		netConn.SetDeadline(deadline)
+	}
+	if redis.password != "" {
+		if _, err := conn.command("AUTH", redis.password); err != nil {
+			netConn.Close()
+			return nil, err
+		}
+	}
+	if redis.db != 0 {
+		if _, err := conn.command("SELECT", strconv.Itoa(redis.db)); err != nil {
+			netConn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+// put возвращает соединение в пул или закрывает его, если пул заполнен.
+func (redis *Redis) put(conn *redisConn) {
+	select {
+	case redis.pool <- conn:
+	default:
+		conn.conn.Close()
+	}
+}
+
+// command отправляет команду массивом bulk-строк и читает ответ.
+func (conn *redisConn) command(args ...string) (any, error) {
+	var request strings.Builder
+	fmt.Fprintf(&request, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&request, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := io.WriteString(conn.conn, request.String()); err != nil {
+		return nil, err
+	}
+	return readReply(conn.reader)
+}
+
+// readReply читает ответ в формате RESP. Массивы не поддерживаются: используемые команды их не возвращают.
+func readReply(reader *bufio.Reader) (any, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, errors.New("redis: empty reply")
+	}
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, RedisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if size < 0 {
+			return nil, errRedisNil
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return nil, err
+		}
+		return string(data[:size]), nil
+	default:
+		return nil, fmt.Errorf("redis: unexpected reply %q", line)
+	}
+}
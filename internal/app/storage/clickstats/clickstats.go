@@ -0,0 +1,108 @@
+// Package clickstats предоставляет функции для расчета статистики переходов по сокращенным URL.
+// Используется хранилищами, которые не умеют агрегировать события перехода самостоятельно.
+package clickstats
+
+import (
+	"sort"
+
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/models"
+)
+
+// DateLayout формат даты, используемый для группировки переходов по дням.
+const DateLayout = "2006-01-02"
+
+// Aggregate рассчитывает статистику переходов по сокращенному URL.
+// В списки самых частых источников и клиентов попадает не более top значений.
+func Aggregate(shortURL string, clicks []models.Click, top int) models.LinkStats {
+	counter := NewCounter()
+	for _, click := range clicks {
+		if click.ShortURL == shortURL {
+			counter.Add(click)
+		}
+	}
+	return counter.Stats(shortURL, top)
+}
+
+// Counter накапливает статистику переходов по одному сокращенному URL без хранения самих событий.
+type Counter struct {
+	Total      int            `json:"total"`       // Total общее количество переходов.
+	Visitors   map[string]int `json:"visitors"`    // Visitors количество переходов по IP-адресу клиента.
+	Days       map[string]int `json:"days"`        // Days количество переходов по дням в формате DateLayout.
+	Referrers  map[string]int `json:"referrers"`   // Referrers количество переходов по источнику.
+	UserAgents map[string]int `json:"user_agents"` // UserAgents количество переходов по клиенту.
+}
+
+// NewCounter создает пустой счетчик переходов.
+func NewCounter() *Counter {
+	return &Counter{
+		Visitors:   make(map[string]int),
+		Days:       make(map[string]int),
+		Referrers:  make(map[string]int),
+		UserAgents: make(map[string]int),
+	}
+}
+
+// Add учитывает событие перехода.
+func (counter *Counter) Add(click models.Click) {
+	counter.Total++
+	counter.Visitors[click.ClientIP]++
+	counter.Days[click.Timestamp.UTC().Format(DateLayout)]++
+	if click.Referrer != "" {
+		counter.Referrers[click.Referrer]++
+	}
+	if click.UserAgent != "" {
+		counter.UserAgents[click.UserAgent]++
+	}
+}
+
+// Merge добавляет к счетчику статистику другого счетчика.
+func (counter *Counter) Merge(other Counter) {
+	counter.Total += other.Total
+	mergeCounts(counter.Visitors, other.Visitors)
+	mergeCounts(counter.Days, other.Days)
+	mergeCounts(counter.Referrers, other.Referrers)
+	mergeCounts(counter.UserAgents, other.UserAgents)
+}
+
+// Stats возвращает статистику переходов по сокращенному URL.
+// В списки самых частых источников и клиентов попадает не более top значений.
+func (counter *Counter) Stats(shortURL string, top int) models.LinkStats {
+	stats := models.LinkStats{
+		ShortURL:       shortURL,
+		TotalClicks:    counter.Total,
+		UniqueVisitors: len(counter.Visitors),
+		ClicksPerDay:   make([]models.ClicksPerDay, 0, len(counter.Days)),
+	}
+	for date, count := range counter.Days {
+		stats.ClicksPerDay = append(stats.ClicksPerDay, models.ClicksPerDay{Date: date, Clicks: count})
+	}
+	sort.Slice(stats.ClicksPerDay, func(i, j int) bool {
+		return stats.ClicksPerDay[i].Date < stats.ClicksPerDay[j].Date
+	})
+	stats.TopReferrers = topItems(counter.Referrers, top)
+	stats.TopUserAgents = topItems(counter.UserAgents, top)
+	return stats
+}
+
+func mergeCounts(counts, other map[string]int) {
+	for value, count := range other {
+		counts[value] += count
+	}
+}
+
+func topItems(counts map[string]int, top int) []models.CountItem {
+	items := make([]models.CountItem, 0, len(counts))
+	for value, count := range counts {
+		items = append(items, models.CountItem{Value: value, Count: count})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Count != items[j].Count {
+			return items[i].Count > items[j].Count
+		}
+		return items[i].Value < items[j].Value
+	})
+	if len(items) > top {
+		items = items[:top]
+	}
+	return items
+}
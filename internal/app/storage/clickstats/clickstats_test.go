@@ -0,0 +1,56 @@
+package clickstats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregate(t *testing.T) {
+	day1 := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	day2 := day1.Add(24 * time.Hour)
+	clicks := []models.Click{
+		{ShortURL: "abc", Timestamp: day2, Referrer: "https://ya.ru", UserAgent: "curl", ClientIP: "10.0.0.1"},
+		{ShortURL: "abc", Timestamp: day1, Referrer: "https://ya.ru", UserAgent: "wget", ClientIP: "10.0.0.2"},
+		{ShortURL: "abc", Timestamp: day1, Referrer: "https://google.com", UserAgent: "curl", ClientIP: "10.0.0.1"},
+		{ShortURL: "abc", Timestamp: day1, UserAgent: "firefox", ClientIP: "10.0.0.3"},
+		{ShortURL: "def", Timestamp: day1, Referrer: "https://ya.ru", UserAgent: "curl", ClientIP: "10.0.0.4"},
+	}
+
+	stats := Aggregate("abc", clicks, 2)
+
+	assert.Equal(t, "abc", stats.ShortURL)
+	assert.Equal(t, 4, stats.TotalClicks)
+	assert.Equal(t, 3, stats.UniqueVisitors)
+	assert.Equal(t, []models.ClicksPerDay{
+		{Date: "2024-05-01", Clicks: 3},
+		{Date: "2024-05-02", Clicks: 1},
+	}, stats.ClicksPerDay)
+	assert.Equal(t, []models.CountItem{
+		{Value: "https://ya.ru", Count: 2},
+		{Value: "https://google.com", Count: 1},
+	}, stats.TopReferrers)
+	assert.Equal(t, []models.CountItem{
+		{Value: "curl", Count: 2},
+		{Value: "firefox", Count: 1},
+	}, stats.TopUserAgents)
+}
+
+func TestCounterMerge(t *testing.T) {
+	day := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	clicks := []models.Click{
+		{ShortURL: "abc", Timestamp: day, Referrer: "https://ya.ru", UserAgent: "curl", ClientIP: "10.0.0.1"},
+		{ShortURL: "abc", Timestamp: day.Add(24 * time.Hour), UserAgent: "wget", ClientIP: "10.0.0.2"},
+		{ShortURL: "abc", Timestamp: day, Referrer: "https://ya.ru", ClientIP: "10.0.0.1"},
+	}
+	rolledUp := NewCounter()
+	rolledUp.Add(clicks[0])
+	rolledUp.Add(clicks[1])
+	counter := NewCounter()
+	counter.Merge(*rolledUp)
+	counter.Add(clicks[2])
+
+	assert.Equal(t, Aggregate("abc", clicks, 10), counter.Stats("abc", 10))
+}
@@ -4,228 +4,931 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
-	"os"
+	"slices"
+	"sort"
 	"sync"
-	"sync/atomic"
+	"time"
 
 	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/config"
 	customerrors "github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/errors"
 	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/logger"
 	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/models"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/storage/clickstats"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/storage/urlquery"
 )
 
 // StorageFile представляет хранилище URL-ов в файле.
+//
+// Файл хранилища является журналом JSON-записей, который только дописывается.
+// Журнал читается один раз при создании хранилища, по нему строится индекс в памяти,
+// и все операции чтения обслуживаются индексом без обращения к файлу.
+// Статистика переходов также хранится в памяти в виде счетчиков по каждому URL.
+// Удаление и восстановление URL дописывают в журнал записи-пометки вместо перезаписи файла.
+//
+// Каждая запись журнала содержит контрольную сумму, частота fsync задается политикой синхронизации.
+// При запуске оборванная последняя запись отрезается, а периодическое сжатие атомарно перезаписывает журналы
+// актуальным состоянием индекса, отбрасывая замененные записи.
 type StorageFile struct {
-	filePath  string
-	uuidSeq   int
-	userIDSeq atomic.Int64
+	filePath     string
+	urlsLog      *journal
+	clicksLog    *journal
+	deletionsLog *journal
+	usersLog     *journal
+	sequenceLog  *journal
+	urls         map[string]models.URL
+	urlsOfUsers  map[int][]string
+	deletions    map[string]models.DeletionJob
+	users        map[int]models.User
+	clicks       map[string]*clickstats.Counter
+	uuidSeq      int
+	nextUserID   int
+	// sequence последний выданный номер последовательности, reserved — граница номеров, зарезервированных в журнале.
+	sequence int64
+	reserved int64
 	sync.RWMutex
-	config config.Config
+	config    config.Config
+	stop      chan struct{}
+	stopped   chan struct{}
+	closeOnce sync.Once
 }
 
-// URLInFile URL в файле.
+// clicksFileSuffix суффикс файла, в котором хранятся события перехода по сокращенным URL.
+const clicksFileSuffix = ".clicks"
+
+// deletionsFileSuffix суффикс файла, в котором хранятся задания на удаление URL.
+const deletionsFileSuffix = ".deletions"
+
+// usersFileSuffix суффикс файла, в котором хранятся зарегистрированные пользователи.
+const usersFileSuffix = ".users"
+
+// sequenceFileSuffix суффикс файла, в котором хранится граница зарезервированных номеров последовательности сокращенных URL.
+const sequenceFileSuffix = ".seq"
+
+// sequenceBlock количество номеров последовательности, которые резервируются одной записью журнала.
+const sequenceBlock = 100
+
+// Операции записей журнала URL.
+const (
+	opSave    = ""        // opSave запись URL целиком, в том числе запись в формате без поля op.
+	opDelete  = "delete"  // opDelete пометка удаления URL.
+	opRestore = "restore" // opRestore снятие пометки удаления URL.
+	opRemove  = "remove"  // opRemove удаление URL из хранилища.
+	opDisable = "disable" // opDisable отключение URL администратором.
+	opEnable  = "enable"  // opEnable включение URL администратором.
+	opOwner   = "owner"   // opOwner передача URL пользователю CreatedBy.
+)
+
+// URLInFile запись журнала URL в файле.
+// Для записей-пометок заполняются только Op, ShortURL и, для удаления, DeletedAt, а для передачи URL — CreatedBy.
 type URLInFile struct {
-	UUID        int    `json:"uuid"`
-	ShortURL    string `json:"short_url"`
-	OriginalURL string `json:"original_url"`
-	CreatedBy   int    `json:"created_by"`
-	IsDeleted   bool   `json:"is_deleted"`
+	Op          string     `json:"op,omitempty"`
+	UUID        int        `json:"uuid,omitempty"`
+	ShortURL    string     `json:"short_url"`
+	OriginalURL string     `json:"original_url,omitempty"`
+	CreatedBy   int        `json:"created_by,omitempty"`
+	IsDeleted   bool       `json:"is_deleted,omitempty"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+	CreatedTS   *time.Time `json:"created_ts,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	IsDisabled  bool       `json:"is_disabled,omitempty"`
+}
+
+func newURLInFile(uuid int, url models.URL) URLInFile {
+	urlInFile := URLInFile{
+		UUID:        uuid,
+		ShortURL:    url.ShortURL,
+		OriginalURL: url.OriginalURL,
+		CreatedBy:   url.CreatedBy,
+		IsDeleted:   url.IsDeleted,
+		IsDisabled:  url.IsDisabled,
+	}
+	if !url.CreatedTS.IsZero() {
+		createdTS := url.CreatedTS
+		urlInFile.CreatedTS = &createdTS
+	}
+	if !url.DeletedAt.IsZero() {
+		deletedAt := url.DeletedAt
+		urlInFile.DeletedAt = &deletedAt
+	}
+	if !url.ExpiresAt.IsZero() {
+		expiresAt := url.ExpiresAt
+		urlInFile.ExpiresAt = &expiresAt
+	}
+	return urlInFile
+}
+
+func (urlInFile URLInFile) toURL() models.URL {
+	url := models.URL{
+		ID:          urlInFile.UUID,
+		ShortURL:    urlInFile.ShortURL,
+		OriginalURL: urlInFile.OriginalURL,
+		CreatedBy:   urlInFile.CreatedBy,
+		IsDeleted:   urlInFile.IsDeleted,
+		IsDisabled:  urlInFile.IsDisabled,
+	}
+	if urlInFile.CreatedTS != nil {
+		url.CreatedTS = *urlInFile.CreatedTS
+	}
+	if urlInFile.DeletedAt != nil {
+		url.DeletedAt = *urlInFile.DeletedAt
+	}
+	if urlInFile.ExpiresAt != nil {
+		url.ExpiresAt = *urlInFile.ExpiresAt
+	}
+	return url
 }
 
-// NewFileStorage создает новый экземпляр хранилища URL-ов в файле.
+// NewFileStorage создает новый экземпляр хранилища URL-ов в файле, строит индекс по журналам
+// и запускает фоновую синхронизацию и сжатие журналов.
 func NewFileStorage(config config.Config) (*StorageFile, error) {
+	policy := config.FileSyncPolicy
+	switch policy {
+	case "":
+		policy = SyncAlways
+	case SyncAlways, SyncInterval, SyncNever:
+	default:
+		return nil, errors.New("unknown file sync policy: " + policy)
+	}
 	storage := &StorageFile{
-		filePath: config.FileStoragePath,
-		config:   config,
+		filePath:    config.FileStoragePath,
+		urls:        make(map[string]models.URL),
+		urlsOfUsers: make(map[int][]string),
+		deletions:   make(map[string]models.DeletionJob),
+		users:       make(map[int]models.User),
+		clicks:      make(map[string]*clickstats.Counter),
+		uuidSeq:     1,
+		nextUserID:  1,
+		config:      config,
+		stop:        make(chan struct{}),
+		stopped:     make(chan struct{}),
+	}
+	var err error
+	storage.urlsLog, err = loadJournal(config.FileStoragePath, policy, storage.applyURLRecord)
+	if err != nil {
+		return nil, err
 	}
-	storage.setSeqFromFile()
+	storage.clicksLog, err = loadJournal(config.FileStoragePath+clicksFileSuffix, policy, storage.applyClickRecord)
+	if err != nil {
+		storage.urlsLog.close()
+		return nil, err
+	}
+	storage.deletionsLog, err = loadJournal(config.FileStoragePath+deletionsFileSuffix, policy, storage.applyDeletionJobRecord)
+	if err != nil {
+		storage.urlsLog.close()
+		storage.clicksLog.close()
+		return nil, err
+	}
+	storage.usersLog, err = loadJournal(config.FileStoragePath+usersFileSuffix, policy, storage.applyUserRecord)
+	if err != nil {
+		storage.urlsLog.close()
+		storage.clicksLog.close()
+		storage.deletionsLog.close()
+		return nil, err
+	}
+	storage.sequenceLog, err = loadJournal(config.FileStoragePath+sequenceFileSuffix, policy, storage.applySequenceRecord)
+	if err != nil {
+		storage.urlsLog.close()
+		storage.clicksLog.close()
+		storage.deletionsLog.close()
+		storage.usersLog.close()
+		return nil, err
+	}
+	storage.sequence = storage.reserved
+	go storage.maintain(policy, config.FileSyncInterval.Duration, config.FileCompactionInterval.Duration)
 	return storage, nil
 }
 
-func (storage *StorageFile) setSeqFromFile() {
-	uuidSeq := 1
-	userIDSeq := 1
-	urlsFromFile := storage.loadFromFile()
-	for _, el := range urlsFromFile {
-		if uuidSeq <= el.UUID {
-			uuidSeq = el.UUID + 1
-		}
-		if userIDSeq <= el.CreatedBy {
-			userIDSeq = el.CreatedBy + 1
+// loadJournal читает журнал path, применяя его записи, и открывает его для дописывания.
+func loadJournal(path string, policy string, apply func(data []byte) error) (*journal, error) {
+	validSize, err := readJournal(path, apply)
+	if err != nil {
+		return nil, err
+	}
+	return openJournal(path, policy, validSize)
+}
+
+func (storage *StorageFile) applyURLRecord(data []byte) error {
+	var urlInFile URLInFile
+	if err := json.Unmarshal(data, &urlInFile); err != nil {
+		return err
+	}
+	storage.apply(urlInFile)
+	return nil
+}
+
+func (storage *StorageFile) applyClickRecord(data []byte) error {
+	var clickInFile ClickInFile
+	if err := json.Unmarshal(data, &clickInFile); err != nil {
+		return err
+	}
+	counter := storage.clickCounter(clickInFile.ShortURL)
+	if clickInFile.Stats != nil {
+		counter.Merge(*clickInFile.Stats)
+	} else {
+		counter.Add(clickInFile.Click)
+	}
+	return nil
+}
+
+func (storage *StorageFile) applyDeletionJobRecord(data []byte) error {
+	var jobInFile DeletionJobInFile
+	if err := json.Unmarshal(data, &jobInFile); err != nil {
+		return err
+	}
+	if jobInFile.Op == opRemove {
+		delete(storage.deletions, jobInFile.ID)
+		return nil
+	}
+	storage.deletions[jobInFile.ID] = jobInFile.toDeletionJob()
+	return nil
+}
+
+func (storage *StorageFile) applyUserRecord(data []byte) error {
+	var userInFile UserInFile
+	if err := json.Unmarshal(data, &userInFile); err != nil {
+		return err
+	}
+	storage.registerUser(userInFile.ID, userInFile.CreatedAt)
+	return nil
+}
+
+func (storage *StorageFile) applySequenceRecord(data []byte) error {
+	var sequenceInFile SequenceInFile
+	if err := json.Unmarshal(data, &sequenceInFile); err != nil {
+		return err
+	}
+	storage.reserved = max(storage.reserved, sequenceInFile.Reserved)
+	return nil
+}
+
+// registerUser добавляет пользователя в индекс, если его там еще нет, и сдвигает счетчик идентификаторов за него.
+// Пользователи, созданные до появления журнала пользователей, регистрируются по записям их URL
+// и попадают в журнал пользователей при следующем сжатии.
+func (storage *StorageFile) registerUser(userID int, createdAt time.Time) {
+	if _, ok := storage.users[userID]; ok {
+		return
+	}
+	storage.users[userID] = models.User{ID: userID, CreatedAt: createdAt}
+	storage.nextUserID = max(storage.nextUserID, userID+1)
+}
+
+// maintain периодически синхронизирует журналы с диском для политики interval и сжимает их, пока хранилище не закрыто.
+func (storage *StorageFile) maintain(policy string, syncInterval, compactionInterval time.Duration) {
+	defer close(storage.stopped)
+	var syncTick, compactionTick <-chan time.Time
+	if policy == SyncInterval && syncInterval > 0 {
+		ticker := time.NewTicker(syncInterval)
+		defer ticker.Stop()
+		syncTick = ticker.C
+	}
+	if compactionInterval > 0 {
+		ticker := time.NewTicker(compactionInterval)
+		defer ticker.Stop()
+		compactionTick = ticker.C
+	}
+	for {
+		select {
+		case <-storage.stop:
+			return
+		case <-syncTick:
+			if err := storage.Sync(); err != nil {
+				logger.Logger.Error("file storage sync error", "error", err)
+			}
+		case <-compactionTick:
+			if err := storage.Compact(); err != nil {
+				logger.Logger.Error("file storage compaction error", "error", err)
+			}
 		}
 	}
-	storage.uuidSeq = uuidSeq
-	storage.userIDSeq.Store(int64(userIDSeq))
 }
 
-func (storage *StorageFile) loadFromFile() []URLInFile {
-	array := make([]URLInFile, 0)
-	file, err := os.OpenFile(storage.filePath, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
-	if err != nil {
-		return array
+// Sync сбрасывает на диск записи журналов, которые еще не синхронизированы.
+func (storage *StorageFile) Sync() error {
+	storage.Lock()
+	defer storage.Unlock()
+	return errors.Join(storage.urlsLog.sync(), storage.clicksLog.sync(), storage.deletionsLog.sync(), storage.usersLog.sync(), storage.sequenceLog.sync())
+}
+
+// Compact атомарно перезаписывает журналы URL, заданий на удаление, пользователей и последовательности актуальным состоянием индекса.
+// Записи-пометки и замененные записи при этом отбрасываются, а события перехода сворачиваются в счетчики по каждому URL.
+func (storage *StorageFile) Compact() error {
+	storage.Lock()
+	defer storage.Unlock()
+	urls := make([]models.URL, 0, len(storage.urls))
+	for _, url := range storage.urls {
+		urls = append(urls, url)
 	}
-	defer file.Close()
-	decoder := json.NewDecoder(file)
-	urlInFile := URLInFile{}
-	err = decoder.Decode(&urlInFile)
-	if err != nil {
-		logger.Logger.Warn(err.Error())
+	sort.Slice(urls, func(i, j int) bool {
+		return urls[i].ID < urls[j].ID
+	})
+	urlRecords := make([]any, len(urls))
+	for i, url := range urls {
+		urlRecords[i] = newURLInFile(url.ID, url)
+	}
+	if err := storage.urlsLog.rewrite(urlRecords); err != nil {
+		return err
+	}
+	jobs := make([]models.DeletionJob, 0, len(storage.deletions))
+	for _, job := range storage.deletions {
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].CreatedAt.Before(jobs[j].CreatedAt)
+	})
+	jobRecords := make([]any, len(jobs))
+	for i, job := range jobs {
+		jobRecords[i] = newDeletionJobInFile(job)
+	}
+	if err := storage.deletionsLog.rewrite(jobRecords); err != nil {
+		return err
+	}
+	users := make([]models.User, 0, len(storage.users))
+	for _, user := range storage.users {
+		users = append(users, user)
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].ID < users[j].ID
+	})
+	userRecords := make([]any, len(users))
+	for i, user := range users {
+		userRecords[i] = UserInFile(user)
+	}
+	if err := storage.usersLog.rewrite(userRecords); err != nil {
+		return err
+	}
+	shortURLs := make([]string, 0, len(storage.clicks))
+	for shortURL := range storage.clicks {
+		shortURLs = append(shortURLs, shortURL)
+	}
+	sort.Strings(shortURLs)
+	clickRecords := make([]any, len(shortURLs))
+	for i, shortURL := range shortURLs {
+		clickRecords[i] = ClickStatsInFile{ShortURL: shortURL, Stats: storage.clicks[shortURL]}
+	}
+	if err := storage.clicksLog.rewrite(clickRecords); err != nil {
+		return err
 	}
-	for err == nil {
-		array = append(array, urlInFile)
-		err = decoder.Decode(&urlInFile)
-		if err != nil {
-			logger.Logger.Warn(err.Error())
+	return storage.sequenceLog.rewrite([]any{SequenceInFile{Reserved: storage.reserved}})
+}
+
+// Close останавливает фоновые задачи хранилища, синхронизирует и закрывает журналы.
+func (storage *StorageFile) Close() error {
+	var err error
+	storage.closeOnce.Do(func() {
+		close(storage.stop)
+		<-storage.stopped
+		storage.Lock()
+		defer storage.Unlock()
+		err = errors.Join(storage.urlsLog.close(), storage.clicksLog.close(), storage.deletionsLog.close(), storage.usersLog.close(), storage.sequenceLog.close())
+	})
+	return err
+}
+
+// apply применяет запись журнала к индексу.
+func (storage *StorageFile) apply(urlInFile URLInFile) {
+	switch urlInFile.Op {
+	case opSave:
+		url := urlInFile.toURL()
+		if _, ok := storage.urls[url.ShortURL]; !ok && url.CreatedBy != 0 {
+			storage.urlsOfUsers[url.CreatedBy] = append(storage.urlsOfUsers[url.CreatedBy], url.ShortURL)
+		}
+		storage.urls[url.ShortURL] = url
+		if storage.uuidSeq <= url.ID {
+			storage.uuidSeq = url.ID + 1
+		}
+		if url.CreatedBy != 0 {
+			storage.registerUser(url.CreatedBy, url.CreatedTS)
+		}
+	case opDelete:
+		if url, ok := storage.urls[urlInFile.ShortURL]; ok {
+			url.IsDeleted = true
+			url.DeletedAt = time.Time{}
+			if urlInFile.DeletedAt != nil {
+				url.DeletedAt = *urlInFile.DeletedAt
+			}
+			storage.urls[url.ShortURL] = url
+		}
+	case opRestore:
+		if url, ok := storage.urls[urlInFile.ShortURL]; ok {
+			url.IsDeleted = false
+			url.DeletedAt = time.Time{}
+			storage.urls[url.ShortURL] = url
 		}
+	case opRemove:
+		url, ok := storage.urls[urlInFile.ShortURL]
+		if !ok {
+			return
+		}
+		delete(storage.urls, url.ShortURL)
+		storage.removeURLOfUser(url)
+	case opDisable, opEnable:
+		if url, ok := storage.urls[urlInFile.ShortURL]; ok {
+			url.IsDisabled = urlInFile.Op == opDisable
+			storage.urls[url.ShortURL] = url
+		}
+	case opOwner:
+		url, ok := storage.urls[urlInFile.ShortURL]
+		if !ok || url.CreatedBy == urlInFile.CreatedBy {
+			return
+		}
+		storage.removeURLOfUser(url)
+		url.CreatedBy = urlInFile.CreatedBy
+		if url.CreatedBy != 0 {
+			storage.urlsOfUsers[url.CreatedBy] = append(storage.urlsOfUsers[url.CreatedBy], url.ShortURL)
+		}
+		storage.urls[url.ShortURL] = url
 	}
-	return array
+}
+
+// removeURLOfUser удаляет URL из списка URL пользователя, создавшего его.
+func (storage *StorageFile) removeURLOfUser(url models.URL) {
+	shortURLs := slices.DeleteFunc(storage.urlsOfUsers[url.CreatedBy], func(shortURL string) bool {
+		return shortURL == url.ShortURL
+	})
+	if len(shortURLs) == 0 {
+		delete(storage.urlsOfUsers, url.CreatedBy)
+		return
+	}
+	storage.urlsOfUsers[url.CreatedBy] = shortURLs
+}
+
+// appendRecords дописывает записи в журнал и применяет их к индексу.
+// Индекс меняется только после успешной записи, поэтому он не расходится с файлом.
+func (storage *StorageFile) appendRecords(records []URLInFile) error {
+	if len(records) == 0 {
+		return nil
+	}
+	recordsToAppend := make([]any, len(records))
+	for i, record := range records {
+		recordsToAppend[i] = record
+	}
+	if err := storage.urlsLog.append(recordsToAppend...); err != nil {
+		return customerrors.NewCustomErrorInternal(err)
+	}
+	for _, record := range records {
+		storage.apply(record)
+	}
+	return nil
 }
 
 // Save сохраняет URL в хранилище.
-func (storage *StorageFile) Save(ctx context.Context, url models.URL) error {
+func (storage *StorageFile) Save(_ context.Context, url models.URL) error {
 	storage.Lock()
 	defer storage.Unlock()
-	file, err := os.OpenFile(storage.filePath, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
-	if err != nil {
-		return customerrors.NewCustomErrorInternal(err)
+	if err := storage.checkURL(url, nil, time.Now()); err != nil {
+		return err
 	}
-	defer file.Close()
-	encoder := json.NewEncoder(file)
-	urlInFile := &URLInFile{
-		UUID:        storage.uuidSeq,
-		ShortURL:    url.ShortURL,
-		OriginalURL: url.OriginalURL,
-		CreatedBy:   url.CreatedBy,
+	return storage.appendRecords([]URLInFile{newURLInFile(storage.uuidSeq, url)})
+}
+
+// checkURL проверяет, можно ли сохранить URL, если перед ним в том же пакете сохраняются URL accepted.
+func (storage *StorageFile) checkURL(url models.URL, accepted []models.URL, now time.Time) error {
+	if _, ok := storage.urls[url.ShortURL]; ok {
+		return customerrors.NewCustomErrorConflict(customerrors.ErrShortURLAlreadyExists)
 	}
-	err = encoder.Encode(urlInFile)
-	storage.uuidSeq++
-	if err != nil {
-		return customerrors.NewCustomErrorInternal(err)
+	if duplicate, ok := storage.findDuplicate(url, now); ok {
+		return customerrors.NewCustomErrorOriginalURLConflict(duplicate)
+	}
+	for _, previous := range accepted {
+		if previous.ShortURL == url.ShortURL {
+			return customerrors.NewCustomErrorConflict(customerrors.ErrShortURLAlreadyExists)
+		}
+		if url.Dedupe && url.IsDuplicateOf(previous, now) {
+			return customerrors.NewCustomErrorOriginalURLConflict(previous.ShortURL)
+		}
 	}
 	return nil
 }
 
+// checkBatch проверяет каждый URL из списка с учетом URL, которые сохраняются перед ним.
+func (storage *StorageFile) checkBatch(urls []models.URL) []error {
+	now := time.Now()
+	errs := make([]error, len(urls))
+	accepted := make([]models.URL, 0, len(urls))
+	for i, url := range urls {
+		errs[i] = storage.checkURL(url, accepted, now)
+		if errs[i] == nil {
+			accepted = append(accepted, url)
+		}
+	}
+	return errs
+}
+
+// findDuplicate возвращает сокращенный URL, который пользователь уже создал для исходного URL, если url требует дедупликации.
+func (storage *StorageFile) findDuplicate(url models.URL, now time.Time) (string, bool) {
+	if !url.Dedupe {
+		return "", false
+	}
+	for _, shortURL := range storage.urlsOfUsers[url.CreatedBy] {
+		if url.IsDuplicateOf(storage.urls[shortURL], now) {
+			return shortURL, true
+		}
+	}
+	return "", false
+}
+
 // FindByShortURL находит оригинальный URL по сокращенному URL.
 func (storage *StorageFile) FindByShortURL(_ context.Context, shortURL string) (*models.URL, error) {
+	storage.RLock()
+	defer storage.RUnlock()
+	url, ok := storage.urls[shortURL]
+	if !ok {
+		return nil, customerrors.NewCustomErrorBadRequest(customerrors.ErrURLNotFound)
+	}
+	return &url, nil
+}
+
+// Ping проверяет доступность хранилища: все журналы должны быть открыты для записи.
+// Журналы создаются при создании хранилища, поэтому новое пустое хранилище тоже доступно.
+func (storage *StorageFile) Ping(_ context.Context) bool {
+	storage.RLock()
+	defer storage.RUnlock()
+	for _, log := range []*journal{storage.urlsLog, storage.clicksLog, storage.deletionsLog, storage.usersLog, storage.sequenceLog} {
+		if err := log.ping(); err != nil {
+			logger.Logger.Warn("file storage is unavailable", "path", log.path, "error", err)
+			return false
+		}
+	}
+	return true
+}
+
+// SaveBatchPartial сохраняет URL из списка независимо друг от друга и возвращает ошибки сохранения по индексам URL.
+// Сохраняемые URL дописываются в журнал одной записью, поэтому при ошибке записи не сохраняется ни один из них.
+func (storage *StorageFile) SaveBatchPartial(_ context.Context, urls []models.URL) ([]error, error) {
 	storage.Lock()
 	defer storage.Unlock()
-	urlsInFile := storage.loadFromFile()
-	for _, el := range urlsInFile {
-		if el.ShortURL == shortURL {
-			url := &models.URL{
-				ID:          el.UUID,
-				ShortURL:    el.ShortURL,
-				OriginalURL: el.OriginalURL,
-				CreatedBy:   el.CreatedBy,
-				IsDeleted:   el.IsDeleted,
-			}
-			return url, nil
+	errs := storage.checkBatch(urls)
+	records := make([]URLInFile, 0, len(urls))
+	for i, url := range urls {
+		if errs[i] == nil {
+			records = append(records, newURLInFile(storage.uuidSeq+len(records), url))
 		}
 	}
-	return nil, customerrors.NewCustomErrorBadRequest(errors.New("original url isn't found"))
+	if err := storage.appendRecords(records); err != nil {
+		return nil, err
+	}
+	return errs, nil
 }
 
-// Ping проверяет доступность хранилища.
-func (storage *StorageFile) Ping(_ context.Context) bool {
+// UserInFile запись журнала пользователей в файле.
+type UserInFile struct {
+	ID        int       `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CreateUser регистрирует нового пользователя с новым идентификатором и дописывает его в журнал пользователей.
+func (storage *StorageFile) CreateUser(context.Context) (models.User, error) {
+	storage.Lock()
+	defer storage.Unlock()
+	user := models.User{ID: storage.nextUserID, CreatedAt: time.Now()}
+	if err := storage.usersLog.append(UserInFile(user)); err != nil {
+		return models.User{}, customerrors.NewCustomErrorInternal(err)
+	}
+	storage.registerUser(user.ID, user.CreatedAt)
+	return user, nil
+}
+
+// SequenceInFile запись журнала последовательности в файле: граница зарезервированных номеров.
+type SequenceInFile struct {
+	Reserved int64 `json:"reserved"`
+}
+
+// NextSequence возвращает следующий номер последовательности сокращенных URL.
+// Номера резервируются в журнале блоками, поэтому после перезапуска неиспользованные номера блока пропускаются.
+func (storage *StorageFile) NextSequence(context.Context) (int64, error) {
+	storage.Lock()
+	defer storage.Unlock()
+	if storage.sequence >= storage.reserved {
+		reserved := storage.reserved + sequenceBlock
+		if err := storage.sequenceLog.append(SequenceInFile{Reserved: reserved}); err != nil {
+			return 0, customerrors.NewCustomErrorInternal(err)
+		}
+		storage.reserved = reserved
+	}
+	storage.sequence++
+	return storage.sequence, nil
+}
+
+// FindByUser находит URL, созданные конкретным пользователем, с учетом фильтров, сортировки и курсора запроса.
+func (storage *StorageFile) FindByUser(_ context.Context, userID int, query models.URLQuery) ([]models.URL, error) {
 	storage.RLock()
-	file, err := os.OpenFile(storage.filePath, os.O_RDWR, 0666)
-	storage.RUnlock()
-	if err != nil {
-		return false
+	defer storage.RUnlock()
+	shortURLs := storage.urlsOfUsers[userID]
+	urls := make([]models.URL, 0, len(shortURLs))
+	for _, shortURL := range shortURLs {
+		urls = append(urls, storage.urls[shortURL])
 	}
-	defer file.Close()
-	return true
+	return urlquery.Apply(urls, query), nil
 }
 
-// SaveBatch сохраняет список URL в хранилище.
-func (storage *StorageFile) SaveBatch(ctx context.Context, urls []models.URL) error {
+// DeleteUrls помечает удаленными URL из списка, созданные указанными пользователями.
+// Для каждого URL, который еще не удален, в журнал дописывается запись-пометка удаления.
+func (storage *StorageFile) DeleteUrls(_ context.Context, urls []models.URLToDelete) ([]string, error) {
+	storage.Lock()
+	defer storage.Unlock()
+	now := time.Now()
+	deleted := make([]string, 0, len(urls))
+	records := make([]URLInFile, 0, len(urls))
 	for _, url := range urls {
-		err := storage.Save(ctx, url)
-		if err != nil {
-			return customerrors.NewCustomErrorInternal(err)
+		el, ok := storage.urls[url.ShortURL]
+		if !ok || el.CreatedBy != url.UserID {
+			continue
+		}
+		if !el.IsDeleted {
+			records = append(records, URLInFile{Op: opDelete, ShortURL: url.ShortURL, DeletedAt: &now})
 		}
+		deleted = append(deleted, url.ShortURL)
 	}
-	return nil
+	if err := storage.appendRecords(records); err != nil {
+		return nil, err
+	}
+	return deleted, nil
 }
 
-// GetUserID возвращает идентификатор пользователя из контекста.
-func (storage *StorageFile) GetUserID(context.Context) int {
-	userID := storage.userIDSeq.Load()
-	storage.userIDSeq.Add(1)
-	return int(userID)
+// RestoreUrls снимает пометку удаления с URL из списка, созданных пользователем и удаленных не раньше deletedAfter.
+func (storage *StorageFile) RestoreUrls(_ context.Context, userID int, shortURLs []string, deletedAfter time.Time) ([]string, error) {
+	storage.Lock()
+	defer storage.Unlock()
+	restored := make([]string, 0, len(shortURLs))
+	records := make([]URLInFile, 0, len(shortURLs))
+	for _, shortURL := range shortURLs {
+		el, ok := storage.urls[shortURL]
+		if ok && el.CreatedBy == userID && el.IsDeleted && !el.DeletedAt.IsZero() && !el.DeletedAt.Before(deletedAfter) {
+			records = append(records, URLInFile{Op: opRestore, ShortURL: shortURL})
+			restored = append(restored, shortURL)
+		}
+	}
+	if err := storage.appendRecords(records); err != nil {
+		return nil, err
+	}
+	return restored, nil
+}
+
+// SetURLDisabled отключает или включает URL, дописывая в журнал запись-пометку.
+func (storage *StorageFile) SetURLDisabled(_ context.Context, shortURL string, disabled bool) error {
+	storage.Lock()
+	defer storage.Unlock()
+	if _, ok := storage.urls[shortURL]; !ok {
+		return customerrors.NewCustomErrorBadRequest(customerrors.ErrURLNotFound)
+	}
+	op := opEnable
+	if disabled {
+		op = opDisable
+	}
+	return storage.appendRecords([]URLInFile{{Op: op, ShortURL: shortURL}})
+}
+
+// SetURLOwner передает URL зарегистрированному пользователю userID, дописывая в журнал запись о передаче.
+func (storage *StorageFile) SetURLOwner(_ context.Context, shortURL string, userID int) error {
+	storage.Lock()
+	defer storage.Unlock()
+	url, ok := storage.urls[shortURL]
+	if !ok {
+		return customerrors.NewCustomErrorBadRequest(customerrors.ErrURLNotFound)
+	}
+	if _, ok := storage.users[userID]; !ok {
+		return customerrors.NewCustomErrorBadRequest(customerrors.ErrUserNotFound)
+	}
+	if url.CreatedBy == userID {
+		return nil
+	}
+	return storage.appendRecords([]URLInFile{{Op: opOwner, ShortURL: shortURL, CreatedBy: userID}})
+}
+
+// DeleteUrlsOfUser помечает удаленными все URL пользователя userID.
+func (storage *StorageFile) DeleteUrlsOfUser(_ context.Context, userID int) ([]string, error) {
+	storage.Lock()
+	defer storage.Unlock()
+	return storage.deleteWhere(storage.urlsOfUsers[userID], func(models.URL) bool { return true })
 }
 
-// FindByUser находит URL, созданные конкретным пользователем.
-func (storage *StorageFile) FindByUser(ctx context.Context, userID int) ([]models.URL, error) {
+// DeleteUrlsOfDomain помечает удаленными все URL, исходный URL которых ведет на домен domain или его поддомен.
+func (storage *StorageFile) DeleteUrlsOfDomain(_ context.Context, domain string) ([]string, error) {
+	storage.Lock()
+	defer storage.Unlock()
+	shortURLs := make([]string, 0)
+	for shortURL := range storage.urls {
+		shortURLs = append(shortURLs, shortURL)
+	}
+	slices.Sort(shortURLs)
+	return storage.deleteWhere(shortURLs, func(url models.URL) bool { return url.HasDomain(domain) })
+}
+
+// deleteWhere помечает удаленными еще не удаленные URL из списка, удовлетворяющие условию match, и возвращает их.
+// Пометки удаления дописываются в журнал одной записью.
+func (storage *StorageFile) deleteWhere(shortURLs []string, match func(models.URL) bool) ([]string, error) {
+	now := time.Now()
+	deleted := make([]string, 0)
+	records := make([]URLInFile, 0)
+	for _, shortURL := range shortURLs {
+		url, ok := storage.urls[shortURL]
+		if !ok || url.IsDeleted || !match(url) {
+			continue
+		}
+		records = append(records, URLInFile{Op: opDelete, ShortURL: shortURL, DeletedAt: &now})
+		deleted = append(deleted, shortURL)
+	}
+	if err := storage.appendRecords(records); err != nil {
+		return nil, err
+	}
+	return deleted, nil
+}
+
+// IsShortURLExists проверяет, существует ли указанный сокращенный URL в хранилище.
+func (storage *StorageFile) IsShortURLExists(_ context.Context, shortURL string) (bool, error) {
 	storage.RLock()
 	defer storage.RUnlock()
-	urlsInFile := storage.loadFromFile()
-	urls := make([]models.URL, 0)
-	for _, el := range urlsInFile {
-		if el.CreatedBy == userID {
-			urls = append(urls, models.URL{
-				ID:          el.UUID,
-				ShortURL:    el.ShortURL,
-				OriginalURL: el.OriginalURL,
-				CreatedBy:   el.CreatedBy,
-			})
+	_, ok := storage.urls[shortURL]
+	return ok, nil
+}
+
+// GetStats возвращает статистику по хранилищу.
+func (storage *StorageFile) GetStats(_ context.Context) (models.Stats, error) {
+	storage.RLock()
+	defer storage.RUnlock()
+	var stats models.Stats
+	for _, url := range storage.urls {
+		if !url.IsDeleted {
+			stats.URLS++
 		}
 	}
-	if len(urls) > 0 {
-		return urls, nil
-	}
-	return nil, customerrors.NewCustomErrorBadRequest(errors.New("original url isn't found"))
+	stats.Users = len(storage.users)
+	return stats, nil
 }
 
-// DeleteUrls удаляет список URL из хранилища.
-func (storage *StorageFile) DeleteUrls(_ context.Context, urls []models.URLToDelete) error {
+// DeleteExpired удаляет из хранилища URL, срок действия которых истек к моменту now.
+func (storage *StorageFile) DeleteExpired(_ context.Context, now time.Time) (int, error) {
 	storage.Lock()
 	defer storage.Unlock()
-	urlsFromFile := storage.loadFromFile()
-	for _, url := range urls {
-		for i, urlFromFile := range urlsFromFile {
-			if url.ShortURL == urlFromFile.ShortURL && urlFromFile.CreatedBy == url.UserID {
-				urlsFromFile[i].IsDeleted = true
-			}
+	records := make([]URLInFile, 0)
+	for shortURL, url := range storage.urls {
+		if url.IsExpired(now) {
+			records = append(records, URLInFile{Op: opRemove, ShortURL: shortURL})
 		}
 	}
-	file, err := os.OpenFile(storage.filePath, os.O_RDWR, 0666)
-	if err != nil {
+	if err := storage.appendRecords(records); err != nil {
+		return 0, err
+	}
+	return len(records), nil
+}
+
+// SaveClicks сохраняет список событий перехода в хранилище.
+// События дописываются в отдельный журнал рядом с файлом хранилища URL-ов.
+func (storage *StorageFile) SaveClicks(_ context.Context, clicks []models.Click) error {
+	if len(clicks) == 0 {
+		return nil
+	}
+	storage.Lock()
+	defer storage.Unlock()
+	records := make([]any, len(clicks))
+	for i, click := range clicks {
+		records[i] = click
+	}
+	if err := storage.clicksLog.append(records...); err != nil {
 		return customerrors.NewCustomErrorInternal(err)
 	}
-	defer file.Close()
-	for _, url := range urlsFromFile {
-		encoder := json.NewEncoder(file)
-		encoder.Encode(url)
+	for _, click := range clicks {
+		storage.clickCounter(click.ShortURL).Add(click)
 	}
 	return nil
 }
 
-// IsShortURLExists проверяет, существует ли указанный сокращенный URL в хранилище.
-func (storage *StorageFile) IsShortURLExists(_ context.Context, shortURL string) (bool, error) {
-	urlsFromFile := storage.loadFromFile()
-	for _, urlFromFile := range urlsFromFile {
-		if urlFromFile.ShortURL == shortURL {
-			return true, nil
+// GetClickStats возвращает статистику переходов по сокращенному URL по счетчикам в памяти.
+func (storage *StorageFile) GetClickStats(_ context.Context, shortURL string, top int) (models.LinkStats, error) {
+	storage.RLock()
+	defer storage.RUnlock()
+	counter, ok := storage.clicks[shortURL]
+	if !ok {
+		counter = clickstats.NewCounter()
+	}
+	return counter.Stats(shortURL, top), nil
+}
+
+// clickCounter возвращает счетчик переходов по сокращенному URL, создавая его при необходимости.
+func (storage *StorageFile) clickCounter(shortURL string) *clickstats.Counter {
+	counter, ok := storage.clicks[shortURL]
+	if !ok {
+		counter = clickstats.NewCounter()
+		storage.clicks[shortURL] = counter
+	}
+	return counter
+}
+
+// ClickInFile запись журнала переходов: событие перехода или, после сжатия журнала,
+// накопленная статистика переходов по сокращенному URL.
+type ClickInFile struct {
+	models.Click
+	Stats *clickstats.Counter `json:"stats,omitempty"`
+}
+
+// ClickStatsInFile накопленная статистика переходов по сокращенному URL, которой при сжатии
+// заменяются события перехода в журнале.
+type ClickStatsInFile struct {
+	ShortURL string              `json:"short_url"`
+	Stats    *clickstats.Counter `json:"stats"`
+}
+
+// DeletionJobInFile задание на удаление в файле.
+// Задания дописываются в журнал при каждом изменении, актуальной считается последняя запись с тем же идентификатором.
+// Запись с операцией opRemove удаляет задание из хранилища.
+type DeletionJobInFile struct {
+	models.DeletionJob
+	Op            string    `json:"op,omitempty"`
+	UserID        int       `json:"user_id"`
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+}
+
+func newDeletionJobInFile(job models.DeletionJob) DeletionJobInFile {
+	return DeletionJobInFile{
+		DeletionJob:   job,
+		UserID:        job.UserID,
+		NextAttemptAt: job.NextAttemptAt,
+	}
+}
+
+func (jobInFile DeletionJobInFile) toDeletionJob() models.DeletionJob {
+	job := jobInFile.DeletionJob
+	job.UserID = jobInFile.UserID
+	job.NextAttemptAt = jobInFile.NextAttemptAt
+	return job
+}
+
+// SaveDeletionJob сохраняет задание на удаление, дописывая его в журнал заданий.
+func (storage *StorageFile) SaveDeletionJob(_ context.Context, job models.DeletionJob) error {
+	storage.Lock()
+	defer storage.Unlock()
+	job.URLs = slices.Clone(job.URLs)
+	if err := storage.deletionsLog.append(newDeletionJobInFile(job)); err != nil {
+		return customerrors.NewCustomErrorInternal(err)
+	}
+	storage.deletions[job.ID] = job
+	return nil
+}
+
+// FindDeletionJob находит задание на удаление по идентификатору.
+func (storage *StorageFile) FindDeletionJob(_ context.Context, id string) (*models.DeletionJob, error) {
+	storage.RLock()
+	defer storage.RUnlock()
+	job, ok := storage.deletions[id]
+	if !ok {
+		return nil, customerrors.NewCustomErrorBadRequest(customerrors.ErrDeletionJobNotFound)
+	}
+	job.URLs = slices.Clone(job.URLs)
+	return &job, nil
+}
+
+// FindPendingDeletionJobs возвращает незавершенные задания на удаление, очередная попытка которых наступила к моменту now.
+func (storage *StorageFile) FindPendingDeletionJobs(_ context.Context, now time.Time, limit int) ([]models.DeletionJob, error) {
+	storage.RLock()
+	defer storage.RUnlock()
+	pending := make([]models.DeletionJob, 0)
+	for _, job := range storage.deletions {
+		if job.Status == models.DeletionJobPending && !job.NextAttemptAt.After(now) {
+			job.URLs = slices.Clone(job.URLs)
+			pending = append(pending, job)
 		}
 	}
-	return false, nil
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].NextAttemptAt.Before(pending[j].NextAttemptAt)
+	})
+	if len(pending) > limit {
+		pending = pending[:limit]
+	}
+	return pending, nil
 }
 
-// GetStats возвращает статистику по хранилищу.
-func (storage *StorageFile) GetStats(ctx context.Context) (models.Stats, error) {
-	urls := make(map[string]struct{})
-	users := make(map[int]struct{})
-	for _, el := range storage.loadFromFile() {
-		if _, ok := users[el.CreatedBy]; !ok {
-			users[el.CreatedBy] = struct{}{}
+// CountPendingDeletionJobs возвращает количество незавершенных заданий на удаление.
+func (storage *StorageFile) CountPendingDeletionJobs(_ context.Context) (int, error) {
+	storage.RLock()
+	defer storage.RUnlock()
+	count := 0
+	for _, job := range storage.deletions {
+		if job.Status == models.DeletionJobPending {
+			count++
 		}
-		if _, ok := urls[el.ShortURL]; !ok && !el.IsDeleted {
-			urls[el.ShortURL] = struct{}{}
+	}
+	return count, nil
+}
+
+// DeleteFinishedDeletionJobs удаляет завершенные задания на удаление, последнее изменение которых было раньше before,
+// дописывая в журнал заданий записи об их удалении.
+func (storage *StorageFile) DeleteFinishedDeletionJobs(_ context.Context, before time.Time) (int, error) {
+	storage.Lock()
+	defer storage.Unlock()
+	records := make([]any, 0)
+	for id, job := range storage.deletions {
+		if job.Status != models.DeletionJobPending && job.UpdatedAt.Before(before) {
+			records = append(records, DeletionJobInFile{DeletionJob: models.DeletionJob{ID: id}, Op: opRemove})
 		}
 	}
-	var stats models.Stats
-	stats.URLS = len(urls)
-	stats.Users = len(users)
-	return stats, nil
+	if len(records) == 0 {
+		return 0, nil
+	}
+	if err := storage.deletionsLog.append(records...); err != nil {
+		return 0, customerrors.NewCustomErrorInternal(err)
+	}
+	for _, record := range records {
+		delete(storage.deletions, record.(DeletionJobInFile).ID)
+	}
+	return len(records), nil
 }
@@ -1,21 +1,27 @@
 package file
 
 import (
+	"bytes"
 	"context"
+	"fmt"
 	"log/slog"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/config"
+	customerrors "github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/errors"
 	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/logger"
 	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/models"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/storage/clickstats"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestSave(t *testing.T) {
 	logger.Init(slog.LevelInfo)
 	config := config.Config{
-		FileStoragePath: "test_data",
+		FileStoragePath: t.TempDir() + "/test_data",
 	}
 
 	storage, err := NewFileStorage(config)
@@ -33,14 +39,13 @@ func TestSave(t *testing.T) {
 	url.ID = foundURL.ID
 	assert.NoError(t, err)
 	assert.Equal(t, url, *foundURL)
-	err = os.Remove(config.FileStoragePath)
-	assert.NoError(t, err)
+	assert.NoError(t, storage.Close())
 }
 
 func TestFindByShortURL(t *testing.T) {
 	logger.Init(slog.LevelInfo)
 	config := config.Config{
-		FileStoragePath: "test_data",
+		FileStoragePath: t.TempDir() + "/test_data",
 	}
 	storage, err := NewFileStorage(config)
 	assert.NoError(t, err)
@@ -60,6 +65,583 @@ func TestFindByShortURL(t *testing.T) {
 
 	_, err = storage.FindByShortURL(context.Background(), "nonexistent")
 	assert.Error(t, err)
-	err = os.Remove(config.FileStoragePath)
+	assert.NoError(t, storage.Close())
+}
+
+func TestDeletionJobs(t *testing.T) {
+	logger.Init(slog.LevelInfo)
+	config := config.Config{
+		FileStoragePath: t.TempDir() + "/test_data",
+	}
+	storage, err := NewFileStorage(config)
+	assert.NoError(t, err)
+	ctx := context.Background()
+	err = storage.Save(ctx, models.URL{ShortURL: "abc", OriginalURL: "https://example.com", CreatedBy: 1})
+	assert.NoError(t, err)
+
+	deleted, err := storage.DeleteUrls(ctx, []models.URLToDelete{{UserID: 1, ShortURL: "abc"}, {UserID: 1, ShortURL: "def"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"abc"}, deleted)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	job := models.DeletionJob{
+		ID:            "job1",
+		UserID:        1,
+		Status:        models.DeletionJobPending,
+		URLs:          []models.DeletionJobURL{{ShortURL: "abc", Status: models.DeletionURLPending}},
+		CreatedAt:     now,
+		UpdatedAt:     now,
+		NextAttemptAt: now,
+	}
+	err = storage.SaveDeletionJob(ctx, job)
+	assert.NoError(t, err)
+	job.Attempts = 1
+	job.NextAttemptAt = now.Add(time.Minute)
+	err = storage.SaveDeletionJob(ctx, job)
+	assert.NoError(t, err)
+
+	// задания переживают перезапуск, актуальной считается последняя запись
+	assert.NoError(t, storage.Close())
+	storage, err = NewFileStorage(config)
+	assert.NoError(t, err)
+	defer storage.Close()
+	found, err := storage.FindDeletionJob(ctx, "job1")
+	assert.NoError(t, err)
+	assert.Equal(t, job, *found)
+	pending, err := storage.FindPendingDeletionJobs(ctx, now, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, pending)
+	pending, err = storage.FindPendingDeletionJobs(ctx, now.Add(time.Minute), 10)
+	assert.NoError(t, err)
+	assert.Len(t, pending, 1)
+	count, err := storage.CountPendingDeletionJobs(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	_, err = storage.FindDeletionJob(ctx, "unknown")
+	assert.ErrorIs(t, err, customerrors.ErrDeletionJobNotFound)
+
+	// завершенные задания удаляются только по истечении срока хранения, в том числе после перезапуска
+	job.Status = models.DeletionJobDone
+	err = storage.SaveDeletionJob(ctx, job)
+	assert.NoError(t, err)
+	count, err = storage.DeleteFinishedDeletionJobs(ctx, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+	count, err = storage.DeleteFinishedDeletionJobs(ctx, now.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.NoError(t, storage.Close())
+	storage, err = NewFileStorage(config)
+	assert.NoError(t, err)
+	defer storage.Close()
+	_, err = storage.FindDeletionJob(ctx, "job1")
+	assert.ErrorIs(t, err, customerrors.ErrDeletionJobNotFound)
+}
+
+func TestRestoreUrls(t *testing.T) {
+	logger.Init(slog.LevelInfo)
+	config := config.Config{
+		FileStoragePath: t.TempDir() + "/test_data",
+	}
+	storage, err := NewFileStorage(config)
+	assert.NoError(t, err)
+	defer storage.Close()
+	ctx := context.Background()
+	for _, url := range []models.URL{
+		{ShortURL: "abc", OriginalURL: "https://example.com", CreatedBy: 1},
+		{ShortURL: "old", OriginalURL: "https://example.org", CreatedBy: 1, IsDeleted: true, DeletedAt: time.Now().Add(-time.Hour)},
+	} {
+		assert.NoError(t, storage.Save(ctx, url))
+	}
+	_, err = storage.DeleteUrls(ctx, []models.URLToDelete{{UserID: 1, ShortURL: "abc"}})
+	assert.NoError(t, err)
+	url, err := storage.FindByShortURL(ctx, "abc")
+	assert.NoError(t, err)
+	assert.True(t, url.IsDeleted)
+	assert.False(t, url.DeletedAt.IsZero())
+
+	restored, err := storage.RestoreUrls(ctx, 2, []string{"abc"}, time.Now().Add(-time.Minute))
+	assert.NoError(t, err)
+	assert.Empty(t, restored)
+	restored, err = storage.RestoreUrls(ctx, 1, []string{"abc", "old"}, time.Now().Add(-time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"abc"}, restored)
+	url, err = storage.FindByShortURL(ctx, "abc")
+	assert.NoError(t, err)
+	assert.False(t, url.IsDeleted)
+	assert.True(t, url.DeletedAt.IsZero())
+}
+
+func TestIndexRebuiltFromLog(t *testing.T) {
+	logger.Init(slog.LevelInfo)
+	config := config.Config{
+		FileStoragePath: t.TempDir() + "/test_data",
+	}
+	// запись в формате без поля op должна читаться как сохранение URL
+	err := os.WriteFile(config.FileStoragePath, []byte(
+		`{"uuid":1,"short_url":"legacy","original_url":"https://example.com/legacy","created_by":3,"is_deleted":false}`+"\n"), 0666)
+	assert.NoError(t, err)
+	storage, err := NewFileStorage(config)
+	assert.NoError(t, err)
+	ctx := context.Background()
+	now := time.Now()
+	for _, url := range []models.URL{
+		{ShortURL: "abc", OriginalURL: "https://example.com", CreatedBy: 1},
+		{ShortURL: "def", OriginalURL: "https://example.org", CreatedBy: 1},
+		{ShortURL: "expired", OriginalURL: "https://example.net", CreatedBy: 2, ExpiresAt: now.Add(-time.Minute)},
+	} {
+		assert.NoError(t, storage.Save(ctx, url))
+	}
+	_, err = storage.DeleteUrls(ctx, []models.URLToDelete{{UserID: 1, ShortURL: "abc"}, {UserID: 1, ShortURL: "def"}})
+	assert.NoError(t, err)
+	_, err = storage.RestoreUrls(ctx, 1, []string{"def"}, now.Add(-time.Minute))
+	assert.NoError(t, err)
+	count, err := storage.DeleteExpired(ctx, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	assert.NoError(t, storage.Close())
+	storage, err = NewFileStorage(config)
+	assert.NoError(t, err)
+	defer storage.Close()
+	abc, err := storage.FindByShortURL(ctx, "abc")
+	assert.NoError(t, err)
+	assert.True(t, abc.IsDeleted)
+	def, err := storage.FindByShortURL(ctx, "def")
+	assert.NoError(t, err)
+	assert.False(t, def.IsDeleted)
+	exists, err := storage.IsShortURLExists(ctx, "expired")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+	legacy, err := storage.FindByShortURL(ctx, "legacy")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://example.com/legacy", legacy.OriginalURL)
+	stats, err := storage.GetStats(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, models.Stats{URLS: 2, Users: 3}, stats)
+	user, err := storage.CreateUser(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, user.ID)
+	err = storage.Save(ctx, models.URL{ShortURL: "ghi", OriginalURL: "https://example.com/ghi", CreatedBy: 1})
+	assert.NoError(t, err)
+	ghi, err := storage.FindByShortURL(ctx, "ghi")
+	assert.NoError(t, err)
+	assert.Equal(t, 5, ghi.ID)
+}
+
+func TestCreateUser(t *testing.T) {
+	logger.Init(slog.LevelInfo)
+	config := config.Config{
+		FileStoragePath: t.TempDir() + "/test_data",
+	}
+	storage, err := NewFileStorage(config)
+	assert.NoError(t, err)
+	ctx := context.Background()
+	first, err := storage.CreateUser(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, first.ID)
+	second, err := storage.CreateUser(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, second.ID)
+	assert.NoError(t, storage.Close())
+
+	// пользователи без URL не теряются после перезапуска, и их идентификаторы не выдаются повторно
+	storage, err = NewFileStorage(config)
+	assert.NoError(t, err)
+	defer storage.Close()
+	stats, err := storage.GetStats(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, stats.Users)
+	third, err := storage.CreateUser(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, third.ID)
+}
+
+func TestNextSequence(t *testing.T) {
+	logger.Init(slog.LevelInfo)
+	config := config.Config{
+		FileStoragePath: t.TempDir() + "/test_data",
+	}
+	storage, err := NewFileStorage(config)
+	assert.NoError(t, err)
+	ctx := context.Background()
+	for want := int64(1); want <= sequenceBlock+1; want++ {
+		sequence, err := storage.NextSequence(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, want, sequence)
+	}
+	assert.NoError(t, storage.Compact())
+	assert.NoError(t, storage.Close())
+
+	// после перезапуска номера не повторяются: неиспользованный остаток зарезервированного блока пропускается
+	storage, err = NewFileStorage(config)
+	assert.NoError(t, err)
+	defer storage.Close()
+	sequence, err := storage.NextSequence(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2*sequenceBlock+1), sequence)
+}
+
+func TestSaveDedupe(t *testing.T) {
+	logger.Init(slog.LevelInfo)
+	storage, err := NewFileStorage(config.Config{
+		FileStoragePath: t.TempDir() + "/test_data",
+	})
+	assert.NoError(t, err)
+	defer storage.Close()
+	ctx := context.Background()
+	err = storage.Save(ctx, models.URL{ShortURL: "abc", OriginalURL: "https://example.com", CreatedBy: 1})
+	assert.NoError(t, err)
+	err = storage.Save(ctx, models.URL{ShortURL: "def", OriginalURL: "https://example.com", CreatedBy: 1})
+	assert.NoError(t, err)
+	err = storage.Save(ctx, models.URL{ShortURL: "ghi", OriginalURL: "https://example.com", CreatedBy: 2, Dedupe: true})
+	assert.NoError(t, err)
+
+	err = storage.Save(ctx, models.URL{ShortURL: "jkl", OriginalURL: "https://example.com", CreatedBy: 2, Dedupe: true})
+	var customErr *customerrors.CustomError
+	assert.ErrorAs(t, err, &customErr)
+	assert.ErrorIs(t, err, customerrors.ErrOriginalURLAlreadyExists)
+	assert.Equal(t, "ghi", customErr.ShortURL)
+	exists, err := storage.IsShortURLExists(ctx, "jkl")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestSaveBatchPartial(t *testing.T) {
+	logger.Init(slog.LevelInfo)
+	config := config.Config{
+		FileStoragePath: t.TempDir() + "/test_data",
+	}
+	storage, err := NewFileStorage(config)
+	assert.NoError(t, err)
+	ctx := context.Background()
+	err = storage.Save(ctx, models.URL{ShortURL: "abc", OriginalURL: "https://example.com", CreatedBy: 1})
+	assert.NoError(t, err)
+
+	errs, err := storage.SaveBatchPartial(ctx, []models.URL{
+		{ShortURL: "abc", OriginalURL: "https://example.org", CreatedBy: 1},
+		{ShortURL: "def", OriginalURL: "https://example.org", CreatedBy: 1},
+		{ShortURL: "def", OriginalURL: "https://example.net", CreatedBy: 1},
+		{ShortURL: "ghi", OriginalURL: "https://example.com", CreatedBy: 1, Dedupe: true},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, errs, 4)
+	assert.ErrorIs(t, errs[0], customerrors.ErrShortURLAlreadyExists)
+	assert.NoError(t, errs[1])
+	assert.ErrorIs(t, errs[2], customerrors.ErrShortURLAlreadyExists)
+	assert.ErrorIs(t, errs[3], customerrors.ErrOriginalURLAlreadyExists)
+	assert.NoError(t, storage.Close())
+
+	storage, err = NewFileStorage(config)
+	assert.NoError(t, err)
+	defer storage.Close()
+	urls, err := storage.FindByUser(ctx, 1, models.URLQuery{SortBy: models.SortByShortURL})
+	assert.NoError(t, err)
+	assert.Len(t, urls, 2)
+	assert.Equal(t, "https://example.org", urls[1].OriginalURL)
+}
+
+func TestTornRecordRecovered(t *testing.T) {
+	logger.Init(slog.LevelInfo)
+	config := config.Config{
+		FileStoragePath: t.TempDir() + "/test_data",
+	}
+	storage, err := NewFileStorage(config)
+	assert.NoError(t, err)
+	ctx := context.Background()
+	err = storage.Save(ctx, models.URL{ShortURL: "abc", OriginalURL: "https://example.com", CreatedBy: 1})
+	assert.NoError(t, err)
+	assert.NoError(t, storage.Close())
+	complete, err := os.ReadFile(config.FileStoragePath)
+	assert.NoError(t, err)
+
+	// запись, оборванная сбоем посреди строки
+	line, err := encodeRecord(newURLInFile(2, models.URL{ShortURL: "def", OriginalURL: "https://example.org"}))
+	assert.NoError(t, err)
+	err = os.WriteFile(config.FileStoragePath, append(complete, line[:len(line)/2]...), 0666)
+	assert.NoError(t, err)
+
+	storage, err = NewFileStorage(config)
+	assert.NoError(t, err)
+	recovered, err := os.ReadFile(config.FileStoragePath)
+	assert.NoError(t, err)
+	assert.Equal(t, complete, recovered)
+	exists, err := storage.IsShortURLExists(ctx, "def")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+	err = storage.Save(ctx, models.URL{ShortURL: "ghi", OriginalURL: "https://example.net", CreatedBy: 1})
+	assert.NoError(t, err)
+	assert.NoError(t, storage.Close())
+
+	storage, err = NewFileStorage(config)
+	assert.NoError(t, err)
+	defer storage.Close()
+	for _, shortURL := range []string{"abc", "ghi"} {
+		_, err := storage.FindByShortURL(ctx, shortURL)
+		assert.NoError(t, err, shortURL)
+	}
+}
+
+func TestCorruptedRecordSkipped(t *testing.T) {
+	logger.Init(slog.LevelInfo)
+	config := config.Config{
+		FileStoragePath: t.TempDir() + "/test_data",
+	}
+	var content []byte
+	for i, shortURL := range []string{"abc", "def", "ghi"} {
+		line, err := encodeRecord(newURLInFile(i+1, models.URL{ShortURL: shortURL, OriginalURL: "https://example.com/" + shortURL}))
+		assert.NoError(t, err)
+		if shortURL == "def" {
+			line = bytes.Replace(line, []byte("example"), []byte("exampla"), 1)
+		}
+		content = append(content, line...)
+	}
+	err := os.WriteFile(config.FileStoragePath, content, 0666)
+	assert.NoError(t, err)
+
+	storage, err := NewFileStorage(config)
+	assert.NoError(t, err)
+	defer storage.Close()
+	for shortURL, exists := range map[string]bool{"abc": true, "def": false, "ghi": true} {
+		ok, err := storage.IsShortURLExists(context.Background(), shortURL)
+		assert.NoError(t, err)
+		assert.Equal(t, exists, ok, shortURL)
+	}
+}
+
+func TestCompact(t *testing.T) {
+	logger.Init(slog.LevelInfo)
+	config := config.Config{
+		FileStoragePath: t.TempDir() + "/test_data",
+	}
+	storage, err := NewFileStorage(config)
+	assert.NoError(t, err)
+	ctx := context.Background()
+	now := time.Now()
+	for _, url := range []models.URL{
+		{ShortURL: "abc", OriginalURL: "https://example.com", CreatedBy: 1},
+		{ShortURL: "def", OriginalURL: "https://example.org", CreatedBy: 1},
+		{ShortURL: "expired", OriginalURL: "https://example.net", CreatedBy: 2, ExpiresAt: now.Add(-time.Minute)},
+	} {
+		assert.NoError(t, storage.Save(ctx, url))
+	}
+	_, err = storage.DeleteUrls(ctx, []models.URLToDelete{{UserID: 1, ShortURL: "abc"}, {UserID: 1, ShortURL: "def"}})
+	assert.NoError(t, err)
+	_, err = storage.RestoreUrls(ctx, 1, []string{"def"}, now.Add(-time.Minute))
+	assert.NoError(t, err)
+	_, err = storage.DeleteExpired(ctx, now)
+	assert.NoError(t, err)
+	job := models.DeletionJob{ID: "job1", UserID: 1, Status: models.DeletionJobPending, CreatedAt: now, NextAttemptAt: now}
+	assert.NoError(t, storage.SaveDeletionJob(ctx, job))
+	job.Status = models.DeletionJobDone
+	assert.NoError(t, storage.SaveDeletionJob(ctx, job))
+
+	assert.NoError(t, storage.Compact())
+	assert.Equal(t, 2, countLines(t, config.FileStoragePath))
+	assert.Equal(t, 1, countLines(t, config.FileStoragePath+deletionsFileSuffix))
+	_, err = os.Stat(config.FileStoragePath + ".tmp")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	err = storage.Save(ctx, models.URL{ShortURL: "ghi", OriginalURL: "https://example.com/ghi", CreatedBy: 1})
+	assert.NoError(t, err)
+	assert.NoError(t, storage.Close())
+
+	storage, err = NewFileStorage(config)
+	assert.NoError(t, err)
+	defer storage.Close()
+	urls, err := storage.FindByUser(ctx, 1, models.URLQuery{SortBy: models.SortByShortURL})
+	assert.NoError(t, err)
+	assert.Len(t, urls, 3)
+	assert.True(t, urls[0].IsDeleted)
+	assert.False(t, urls[0].DeletedAt.IsZero())
+	assert.False(t, urls[1].IsDeleted)
+	found, err := storage.FindDeletionJob(ctx, "job1")
+	assert.NoError(t, err)
+	assert.Equal(t, models.DeletionJobDone, found.Status)
+}
+
+func TestClickStats(t *testing.T) {
+	logger.Init(slog.LevelInfo)
+	config := config.Config{
+		FileStoragePath: t.TempDir() + "/test_data",
+	}
+	storage, err := NewFileStorage(config)
+	assert.NoError(t, err)
+	ctx := context.Background()
+	day := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	clicks := []models.Click{
+		{ShortURL: "abc", Timestamp: day, Referrer: "https://ya.ru", UserAgent: "curl", ClientIP: "10.0.0.1"},
+		{ShortURL: "abc", Timestamp: day.Add(24 * time.Hour), UserAgent: "wget", ClientIP: "10.0.0.2"},
+		{ShortURL: "def", Timestamp: day, ClientIP: "10.0.0.1"},
+	}
+	assert.NoError(t, storage.SaveClicks(ctx, clicks[:2]))
+	expected := clickstats.Aggregate("abc", clicks, 10)
+	stats, err := storage.GetClickStats(ctx, "abc", 10)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, stats)
+
+	// события сворачиваются в счетчики при сжатии, и статистика не меняется после перезапуска
+	assert.NoError(t, storage.Compact())
+	assert.NoError(t, storage.SaveClicks(ctx, clicks[2:]))
+	assert.NoError(t, storage.Close())
+	storage, err = NewFileStorage(config)
+	assert.NoError(t, err)
+	defer storage.Close()
+	stats, err = storage.GetClickStats(ctx, "abc", 10)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, stats)
+	stats, err = storage.GetClickStats(ctx, "def", 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, stats.TotalClicks)
+	stats, err = storage.GetClickStats(ctx, "unknown", 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, stats.TotalClicks)
+	data, err := os.ReadFile(config.FileStoragePath + clicksFileSuffix)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(data), "\n"), "compacted journal must keep one record per link plus new events")
+}
+
+func TestSyncPolicy(t *testing.T) {
+	logger.Init(slog.LevelInfo)
+	for _, policy := range []string{SyncAlways, SyncInterval, SyncNever} {
+		storage, err := NewFileStorage(config.Config{
+			FileStoragePath:  t.TempDir() + "/test_data",
+			FileSyncPolicy:   policy,
+			FileSyncInterval: config.Duration{Duration: time.Millisecond},
+		})
+		assert.NoError(t, err, policy)
+		err = storage.Save(context.Background(), models.URL{ShortURL: "abc", OriginalURL: "https://example.com"})
+		assert.NoError(t, err, policy)
+		assert.NoError(t, storage.Close(), policy)
+	}
+	_, err := NewFileStorage(config.Config{FileStoragePath: t.TempDir() + "/test_data", FileSyncPolicy: "sometimes"})
+	assert.Error(t, err)
+}
+
+func countLines(t *testing.T, path string) int {
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	return bytes.Count(content, []byte("\n"))
+}
+
+func BenchmarkFindByShortURL(b *testing.B) {
+	logger.Init(slog.LevelInfo)
+	for _, size := range []int{1000, 10000, 100000} {
+		b.Run(fmt.Sprintf("urls=%d", size), func(b *testing.B) {
+			storage, err := NewFileStorage(config.Config{FileStoragePath: b.TempDir() + "/test_data"})
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer storage.Close()
+			urls := make([]models.URL, size)
+			for i := range urls {
+				urls[i] = models.URL{
+					ShortURL:    fmt.Sprintf("short%d", i),
+					OriginalURL: fmt.Sprintf("https://example.com/%d", i),
+					CreatedBy:   i%100 + 1,
+				}
+			}
+			if _, err := storage.SaveBatchPartial(context.Background(), urls); err != nil {
+				b.Fatal(err)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := storage.FindByShortURL(context.Background(), urls[i%size].ShortURL); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkIsShortURLExists(b *testing.B) {
+	logger.Init(slog.LevelInfo)
+	for _, size := range []int{1000, 10000, 100000} {
+		b.Run(fmt.Sprintf("urls=%d", size), func(b *testing.B) {
+			storage, err := NewFileStorage(config.Config{FileStoragePath: b.TempDir() + "/test_data"})
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer storage.Close()
+			urls := make([]models.URL, size)
+			for i := range urls {
+				urls[i] = models.URL{ShortURL: fmt.Sprintf("short%d", i), OriginalURL: fmt.Sprintf("https://example.com/%d", i)}
+			}
+			if _, err := storage.SaveBatchPartial(context.Background(), urls); err != nil {
+				b.Fatal(err)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := storage.IsShortURLExists(context.Background(), "missing"); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestPing(t *testing.T) {
+	logger.Init(slog.LevelInfo)
+	path := t.TempDir() + "/test_data"
+	storage, err := NewFileStorage(config.Config{FileStoragePath: path})
+	assert.NoError(t, err)
+	assert.True(t, storage.Ping(context.Background()), "new empty storage must be available")
+
+	assert.NoError(t, storage.Save(context.Background(), models.URL{ShortURL: "abc", OriginalURL: "https://example.com"}))
+	assert.NoError(t, storage.Compact())
+	assert.True(t, storage.Ping(context.Background()), "storage must be available after compaction")
+
+	assert.NoError(t, os.Remove(path+usersFileSuffix))
+	assert.False(t, storage.Ping(context.Background()), "storage must be unavailable without journal file")
+	assert.NoError(t, storage.Close())
+	assert.False(t, storage.Ping(context.Background()), "closed storage must be unavailable")
+}
+
+func TestAdmin(t *testing.T) {
+	logger.Init(slog.LevelInfo)
+	config := config.Config{
+		FileStoragePath: t.TempDir() + "/test_data",
+	}
+	storage, err := NewFileStorage(config)
+	assert.NoError(t, err)
+	ctx := context.Background()
+	for _, url := range []models.URL{
+		{ShortURL: "abc", OriginalURL: "https://sub.example.com/x", CreatedBy: 1},
+		{ShortURL: "def", OriginalURL: "https://notexample.com", CreatedBy: 1},
+		{ShortURL: "ghi", OriginalURL: "https://example.com", CreatedBy: 2},
+	} {
+		assert.NoError(t, storage.Save(ctx, url))
+	}
+	assert.NoError(t, storage.SetURLDisabled(ctx, "def", true))
+	assert.ErrorIs(t, storage.SetURLOwner(ctx, "abc", 3), customerrors.ErrUserNotFound)
+	assert.NoError(t, storage.SetURLOwner(ctx, "abc", 2))
+	deleted, err := storage.DeleteUrlsOfDomain(ctx, "example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"abc", "ghi"}, deleted)
+
+	// состояние восстанавливается из записей-пометок журнала
+	assert.NoError(t, storage.Close())
+	storage, err = NewFileStorage(config)
+	assert.NoError(t, err)
+	defer storage.Close()
+	def, err := storage.FindByShortURL(ctx, "def")
+	assert.NoError(t, err)
+	assert.True(t, def.IsDisabled)
+	assert.False(t, def.IsDeleted)
+	abc, err := storage.FindByShortURL(ctx, "abc")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, abc.CreatedBy)
+	assert.True(t, abc.IsDeleted)
+	urls, err := storage.FindByUser(ctx, 2, models.URLQuery{})
+	assert.NoError(t, err)
+	assert.Len(t, urls, 2)
+
+	assert.NoError(t, storage.Compact())
+	assert.NoError(t, storage.SetURLDisabled(ctx, "def", false))
+	deleted, err = storage.DeleteUrlsOfUser(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"def"}, deleted)
+	def, err = storage.FindByShortURL(ctx, "def")
 	assert.NoError(t, err)
+	assert.False(t, def.IsDisabled)
+	assert.True(t, def.IsDeleted)
 }
@@ -0,0 +1,252 @@
+package file
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/logger"
+)
+
+// Политики синхронизации файлов хранилища с диском.
+const (
+	SyncAlways   = "always"   // SyncAlways fsync после каждой записи.
+	SyncInterval = "interval" // SyncInterval fsync в фоне не реже интервала синхронизации.
+	SyncNever    = "never"    // SyncNever синхронизация остается на усмотрение операционной системы.
+)
+
+// recordInFile строка журнала: данные записи и их контрольная сумма CRC-32.
+// Строки без поля data записаны в формате без контрольной суммы и читаются как данные целиком.
+type recordInFile struct {
+	CRC  uint32          `json:"crc"`
+	Data json.RawMessage `json:"data"`
+}
+
+// encodeRecord кодирует запись в строку журнала с контрольной суммой.
+func encodeRecord(record any) ([]byte, error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+	line, err := json.Marshal(recordInFile{CRC: crc32.ChecksumIEEE(data), Data: data})
+	if err != nil {
+		return nil, err
+	}
+	return append(line, '\n'), nil
+}
+
+// decodeRecord проверяет строку журнала и возвращает данные записи.
+func decodeRecord(line []byte) ([]byte, error) {
+	var record recordInFile
+	if err := json.Unmarshal(line, &record); err != nil {
+		return nil, err
+	}
+	if record.Data == nil {
+		return line, nil
+	}
+	if crc32.ChecksumIEEE(record.Data) != record.CRC {
+		return nil, errors.New("checksum mismatch")
+	}
+	return record.Data, nil
+}
+
+// readJournal читает журнал path и передает данные каждой записи в apply.
+// Поврежденные записи пропускаются. Последняя строка без перевода строки считается оборванной при сбое записью
+// и тоже пропускается. Возвращает размер корректной части журнала, после которой такая строка начинается.
+func readJournal(path string, apply func(data []byte) error) (int64, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	reader := bufio.NewReader(file)
+	var size int64
+	for lineNumber := 1; ; lineNumber++ {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			if len(line) > 0 {
+				logger.Logger.Warn("torn record at the end of file is skipped", "path", path, "line", lineNumber)
+			}
+			return size, nil
+		}
+		if err != nil {
+			return size, err
+		}
+		size += int64(len(line))
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		data, err := decodeRecord(line)
+		if err == nil {
+			err = apply(data)
+		}
+		if err != nil {
+			logger.Logger.Error("corrupted record is skipped", "path", path, "line", lineNumber, "error", err)
+		}
+	}
+}
+
+// journal файл, в который записи только дописываются.
+type journal struct {
+	path     string
+	file     *os.File
+	size     int64
+	policy   string
+	unsynced bool
+}
+
+// openJournal открывает журнал для дописывания.
+// validSize размер корректной части журнала: оборванная последняя строка отрезается, чтобы новые записи не склеились с ней.
+func openJournal(path string, policy string, validSize int64) (*journal, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0666)
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	if info.Size() > validSize {
+		logger.Logger.Warn("file is truncated to the last complete record", "path", path, "size", validSize)
+		if err := file.Truncate(validSize); err != nil {
+			file.Close()
+			return nil, err
+		}
+		if err := file.Sync(); err != nil {
+			file.Close()
+			return nil, err
+		}
+	}
+	return &journal{path: path, file: file, size: validSize, policy: policy}, nil
+}
+
+// append дописывает записи в журнал одной операцией записи.
+// Если запись не удалась, журнал обрезается до прежнего размера.
+func (j *journal) append(records ...any) error {
+	var buf bytes.Buffer
+	for _, record := range records {
+		line, err := encodeRecord(record)
+		if err != nil {
+			return err
+		}
+		buf.Write(line)
+	}
+	n, err := j.file.Write(buf.Bytes())
+	if err != nil {
+		if n > 0 {
+			if err := j.file.Truncate(j.size); err != nil {
+				logger.Logger.Error("failed to truncate partially written record", "path", j.path, "error", err)
+			}
+		}
+		return err
+	}
+	j.size += int64(n)
+	j.unsynced = true
+	if j.policy == SyncAlways {
+		return j.sync()
+	}
+	return nil
+}
+
+// sync сбрасывает записанные данные на диск, если после прошлой синхронизации были записи.
+func (j *journal) sync() error {
+	if !j.unsynced {
+		return nil
+	}
+	if err := j.file.Sync(); err != nil {
+		return err
+	}
+	j.unsynced = false
+	return nil
+}
+
+// rewrite атомарно заменяет содержимое журнала записями records.
+// Записи пишутся во временный файл, который после fsync переименовывается поверх журнала,
+// поэтому при сбое на диске остается либо прежний, либо новый журнал целиком.
+func (j *journal) rewrite(records []any) error {
+	tmpPath := j.path + ".tmp"
+	tmp, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpPath)
+	writer := bufio.NewWriter(tmp)
+	var size int64
+	for _, record := range records {
+		line, err := encodeRecord(record)
+		if err != nil {
+			tmp.Close()
+			return err
+		}
+		n, _ := writer.Write(line)
+		size += int64(n)
+	}
+	if err := writer.Flush(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, j.path); err != nil {
+		return err
+	}
+	if err := syncDir(filepath.Dir(j.path)); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(j.path, os.O_WRONLY|os.O_APPEND, 0666)
+	if err != nil {
+		return fmt.Errorf("reopen %s after rewrite: %w", j.path, err)
+	}
+	j.file.Close()
+	j.file = file
+	j.size = size
+	j.unsynced = false
+	return nil
+}
+
+// ping проверяет, что файл журнала открыт и все еще находится по пути журнала.
+func (j *journal) ping() error {
+	opened, err := j.file.Stat()
+	if err != nil {
+		return err
+	}
+	current, err := os.Stat(j.path)
+	if err != nil {
+		return err
+	}
+	if !os.SameFile(opened, current) {
+		return fmt.Errorf("%s was replaced", j.path)
+	}
+	return nil
+}
+
+// close синхронизирует и закрывает журнал.
+func (j *journal) close() error {
+	return errors.Join(j.sync(), j.file.Close())
+}
+
+// syncDir сбрасывает на диск изменения каталога, например переименование файла.
+func syncDir(path string) error {
+	dir, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+	return dir.Sync()
+}
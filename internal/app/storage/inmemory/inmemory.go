@@ -2,32 +2,44 @@ package inmemory
 
 import (
 	"context"
+	"slices"
+	"sort"
 	"sync"
-	"sync/atomic"
-
-	"errors"
+	"time"
 
 	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/config"
 	customerrors "github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/errors"
 	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/models"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/storage/clickstats"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/storage/urlquery"
 )
 
 // StorageInMemory представляет хранилище URL-ов в памяти.
 type StorageInMemory struct {
 	urls        map[string]models.URL
-	urlsOfUsers map[int][]models.URL
+	urlsOfUsers map[int][]string
+	clicks      map[string][]models.Click
+	deletions   map[string]models.DeletionJob
+	users       map[int]models.User
+	nextUserID  int
+	sequence    int64
 	sync.RWMutex
-	userIDSeq atomic.Int64
-	config    config.Config
+	config config.Config
 }
 
 // NewInMemoryStorage создает новый экземпляр хранилища URL-ов в памяти.
+// Идентификаторы пользователей начинаются с 1, так как 0 означает отсутствие пользователя.
 func NewInMemoryStorage(config config.Config) *StorageInMemory {
-	return &StorageInMemory{
+	storage := &StorageInMemory{
 		urls:        make(map[string]models.URL),
-		urlsOfUsers: make(map[int][]models.URL),
+		urlsOfUsers: make(map[int][]string),
+		clicks:      make(map[string][]models.Click),
+		deletions:   make(map[string]models.DeletionJob),
+		users:       make(map[int]models.User),
+		nextUserID:  1,
 		config:      config,
 	}
+	return storage
 }
 
 // FindByShortURL находит оригинальный URL по сокращенному URL.
@@ -36,36 +48,95 @@ func (storage *StorageInMemory) FindByShortURL(_ context.Context, shortURL strin
 	defer storage.RUnlock()
 	url, ok := storage.urls[shortURL]
 	if !ok {
-		return nil, customerrors.NewCustomErrorBadRequest(errors.New("original url isn't found"))
+		return nil, customerrors.NewCustomErrorBadRequest(customerrors.ErrURLNotFound)
 	}
-	return &models.URL{
-		ShortURL:    shortURL,
-		OriginalURL: url.OriginalURL,
-	}, nil
+	return &url, nil
 }
 
 // Save сохраняет URL в хранилище.
 func (storage *StorageInMemory) Save(ctx context.Context, url models.URL) error {
 	storage.Lock()
 	defer storage.Unlock()
+	if err := storage.checkURL(url, nil, time.Now()); err != nil {
+		return err
+	}
+	storage.saveURL(ctx, url)
+	return nil
+}
+
+func (storage *StorageInMemory) saveURL(ctx context.Context, url models.URL) {
+	url.Dedupe = false
 	storage.saveURLForUser(ctx, url)
 	storage.urls[url.ShortURL] = url
+}
+
+// checkURL проверяет, можно ли сохранить URL, если перед ним в том же пакете сохраняются URL accepted.
+func (storage *StorageInMemory) checkURL(url models.URL, accepted []models.URL, now time.Time) error {
+	if _, ok := storage.urls[url.ShortURL]; ok {
+		return customerrors.NewCustomErrorConflict(customerrors.ErrShortURLAlreadyExists)
+	}
+	if duplicate, ok := storage.findDuplicate(url, now); ok {
+		return customerrors.NewCustomErrorOriginalURLConflict(duplicate)
+	}
+	for _, previous := range accepted {
+		if previous.ShortURL == url.ShortURL {
+			return customerrors.NewCustomErrorConflict(customerrors.ErrShortURLAlreadyExists)
+		}
+		if url.Dedupe && url.IsDuplicateOf(previous, now) {
+			return customerrors.NewCustomErrorOriginalURLConflict(previous.ShortURL)
+		}
+	}
 	return nil
 }
 
+// checkBatch проверяет каждый URL из списка с учетом URL, которые сохраняются перед ним.
+func (storage *StorageInMemory) checkBatch(urls []models.URL) []error {
+	now := time.Now()
+	errs := make([]error, len(urls))
+	accepted := make([]models.URL, 0, len(urls))
+	for i, url := range urls {
+		errs[i] = storage.checkURL(url, accepted, now)
+		if errs[i] == nil {
+			accepted = append(accepted, url)
+		}
+	}
+	return errs
+}
+
+// findDuplicate возвращает сокращенный URL, который пользователь уже создал для исходного URL, если url требует дедупликации.
+func (storage *StorageInMemory) findDuplicate(url models.URL, now time.Time) (string, bool) {
+	if !url.Dedupe {
+		return "", false
+	}
+	for _, shortURL := range storage.urlsOfUsers[url.CreatedBy] {
+		if url.IsDuplicateOf(storage.urls[shortURL], now) {
+			return shortURL, true
+		}
+	}
+	return "", false
+}
+
 func (storage *StorageInMemory) saveURLForUser(_ context.Context, url models.URL) {
 	if url.CreatedBy == 0 {
 		return
 	}
+	storage.registerUser(url.CreatedBy, url.CreatedTS)
 	urls, ok := storage.urlsOfUsers[url.CreatedBy]
 	if ok {
-		urls = append(urls, url)
-		storage.urlsOfUsers[url.CreatedBy] = urls
+		storage.urlsOfUsers[url.CreatedBy] = append(urls, url.ShortURL)
+		return
+	}
+	storage.urlsOfUsers[url.CreatedBy] = []string{url.ShortURL}
+}
+
+// registerUser запоминает пользователя, создавшего URL, если он еще не зарегистрирован,
+// чтобы его идентификатор не был выдан повторно.
+func (storage *StorageInMemory) registerUser(userID int, createdAt time.Time) {
+	if _, ok := storage.users[userID]; ok {
 		return
 	}
-	storage.userIDSeq.Add(1)
-	storage.urlsOfUsers[url.CreatedBy] = make([]models.URL, 1)
-	storage.urlsOfUsers[url.CreatedBy][0] = url
+	storage.users[userID] = models.User{ID: userID, CreatedAt: createdAt}
+	storage.nextUserID = max(storage.nextUserID, userID+1)
 }
 
 // Ping проверяет доступность хранилища.
@@ -73,49 +144,168 @@ func (storage *StorageInMemory) Ping(_ context.Context) bool {
 	return true
 }
 
-// SaveBatch сохраняет список URL в хранилище.
-func (storage *StorageInMemory) SaveBatch(ctx context.Context, urls []models.URL) error {
+// SaveBatchPartial сохраняет URL из списка независимо друг от друга и возвращает ошибки сохранения по индексам URL.
+func (storage *StorageInMemory) SaveBatchPartial(ctx context.Context, urls []models.URL) ([]error, error) {
 	storage.Lock()
 	defer storage.Unlock()
-	for _, url := range urls {
-		storage.saveURLForUser(ctx, url)
-		storage.urls[url.ShortURL] = url
+	errs := storage.checkBatch(urls)
+	for i, url := range urls {
+		if errs[i] == nil {
+			storage.saveURL(ctx, url)
+		}
 	}
-	return nil
+	return errs, nil
 }
 
-// GetUserID возвращает идентификатор пользователя из хранилища.
-func (storage *StorageInMemory) GetUserID(context.Context) int {
-	userID := storage.userIDSeq.Load()
-	storage.userIDSeq.Add(1)
-	return int(userID)
+// CreateUser регистрирует нового пользователя с новым идентификатором.
+func (storage *StorageInMemory) CreateUser(context.Context) (models.User, error) {
+	storage.Lock()
+	defer storage.Unlock()
+	user := models.User{ID: storage.nextUserID, CreatedAt: time.Now()}
+	storage.users[user.ID] = user
+	storage.nextUserID++
+	return user, nil
 }
 
-// FindByUser находит URL, созданные конкретным пользователем.
-func (storage *StorageInMemory) FindByUser(ctx context.Context, userID int) ([]models.URL, error) {
+// NextSequence возвращает следующий номер последовательности сокращенных URL.
+func (storage *StorageInMemory) NextSequence(context.Context) (int64, error) {
+	storage.Lock()
+	defer storage.Unlock()
+	storage.sequence++
+	return storage.sequence, nil
+}
+
+// FindByUser находит URL, созданные конкретным пользователем, с учетом фильтров, сортировки и курсора запроса.
+func (storage *StorageInMemory) FindByUser(_ context.Context, userID int, query models.URLQuery) ([]models.URL, error) {
 	storage.RLock()
 	defer storage.RUnlock()
-	urls, ok := storage.urlsOfUsers[userID]
-	if !ok {
-		return nil, customerrors.NewCustomErrorBadRequest(errors.New("original url isn't found"))
+	shortURLs := storage.urlsOfUsers[userID]
+	urls := make([]models.URL, 0, len(shortURLs))
+	for _, shortURL := range shortURLs {
+		urls = append(urls, storage.urls[shortURL])
 	}
-	return urls, nil
+	return urlquery.Apply(urls, query), nil
 }
 
-// DeleteUrls удаляет список URL из хранилища.
-func (storage *StorageInMemory) DeleteUrls(_ context.Context, urls []models.URLToDelete) error {
+// DeleteUrls помечает удаленными URL из списка, созданные указанными пользователями.
+func (storage *StorageInMemory) DeleteUrls(_ context.Context, urls []models.URLToDelete) ([]string, error) {
 	storage.Lock()
 	defer storage.Unlock()
+	now := time.Now()
+	deleted := make([]string, 0, len(urls))
 	for _, url := range urls {
 		el, ok := storage.urls[url.ShortURL]
 		if ok && el.CreatedBy == url.UserID {
-			el.IsDeleted = true
-			storage.urls[url.ShortURL] = el
+			if !el.IsDeleted {
+				el.IsDeleted = true
+				el.DeletedAt = now
+				storage.urls[url.ShortURL] = el
+			}
+			deleted = append(deleted, url.ShortURL)
+		}
+	}
+	return deleted, nil
+}
+
+// RestoreUrls снимает пометку удаления с URL из списка, созданных пользователем и удаленных не раньше deletedAfter.
+func (storage *StorageInMemory) RestoreUrls(_ context.Context, userID int, shortURLs []string, deletedAfter time.Time) ([]string, error) {
+	storage.Lock()
+	defer storage.Unlock()
+	restored := make([]string, 0, len(shortURLs))
+	for _, shortURL := range shortURLs {
+		el, ok := storage.urls[shortURL]
+		if ok && el.CreatedBy == userID && el.IsDeleted && !el.DeletedAt.IsZero() && !el.DeletedAt.Before(deletedAfter) {
+			el.IsDeleted = false
+			el.DeletedAt = time.Time{}
+			storage.urls[shortURL] = el
+			restored = append(restored, shortURL)
 		}
 	}
+	return restored, nil
+}
+
+// SetURLDisabled отключает или включает URL.
+func (storage *StorageInMemory) SetURLDisabled(_ context.Context, shortURL string, disabled bool) error {
+	storage.Lock()
+	defer storage.Unlock()
+	url, ok := storage.urls[shortURL]
+	if !ok {
+		return customerrors.NewCustomErrorBadRequest(customerrors.ErrURLNotFound)
+	}
+	url.IsDisabled = disabled
+	storage.urls[shortURL] = url
+	return nil
+}
+
+// SetURLOwner передает URL зарегистрированному пользователю userID.
+func (storage *StorageInMemory) SetURLOwner(_ context.Context, shortURL string, userID int) error {
+	storage.Lock()
+	defer storage.Unlock()
+	url, ok := storage.urls[shortURL]
+	if !ok {
+		return customerrors.NewCustomErrorBadRequest(customerrors.ErrURLNotFound)
+	}
+	if _, ok := storage.users[userID]; !ok {
+		return customerrors.NewCustomErrorBadRequest(customerrors.ErrUserNotFound)
+	}
+	if url.CreatedBy == userID {
+		return nil
+	}
+	storage.removeURLOfUser(url)
+	url.CreatedBy = userID
+	storage.urlsOfUsers[userID] = append(storage.urlsOfUsers[userID], shortURL)
+	storage.urls[shortURL] = url
 	return nil
 }
 
+// removeURLOfUser удаляет URL из списка URL пользователя, создавшего его.
+func (storage *StorageInMemory) removeURLOfUser(url models.URL) {
+	shortURLs := slices.DeleteFunc(storage.urlsOfUsers[url.CreatedBy], func(shortURL string) bool {
+		return shortURL == url.ShortURL
+	})
+	if len(shortURLs) == 0 {
+		delete(storage.urlsOfUsers, url.CreatedBy)
+		return
+	}
+	storage.urlsOfUsers[url.CreatedBy] = shortURLs
+}
+
+// DeleteUrlsOfUser помечает удаленными все URL пользователя userID.
+func (storage *StorageInMemory) DeleteUrlsOfUser(_ context.Context, userID int) ([]string, error) {
+	storage.Lock()
+	defer storage.Unlock()
+	return storage.deleteWhere(storage.urlsOfUsers[userID], func(models.URL) bool { return true }), nil
+}
+
+// DeleteUrlsOfDomain помечает удаленными все URL, исходный URL которых ведет на домен domain или его поддомен.
+func (storage *StorageInMemory) DeleteUrlsOfDomain(_ context.Context, domain string) ([]string, error) {
+	storage.Lock()
+	defer storage.Unlock()
+	shortURLs := make([]string, 0)
+	for shortURL := range storage.urls {
+		shortURLs = append(shortURLs, shortURL)
+	}
+	slices.Sort(shortURLs)
+	return storage.deleteWhere(shortURLs, func(url models.URL) bool { return url.HasDomain(domain) }), nil
+}
+
+// deleteWhere помечает удаленными еще не удаленные URL из списка, удовлетворяющие условию match, и возвращает их.
+func (storage *StorageInMemory) deleteWhere(shortURLs []string, match func(models.URL) bool) []string {
+	now := time.Now()
+	deleted := make([]string, 0)
+	for _, shortURL := range shortURLs {
+		url, ok := storage.urls[shortURL]
+		if !ok || url.IsDeleted || !match(url) {
+			continue
+		}
+		url.IsDeleted = true
+		url.DeletedAt = now
+		storage.urls[shortURL] = url
+		deleted = append(deleted, shortURL)
+	}
+	return deleted
+}
+
 // IsShortURLExists проверяет, существует ли указанный сокращенный URL в хранилище.
 func (storage *StorageInMemory) IsShortURLExists(_ context.Context, shortURL string) (bool, error) {
 	storage.RLock()
@@ -126,6 +316,8 @@ func (storage *StorageInMemory) IsShortURLExists(_ context.Context, shortURL str
 
 // GetStats возвращает статистику по хранилищу.
 func (storage *StorageInMemory) GetStats(ctx context.Context) (models.Stats, error) {
+	storage.RLock()
+	defer storage.RUnlock()
 	var stats models.Stats
 	countURLS := 0
 	for _, val := range storage.urls {
@@ -134,6 +326,122 @@ func (storage *StorageInMemory) GetStats(ctx context.Context) (models.Stats, err
 		}
 	}
 	stats.URLS = countURLS
-	stats.Users = len(storage.urlsOfUsers)
+	stats.Users = len(storage.users)
 	return stats, nil
 }
+
+// DeleteExpired удаляет из хранилища URL, срок действия которых истек к моменту now.
+func (storage *StorageInMemory) DeleteExpired(_ context.Context, now time.Time) (int, error) {
+	storage.Lock()
+	defer storage.Unlock()
+	count := 0
+	for shortURL, url := range storage.urls {
+		if url.IsExpired(now) {
+			delete(storage.urls, shortURL)
+			count++
+		}
+	}
+	if count == 0 {
+		return 0, nil
+	}
+	for userID, shortURLs := range storage.urlsOfUsers {
+		notExpired := shortURLs[:0]
+		for _, shortURL := range shortURLs {
+			if _, ok := storage.urls[shortURL]; ok {
+				notExpired = append(notExpired, shortURL)
+			}
+		}
+		storage.urlsOfUsers[userID] = notExpired
+	}
+	return count, nil
+}
+
+// Close освобождает ресурсы хранилища. Хранилищу в памяти освобождать нечего.
+func (storage *StorageInMemory) Close() error {
+	return nil
+}
+
+// SaveClicks сохраняет список событий перехода в хранилище.
+func (storage *StorageInMemory) SaveClicks(_ context.Context, clicks []models.Click) error {
+	storage.Lock()
+	defer storage.Unlock()
+	for _, click := range clicks {
+		storage.clicks[click.ShortURL] = append(storage.clicks[click.ShortURL], click)
+	}
+	return nil
+}
+
+// GetClickStats возвращает статистику переходов по сокращенному URL.
+func (storage *StorageInMemory) GetClickStats(_ context.Context, shortURL string, top int) (models.LinkStats, error) {
+	storage.RLock()
+	defer storage.RUnlock()
+	return clickstats.Aggregate(shortURL, storage.clicks[shortURL], top), nil
+}
+
+// SaveDeletionJob сохраняет задание на удаление.
+func (storage *StorageInMemory) SaveDeletionJob(_ context.Context, job models.DeletionJob) error {
+	storage.Lock()
+	defer storage.Unlock()
+	job.URLs = slices.Clone(job.URLs)
+	storage.deletions[job.ID] = job
+	return nil
+}
+
+// FindDeletionJob находит задание на удаление по идентификатору.
+func (storage *StorageInMemory) FindDeletionJob(_ context.Context, id string) (*models.DeletionJob, error) {
+	storage.RLock()
+	defer storage.RUnlock()
+	job, ok := storage.deletions[id]
+	if !ok {
+		return nil, customerrors.NewCustomErrorBadRequest(customerrors.ErrDeletionJobNotFound)
+	}
+	job.URLs = slices.Clone(job.URLs)
+	return &job, nil
+}
+
+// FindPendingDeletionJobs возвращает незавершенные задания на удаление, очередная попытка которых наступила к моменту now.
+func (storage *StorageInMemory) FindPendingDeletionJobs(_ context.Context, now time.Time, limit int) ([]models.DeletionJob, error) {
+	storage.RLock()
+	defer storage.RUnlock()
+	jobs := make([]models.DeletionJob, 0)
+	for _, job := range storage.deletions {
+		if job.Status == models.DeletionJobPending && !job.NextAttemptAt.After(now) {
+			job.URLs = slices.Clone(job.URLs)
+			jobs = append(jobs, job)
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].NextAttemptAt.Before(jobs[j].NextAttemptAt)
+	})
+	if len(jobs) > limit {
+		jobs = jobs[:limit]
+	}
+	return jobs, nil
+}
+
+// CountPendingDeletionJobs возвращает количество незавершенных заданий на удаление.
+func (storage *StorageInMemory) CountPendingDeletionJobs(_ context.Context) (int, error) {
+	storage.RLock()
+	defer storage.RUnlock()
+	count := 0
+	for _, job := range storage.deletions {
+		if job.Status == models.DeletionJobPending {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// DeleteFinishedDeletionJobs удаляет завершенные задания на удаление, последнее изменение которых было раньше before.
+func (storage *StorageInMemory) DeleteFinishedDeletionJobs(_ context.Context, before time.Time) (int, error) {
+	storage.Lock()
+	defer storage.Unlock()
+	count := 0
+	for id, job := range storage.deletions {
+		if job.Status != models.DeletionJobPending && job.UpdatedAt.Before(before) {
+			delete(storage.deletions, id)
+			count++
+		}
+	}
+	return count, nil
+}
@@ -3,8 +3,10 @@ package inmemory
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/config"
+	customerrors "github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/errors"
 	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/models"
 	"github.com/stretchr/testify/assert"
 )
@@ -53,4 +55,122 @@ func TestStorageInMemory_Save(t *testing.T) {
 	assert.Equal(t, url, *foundURL)
 }
 
+func TestStorageInMemory_SaveShortURLConflict(t *testing.T) {
+	storage := NewInMemoryStorage(config.Config{})
+
+	url := models.URL{
+		ShortURL:    "abc",
+		OriginalURL: "https://example.com",
+	}
+	err := storage.Save(context.Background(), url)
+	assert.NoError(t, err)
+
+	// Saving another URL with the same short URL must fail
+	url.OriginalURL = "https://example.org"
+	err = storage.Save(context.Background(), url)
+	assert.ErrorIs(t, err, customerrors.ErrShortURLAlreadyExists)
+}
+
+func TestStorageInMemory_DeleteExpired(t *testing.T) {
+	storage := NewInMemoryStorage(config.Config{})
+	now := time.Now()
+
+	for _, url := range []models.URL{
+		{ShortURL: "expired", OriginalURL: "https://example.com", CreatedBy: 1, ExpiresAt: now.Add(-time.Minute)},
+		{ShortURL: "active", OriginalURL: "https://example.org", CreatedBy: 1, ExpiresAt: now.Add(time.Minute)},
+		{ShortURL: "forever", OriginalURL: "https://example.net", CreatedBy: 1},
+	} {
+		assert.NoError(t, storage.Save(context.Background(), url))
+	}
+
+	count, err := storage.DeleteExpired(context.Background(), now)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	_, err = storage.FindByShortURL(context.Background(), "expired")
+	assert.Error(t, err)
+	urls, err := storage.FindByUser(context.Background(), 1, models.URLQuery{})
+	assert.NoError(t, err)
+	assert.Len(t, urls, 2)
+}
+
+func TestStorageInMemory_DeleteFinishedDeletionJobs(t *testing.T) {
+	storage := NewInMemoryStorage(config.Config{})
+	ctx := context.Background()
+	now := time.Now()
+
+	for _, job := range []models.DeletionJob{
+		{ID: "old-done", Status: models.DeletionJobDone, UpdatedAt: now.Add(-time.Hour)},
+		{ID: "old-failed", Status: models.DeletionJobFailed, UpdatedAt: now.Add(-time.Hour)},
+		{ID: "old-pending", Status: models.DeletionJobPending, UpdatedAt: now.Add(-time.Hour)},
+		{ID: "recent-done", Status: models.DeletionJobDone, UpdatedAt: now},
+	} {
+		assert.NoError(t, storage.SaveDeletionJob(ctx, job))
+	}
+
+	count, err := storage.DeleteFinishedDeletionJobs(ctx, now.Add(-time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	for _, id := range []string{"old-done", "old-failed"} {
+		_, err = storage.FindDeletionJob(ctx, id)
+		assert.ErrorIs(t, err, customerrors.ErrDeletionJobNotFound)
+	}
+	for _, id := range []string{"old-pending", "recent-done"} {
+		_, err = storage.FindDeletionJob(ctx, id)
+		assert.NoError(t, err)
+	}
+}
+
+func TestStorageInMemory_SaveClicks(t *testing.T) {
+	storage := NewInMemoryStorage(config.Config{})
+	now := time.Now()
+
+	err := storage.SaveClicks(context.Background(), []models.Click{
+		{ShortURL: "abc", Timestamp: now, Referrer: "https://ya.ru", UserAgent: "curl", ClientIP: "127.0.0.1"},
+		{ShortURL: "abc", Timestamp: now, UserAgent: "curl", ClientIP: "127.0.0.2"},
+		{ShortURL: "def", Timestamp: now, UserAgent: "curl", ClientIP: "127.0.0.1"},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, storage.clicks["abc"], 2)
+	assert.Len(t, storage.clicks["def"], 1)
+}
+
 // Add more test functions for other methods in the StorageInMemory struct
+
+func TestStorageInMemory_Admin(t *testing.T) {
+	storage := NewInMemoryStorage(config.Config{})
+	ctx := context.Background()
+	for _, url := range []models.URL{
+		{ShortURL: "abc", OriginalURL: "https://Sub.Example.com/x", CreatedBy: 1},
+		{ShortURL: "def", OriginalURL: "https://notexample.com", CreatedBy: 1},
+		{ShortURL: "ghi", OriginalURL: "https://example.com", CreatedBy: 2},
+	} {
+		assert.NoError(t, storage.Save(ctx, url))
+	}
+
+	assert.NoError(t, storage.SetURLDisabled(ctx, "abc", true))
+	abc, err := storage.FindByShortURL(ctx, "abc")
+	assert.NoError(t, err)
+	assert.True(t, abc.IsDisabled)
+	assert.ErrorIs(t, storage.SetURLDisabled(ctx, "unknown", true), customerrors.ErrURLNotFound)
+
+	assert.ErrorIs(t, storage.SetURLOwner(ctx, "abc", 3), customerrors.ErrUserNotFound)
+	assert.NoError(t, storage.SetURLOwner(ctx, "abc", 2))
+	urls, err := storage.FindByUser(ctx, 1, models.URLQuery{})
+	assert.NoError(t, err)
+	assert.Len(t, urls, 1)
+	urls, err = storage.FindByUser(ctx, 2, models.URLQuery{SortBy: models.SortByShortURL})
+	assert.NoError(t, err)
+	assert.Len(t, urls, 2)
+
+	deleted, err := storage.DeleteUrlsOfDomain(ctx, "example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"abc", "ghi"}, deleted)
+	deleted, err = storage.DeleteUrlsOfUser(ctx, 2)
+	assert.NoError(t, err)
+	assert.Empty(t, deleted, "urls deleted earlier are not returned")
+	deleted, err = storage.DeleteUrlsOfUser(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"def"}, deleted)
+}
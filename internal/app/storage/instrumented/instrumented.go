@@ -0,0 +1,224 @@
+// Package instrumented предоставляет обертку над хранилищем URL, которая собирает метрики вызовов хранилища —
+// длительность и количество ошибок по типу хранилища и операции — и создает спан трассировки для каждого вызова.
+package instrumented
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	customerrors "github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/errors"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/metrics"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/models"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/storage"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/tracing"
+)
+
+// expectedErrors ошибки, которые описывают результат операции, а не сбой хранилища.
+var expectedErrors = []error{
+	customerrors.ErrURLNotFound,
+	customerrors.ErrShortURLAlreadyExists,
+	customerrors.ErrOriginalURLAlreadyExists,
+	customerrors.ErrDeletionJobNotFound,
+	customerrors.ErrUserNotFound,
+}
+
+// Storage обертка над хранилищем URL, которая собирает метрики и трассировку вызовов.
+type Storage struct {
+	storage.ShortenerStorage
+	// backend тип хранилища, значение метки backend.
+	backend string
+}
+
+// New оборачивает хранилище типа backend сбором метрик и трассировки.
+func New(storage storage.ShortenerStorage, backend string) *Storage {
+	return &Storage{ShortenerStorage: storage, backend: backend}
+}
+
+// SaveClicks сохраняет список событий перехода в хранилище.
+func (s *Storage) SaveClicks(ctx context.Context, clicks []models.Click) (err error) {
+	ctx, span := s.start(ctx, "SaveClicks")
+	defer s.observe(span, "SaveClicks", time.Now(), &err)
+	return s.ShortenerStorage.SaveClicks(ctx, clicks)
+}
+
+// GetClickStats возвращает статистику переходов по сокращенному URL.
+func (s *Storage) GetClickStats(ctx context.Context, shortURL string, top int) (stats models.LinkStats, err error) {
+	ctx, span := s.start(ctx, "GetClickStats")
+	defer s.observe(span, "GetClickStats", time.Now(), &err)
+	return s.ShortenerStorage.GetClickStats(ctx, shortURL, top)
+}
+
+// SaveDeletionJob сохраняет задание на удаление.
+func (s *Storage) SaveDeletionJob(ctx context.Context, job models.DeletionJob) (err error) {
+	ctx, span := s.start(ctx, "SaveDeletionJob")
+	defer s.observe(span, "SaveDeletionJob", time.Now(), &err)
+	return s.ShortenerStorage.SaveDeletionJob(ctx, job)
+}
+
+// FindDeletionJob находит задание на удаление по идентификатору.
+func (s *Storage) FindDeletionJob(ctx context.Context, id string) (job *models.DeletionJob, err error) {
+	ctx, span := s.start(ctx, "FindDeletionJob")
+	defer s.observe(span, "FindDeletionJob", time.Now(), &err)
+	return s.ShortenerStorage.FindDeletionJob(ctx, id)
+}
+
+// FindPendingDeletionJobs возвращает незавершенные задания на удаление, попытка которых наступила.
+func (s *Storage) FindPendingDeletionJobs(ctx context.Context, now time.Time, limit int) (jobs []models.DeletionJob, err error) {
+	ctx, span := s.start(ctx, "FindPendingDeletionJobs")
+	defer s.observe(span, "FindPendingDeletionJobs", time.Now(), &err)
+	return s.ShortenerStorage.FindPendingDeletionJobs(ctx, now, limit)
+}
+
+// CountPendingDeletionJobs возвращает количество незавершенных заданий на удаление.
+func (s *Storage) CountPendingDeletionJobs(ctx context.Context) (count int, err error) {
+	ctx, span := s.start(ctx, "CountPendingDeletionJobs")
+	defer s.observe(span, "CountPendingDeletionJobs", time.Now(), &err)
+	return s.ShortenerStorage.CountPendingDeletionJobs(ctx)
+}
+
+// DeleteFinishedDeletionJobs удаляет устаревшие завершенные задания на удаление.
+func (s *Storage) DeleteFinishedDeletionJobs(ctx context.Context, before time.Time) (count int, err error) {
+	ctx, span := s.start(ctx, "DeleteFinishedDeletionJobs")
+	defer s.observe(span, "DeleteFinishedDeletionJobs", time.Now(), &err)
+	return s.ShortenerStorage.DeleteFinishedDeletionJobs(ctx, before)
+}
+
+// CreateUser регистрирует нового пользователя.
+func (s *Storage) CreateUser(ctx context.Context) (user models.User, err error) {
+	ctx, span := s.start(ctx, "CreateUser")
+	defer s.observe(span, "CreateUser", time.Now(), &err)
+	return s.ShortenerStorage.CreateUser(ctx)
+}
+
+// NextSequence возвращает следующий номер последовательности сокращенных URL.
+func (s *Storage) NextSequence(ctx context.Context) (number int64, err error) {
+	ctx, span := s.start(ctx, "NextSequence")
+	defer s.observe(span, "NextSequence", time.Now(), &err)
+	return s.ShortenerStorage.NextSequence(ctx)
+}
+
+// FindByShortURL находит URL по сокращенному URL.
+func (s *Storage) FindByShortURL(ctx context.Context, shortURL string) (url *models.URL, err error) {
+	ctx, span := s.start(ctx, "FindByShortURL")
+	defer s.observe(span, "FindByShortURL", time.Now(), &err)
+	return s.ShortenerStorage.FindByShortURL(ctx, shortURL)
+}
+
+// Save сохраняет URL в хранилище.
+func (s *Storage) Save(ctx context.Context, url models.URL) (err error) {
+	ctx, span := s.start(ctx, "Save")
+	defer s.observe(span, "Save", time.Now(), &err)
+	return s.ShortenerStorage.Save(ctx, url)
+}
+
+// SaveBatchPartial сохраняет URL из списка независимо друг от друга.
+// Ошибки сохранения отдельных URL ошибками хранилища не считаются.
+func (s *Storage) SaveBatchPartial(ctx context.Context, urls []models.URL) (errs []error, err error) {
+	ctx, span := s.start(ctx, "SaveBatchPartial")
+	defer s.observe(span, "SaveBatchPartial", time.Now(), &err)
+	return s.ShortenerStorage.SaveBatchPartial(ctx, urls)
+}
+
+// Ping проверяет доступность хранилища. Недоступность хранилища считается ошибкой.
+func (s *Storage) Ping(ctx context.Context) bool {
+	var err error
+	ctx, span := s.start(ctx, "Ping")
+	defer s.observe(span, "Ping", time.Now(), &err)
+	ok := s.ShortenerStorage.Ping(ctx)
+	if !ok {
+		err = errors.New("storage is unavailable")
+	}
+	return ok
+}
+
+// FindByUser находит URL, созданные пользователем.
+func (s *Storage) FindByUser(ctx context.Context, userID int, query models.URLQuery) (urls []models.URL, err error) {
+	ctx, span := s.start(ctx, "FindByUser")
+	defer s.observe(span, "FindByUser", time.Now(), &err)
+	return s.ShortenerStorage.FindByUser(ctx, userID, query)
+}
+
+// DeleteUrls помечает URL удаленными.
+func (s *Storage) DeleteUrls(ctx context.Context, urls []models.URLToDelete) (deleted []string, err error) {
+	ctx, span := s.start(ctx, "DeleteUrls")
+	defer s.observe(span, "DeleteUrls", time.Now(), &err)
+	return s.ShortenerStorage.DeleteUrls(ctx, urls)
+}
+
+// RestoreUrls снимает пометку удаления с URL.
+func (s *Storage) RestoreUrls(ctx context.Context, userID int, shortURLs []string, deletedAfter time.Time) (restored []string, err error) {
+	ctx, span := s.start(ctx, "RestoreUrls")
+	defer s.observe(span, "RestoreUrls", time.Now(), &err)
+	return s.ShortenerStorage.RestoreUrls(ctx, userID, shortURLs, deletedAfter)
+}
+
+// SetURLDisabled отключает или включает URL.
+func (s *Storage) SetURLDisabled(ctx context.Context, shortURL string, disabled bool) (err error) {
+	ctx, span := s.start(ctx, "SetURLDisabled")
+	defer s.observe(span, "SetURLDisabled", time.Now(), &err)
+	return s.ShortenerStorage.SetURLDisabled(ctx, shortURL, disabled)
+}
+
+// SetURLOwner передает URL пользователю.
+func (s *Storage) SetURLOwner(ctx context.Context, shortURL string, userID int) (err error) {
+	ctx, span := s.start(ctx, "SetURLOwner")
+	defer s.observe(span, "SetURLOwner", time.Now(), &err)
+	return s.ShortenerStorage.SetURLOwner(ctx, shortURL, userID)
+}
+
+// DeleteUrlsOfUser помечает удаленными все URL пользователя.
+func (s *Storage) DeleteUrlsOfUser(ctx context.Context, userID int) (deleted []string, err error) {
+	ctx, span := s.start(ctx, "DeleteUrlsOfUser")
+	defer s.observe(span, "DeleteUrlsOfUser", time.Now(), &err)
+	return s.ShortenerStorage.DeleteUrlsOfUser(ctx, userID)
+}
+
+// DeleteUrlsOfDomain помечает удаленными все URL на домен.
+func (s *Storage) DeleteUrlsOfDomain(ctx context.Context, domain string) (deleted []string, err error) {
+	ctx, span := s.start(ctx, "DeleteUrlsOfDomain")
+	defer s.observe(span, "DeleteUrlsOfDomain", time.Now(), &err)
+	return s.ShortenerStorage.DeleteUrlsOfDomain(ctx, domain)
+}
+
+// IsShortURLExists проверяет, существует ли сокращенный URL.
+func (s *Storage) IsShortURLExists(ctx context.Context, shortURL string) (ok bool, err error) {
+	ctx, span := s.start(ctx, "IsShortURLExists")
+	defer s.observe(span, "IsShortURLExists", time.Now(), &err)
+	return s.ShortenerStorage.IsShortURLExists(ctx, shortURL)
+}
+
+// GetStats возвращает статистику по хранилищу.
+func (s *Storage) GetStats(ctx context.Context) (stats models.Stats, err error) {
+	ctx, span := s.start(ctx, "GetStats")
+	defer s.observe(span, "GetStats", time.Now(), &err)
+	return s.ShortenerStorage.GetStats(ctx)
+}
+
+// DeleteExpired удаляет истекшие URL.
+func (s *Storage) DeleteExpired(ctx context.Context, now time.Time) (count int, err error) {
+	ctx, span := s.start(ctx, "DeleteExpired")
+	defer s.observe(span, "DeleteExpired", time.Now(), &err)
+	return s.ShortenerStorage.DeleteExpired(ctx, now)
+}
+
+// start создает спан вызова операции operation хранилища.
+func (s *Storage) start(ctx context.Context, operation string) (context.Context, *tracing.Span) {
+	return tracing.Start(ctx, "storage."+operation, tracing.String("storage.backend", s.backend))
+}
+
+// observe завершает спан операции operation, начатой в start, и записывает ее длительность и ошибку, если это сбой хранилища.
+func (s *Storage) observe(span *tracing.Span, operation string, start time.Time, err *error) {
+	defer span.End()
+	metrics.StorageOperationDuration.With(s.backend, operation).Observe(time.Since(start).Seconds())
+	if *err == nil {
+		return
+	}
+	for _, expected := range expectedErrors {
+		if errors.Is(*err, expected) {
+			return
+		}
+	}
+	span.RecordError(*err)
+	metrics.StorageOperationErrors.With(s.backend, operation).Inc()
+}
@@ -0,0 +1,31 @@
+package instrumented
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/config"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/metrics"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/models"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/storage/inmemory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorageMetrics(t *testing.T) {
+	ctx := context.Background()
+	storage := New(inmemory.NewInMemoryStorage(config.Config{}), "test")
+	require.NoError(t, storage.Save(ctx, models.URL{ShortURL: "abc", OriginalURL: "https://example.com"}))
+
+	_, err := storage.FindByShortURL(ctx, "abc")
+	require.NoError(t, err)
+	_, err = storage.FindByShortURL(ctx, "missing")
+	require.Error(t, err)
+	err = storage.Save(ctx, models.URL{ShortURL: "abc", OriginalURL: "https://example.org"})
+	require.Error(t, err)
+
+	assert.Equal(t, uint64(2), metrics.StorageOperationDuration.With("test", "FindByShortURL").Count())
+	assert.Equal(t, uint64(2), metrics.StorageOperationDuration.With("test", "Save").Count())
+	assert.Zero(t, metrics.StorageOperationErrors.With("test", "FindByShortURL").Value(), "not found url is not a storage failure")
+	assert.Zero(t, metrics.StorageOperationErrors.With("test", "Save").Value(), "taken short url is not a storage failure")
+}
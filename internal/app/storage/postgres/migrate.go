@@ -0,0 +1,209 @@
+package postgres
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/logger"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// migrationLockID ключ advisory lock, под которым выполняются миграции,
+// чтобы несколько экземпляров сервиса не изменяли схему одновременно.
+const migrationLockID = 7_353_117_001
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Migration представляет версионированную миграцию схемы базы данных.
+type Migration struct {
+	Version int    // Version номер версии схемы после применения миграции.
+	Name    string // Name название миграции.
+	up      string
+	down    string
+}
+
+// MigrationStatus представляет состояние миграции в базе данных.
+type MigrationStatus struct {
+	Migration
+	AppliedAt time.Time // AppliedAt время применения миграции, нулевое значение — миграция не применена.
+}
+
+// loadMigrations читает встроенные миграции из файлов вида <версия>_<название>.up.sql и <версия>_<название>.down.sql.
+// Миграции возвращаются в порядке возрастания версий.
+func loadMigrations(files fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(files, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	migrations := make(map[int]*Migration)
+	for _, entry := range entries {
+		base, direction, ok := strings.Cut(strings.TrimSuffix(entry.Name(), ".sql"), ".")
+		if !ok || (direction != "up" && direction != "down") {
+			return nil, fmt.Errorf("migration %s: file name must end with .up.sql or .down.sql", entry.Name())
+		}
+		versionPart, name, ok := strings.Cut(base, "_")
+		version, err := strconv.Atoi(versionPart)
+		if !ok || err != nil || version <= 0 {
+			return nil, fmt.Errorf("migration %s: file name must start with a positive version", entry.Name())
+		}
+		content, err := fs.ReadFile(files, path.Join("migrations", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		migration, ok := migrations[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: name}
+			migrations[version] = migration
+		}
+		if migration.Name != name {
+			return nil, fmt.Errorf("migration %d: names %s and %s differ", version, migration.Name, name)
+		}
+		if direction == "up" {
+			migration.up = string(content)
+		} else {
+			migration.down = string(content)
+		}
+	}
+	result := make([]Migration, 0, len(migrations))
+	for _, migration := range migrations {
+		if migration.up == "" || migration.down == "" {
+			return nil, fmt.Errorf("migration %d: both up and down files are required", migration.Version)
+		}
+		result = append(result, *migration)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Version < result[j].Version
+	})
+	return result, nil
+}
+
+// Migrator применяет и откатывает встроенные миграции схемы базы данных PostgreSQL.
+// Примененные версии хранятся в таблице schema_version.
+type Migrator struct {
+	pool       *pgxpool.Pool
+	migrations []Migration
+}
+
+// NewMigrator создает новый экземпляр Migrator для базы данных пула pool.
+func NewMigrator(pool *pgxpool.Pool) (*Migrator, error) {
+	migrations, err := loadMigrations(migrationFiles)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{pool: pool, migrations: migrations}, nil
+}
+
+// Up применяет все непримененные миграции.
+func (migrator *Migrator) Up(ctx context.Context) error {
+	return migrator.withLock(ctx, func(conn *pgxpool.Conn, applied map[int]time.Time) error {
+		for _, migration := range migrator.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+			query := `insert into schema_version(version, name) values($1, $2)`
+			if err := migrator.apply(ctx, conn, migration.up, query, migration.Version, migration.Name); err != nil {
+				return fmt.Errorf("migration %d_%s up: %w", migration.Version, migration.Name, err)
+			}
+			logger.Logger.Info("migration is applied", "version", migration.Version, "name", migration.Name)
+		}
+		return nil
+	})
+}
+
+// Down откатывает steps последних примененных миграций.
+func (migrator *Migrator) Down(ctx context.Context, steps int) error {
+	if steps <= 0 {
+		return errors.New("number of migrations to roll back must be positive")
+	}
+	return migrator.withLock(ctx, func(conn *pgxpool.Conn, applied map[int]time.Time) error {
+		for i := len(migrator.migrations) - 1; i >= 0 && steps > 0; i-- {
+			migration := migrator.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+			query := `delete from schema_version where version = $1`
+			if err := migrator.apply(ctx, conn, migration.down, query, migration.Version); err != nil {
+				return fmt.Errorf("migration %d_%s down: %w", migration.Version, migration.Name, err)
+			}
+			logger.Logger.Info("migration is rolled back", "version", migration.Version, "name", migration.Name)
+			steps--
+		}
+		return nil
+	})
+}
+
+// Status возвращает состояние всех встроенных миграций.
+func (migrator *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	var statuses []MigrationStatus
+	err := migrator.withLock(ctx, func(conn *pgxpool.Conn, applied map[int]time.Time) error {
+		for _, migration := range migrator.migrations {
+			statuses = append(statuses, MigrationStatus{Migration: migration, AppliedAt: applied[migration.Version]})
+		}
+		return nil
+	})
+	return statuses, err
+}
+
+// withLock выполняет fn на отдельном соединении под advisory lock,
+// передавая время применения уже примененных миграций по версиям.
+func (migrator *Migrator) withLock(ctx context.Context, fn func(conn *pgxpool.Conn, applied map[int]time.Time) error) (err error) {
+	conn, err := migrator.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+	if _, err := conn.Exec(ctx, `select pg_advisory_lock($1)`, migrationLockID); err != nil {
+		return err
+	}
+	defer func() {
+		// Блокировка снимается и при отмене ctx, иначе она останется за соединением, вернувшимся в пул.
+		_, unlockErr := conn.Exec(context.WithoutCancel(ctx), `select pg_advisory_unlock($1)`, migrationLockID)
+		err = errors.Join(err, unlockErr)
+	}()
+	query := `
+		create table if not exists schema_version (
+			version int primary key,
+			name varchar not null,
+			applied_at timestamptz not null default now()
+		)
+	`
+	if _, err := conn.Exec(ctx, query); err != nil {
+		return err
+	}
+	rows, err := conn.Query(ctx, `select version, applied_at from schema_version`)
+	if err != nil {
+		return err
+	}
+	applied := make(map[int]time.Time)
+	var version int
+	var appliedAt time.Time
+	_, err = pgx.ForEachRow(rows, []any{&version, &appliedAt}, func() error {
+		applied[version] = appliedAt
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return fn(conn, applied)
+}
+
+// apply выполняет миграцию и изменение schema_version в одной транзакции.
+func (migrator *Migrator) apply(ctx context.Context, conn *pgxpool.Conn, migration string, versionQuery string, args ...any) error {
+	return pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, migration); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, versionQuery, args...)
+		return err
+	})
+}
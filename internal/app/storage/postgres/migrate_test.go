@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadEmbeddedMigrations(t *testing.T) {
+	migrations, err := loadMigrations(migrationFiles)
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+	for i, migration := range migrations {
+		assert.Equal(t, i+1, migration.Version, migration.Name)
+		assert.NotEmpty(t, migration.up, migration.Name)
+		assert.NotEmpty(t, migration.down, migration.Name)
+	}
+}
+
+func TestLoadMigrations(t *testing.T) {
+	file := &fstest.MapFile{Data: []byte("select 1;")}
+	tests := []struct {
+		name    string
+		files   fstest.MapFS
+		want    []int
+		wantErr bool
+	}{
+		{
+			name: "ordered by version",
+			files: fstest.MapFS{
+				"migrations/0010_b.up.sql":   file,
+				"migrations/0010_b.down.sql": file,
+				"migrations/0002_a.up.sql":   file,
+				"migrations/0002_a.down.sql": file,
+			},
+			want: []int{2, 10},
+		},
+		{
+			name:    "missing down",
+			files:   fstest.MapFS{"migrations/0001_a.up.sql": file},
+			wantErr: true,
+		},
+		{
+			name:    "invalid version",
+			files:   fstest.MapFS{"migrations/first_a.up.sql": file, "migrations/first_a.down.sql": file},
+			wantErr: true,
+		},
+		{
+			name:    "invalid direction",
+			files:   fstest.MapFS{"migrations/0001_a.sql": file},
+			wantErr: true,
+		},
+		{
+			name:    "different names",
+			files:   fstest.MapFS{"migrations/0001_a.up.sql": file, "migrations/0001_b.down.sql": file},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migrations, err := loadMigrations(tt.files)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			var versions []int
+			for _, migration := range migrations {
+				versions = append(versions, migration.Version)
+			}
+			assert.Equal(t, tt.want, versions)
+		})
+	}
+}
@@ -0,0 +1,12 @@
+-- Схема создается с if not exists: базы, созданные до появления миграций, переходят на версию 1 без ошибок.
+create table if not exists urls (
+	id serial primary key,
+	created_by int,
+	created_ts timestamp default now(),
+	short_url varchar unique not null,
+	original_url varchar unique not null,
+	is_deleted bool default false
+);
+alter table urls add column if not exists expires_at timestamptz;
+alter table urls add column if not exists deleted_at timestamptz;
+create index if not exists urls_created_by_ts_idx on urls(created_by, created_ts, short_url);
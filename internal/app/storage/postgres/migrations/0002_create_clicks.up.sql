@@ -0,0 +1,9 @@
+create table if not exists clicks (
+	id bigserial primary key,
+	short_url varchar not null,
+	ts timestamptz not null,
+	referrer varchar not null default '',
+	user_agent varchar not null default '',
+	client_ip varchar not null default ''
+);
+create index if not exists clicks_short_url_ts_idx on clicks(short_url, ts);
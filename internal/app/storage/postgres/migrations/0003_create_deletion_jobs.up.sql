@@ -0,0 +1,12 @@
+create table if not exists deletion_jobs (
+	id varchar primary key,
+	user_id int not null,
+	status varchar not null,
+	urls jsonb not null,
+	attempts int not null default 0,
+	last_error varchar not null default '',
+	created_at timestamptz not null,
+	updated_at timestamptz not null,
+	next_attempt_at timestamptz not null
+);
+create index if not exists deletion_jobs_status_next_attempt_idx on deletion_jobs(status, next_attempt_at);
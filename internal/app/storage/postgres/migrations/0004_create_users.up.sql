@@ -0,0 +1,12 @@
+create table users (
+	id serial primary key,
+	created_at timestamptz not null default now()
+);
+-- Пользователи, получившие идентификатор до появления таблицы, переносятся по созданным ими URL,
+-- а последовательность сдвигается за них, чтобы их идентификаторы не были выданы повторно.
+insert into users(id, created_at)
+select created_by, coalesce(min(created_ts), now())
+from urls
+where created_by > 0
+group by created_by;
+select setval(pg_get_serial_sequence('users', 'id'), coalesce(max(id), 0) + 1, false) from users;
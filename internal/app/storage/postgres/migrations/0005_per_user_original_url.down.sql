@@ -0,0 +1,2 @@
+drop index if exists urls_created_by_original_url_idx;
+alter table urls add constraint urls_original_url_key unique (original_url);
@@ -0,0 +1,3 @@
+-- Один исходный URL может быть сокращен несколько раз, дедупликация выполняется по запросу в пределах пользователя.
+alter table urls drop constraint if exists urls_original_url_key;
+create index if not exists urls_created_by_original_url_idx on urls(created_by, original_url);
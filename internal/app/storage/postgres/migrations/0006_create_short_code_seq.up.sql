@@ -0,0 +1,2 @@
+-- Последовательность номеров сокращенных URL для стратегий генерации sequence и hashids.
+create sequence short_code_seq;
@@ -0,0 +1,2 @@
+-- Отключенные администратором URL не открываются и не участвуют в дедупликации.
+alter table urls add column if not exists is_disabled bool not null default false;
@@ -2,29 +2,42 @@ package postgres
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
-	"net/http"
-	"sync/atomic"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/config"
 	customerrors "github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/errors"
 	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/models"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+const (
+	uniqueViolationCode      = "23505"
+	shortURLUniqueConstraint = "urls_short_url_key"
+)
+
 // StoragePostgres представляет хранилище URL-ов в базе данных PostgreSQL.
 type StoragePostgres struct {
 	databaseURL string
 	pool        *pgxpool.Pool
-	userIDSeq   atomic.Int64
 	config      config.Config
 }
 
 // NewPostgresStorage создает новый экземпляр хранилища URL-ов в базе данных PostgreSQL.
+// Запросы к базе данных трассируются, если трассировка включена.
 func NewPostgresStorage(config config.Config) (*StoragePostgres, error) {
-	pool, err := pgxpool.New(context.TODO(), config.DatabaseURL)
+	poolConfig, err := pgxpool.ParseConfig(config.DatabaseURL)
+	if err != nil {
+		return nil, err
+	}
+	poolConfig.ConnConfig.Tracer = queryTracer{}
+	pool, err := pgxpool.NewWithConfig(context.TODO(), poolConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -33,131 +46,168 @@ func NewPostgresStorage(config config.Config) (*StoragePostgres, error) {
 		pool:        pool,
 		config:      config,
 	}
-	if err := storage.createTables(); err != nil {
-		return nil, err
-	}
-	if err := storage.setUserIDSeq(); err != nil {
+	if err := storage.migrate(); err != nil {
+		pool.Close()
 		return nil, err
 	}
-	return storage, err
+	return storage, nil
 }
 
-func (storage *StoragePostgres) createTables() error {
-	query := `
-		create table if not exists urls (
-			id serial primary key,
-			created_by int,
-			created_ts timestamp default now(),
-			short_url varchar unique not null,
-			original_url varchar unique not null,
-			is_deleted bool default false
-		);
-	`
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(30*time.Second))
-	defer cancel()
-	_, err := storage.pool.Exec(ctx, query)
+// migrate применяет к базе данных непримененные миграции схемы.
+func (storage *StoragePostgres) migrate() error {
+	migrator, err := NewMigrator(storage.pool)
 	if err != nil {
 		return err
 	}
-	return nil
-}
-
-func (storage *StoragePostgres) setUserIDSeq() error {
-	query := `
-		select coalesce(max(created_by), 0) + 1 from urls
-	`
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(30*time.Second))
 	defer cancel()
-	var userID int
-	err := storage.pool.QueryRow(ctx, query).Scan(&userID)
-	storage.userIDSeq.Store(int64(userID))
-	return err
+	return migrator.Up(ctx)
 }
 
 // Save сохраняет URL в хранилище.
 func (storage *StoragePostgres) Save(ctx context.Context, url models.URL) error {
-	query := getInsertQuery()
 	tr, err := storage.pool.Begin(ctx)
 	if err != nil {
 		return customerrors.NewCustomErrorInternal(err)
 	}
-	var shortURL string
-	err = tr.QueryRow(ctx, query, url.ShortURL, url.OriginalURL, url.CreatedBy).Scan(&shortURL)
-	if shortURL != "" {
-		tr.Rollback(ctx)
-		err := customerrors.NewCustomError(errors.New("original url already exists"))
-		err.Status = http.StatusConflict
-		err.ShortURL = shortURL
-		return err
+	defer tr.Rollback(ctx)
+	if url.Dedupe {
+		if _, err := tr.Exec(ctx, lockOriginalURLQuery, url.CreatedBy, url.OriginalURL); err != nil {
+			return customerrors.NewCustomErrorInternal(err)
+		}
 	}
+	var duplicate string
+	var inserted bool
+	err = tr.QueryRow(ctx, insertQuery, url.ShortURL, url.OriginalURL, url.CreatedBy, nullTime(url.ExpiresAt), url.Dedupe).Scan(&duplicate, &inserted)
 	if err != nil {
-		tr.Rollback(ctx)
+		return wrapInsertError(err)
+	}
+	if err := insertResultError(duplicate, inserted); err != nil {
+		return err
+	}
+	if err := tr.Commit(ctx); err != nil {
 		return customerrors.NewCustomErrorInternal(err)
 	}
-	tr.Commit(ctx)
 	return nil
 }
 
-// SaveBatch сохраняет список URL в хранилище.
-func (storage *StoragePostgres) SaveBatch(ctx context.Context, urls []models.URL) error {
-	query := getInsertQuery()
+func wrapInsertError(err error) error {
+	var customErr *customerrors.CustomError
+	if errors.As(err, &customErr) {
+		return customErr
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode && pgErr.ConstraintName == shortURLUniqueConstraint {
+		return customerrors.NewCustomErrorConflict(customerrors.ErrShortURLAlreadyExists)
+	}
+	return customerrors.NewCustomErrorInternal(err)
+}
+
+// SaveBatchPartial сохраняет URL из списка независимо друг от друга одним пакетом запросов в транзакции
+// и возвращает ошибки сохранения по индексам URL. Конфликты не прерывают транзакцию.
+func (storage *StoragePostgres) SaveBatchPartial(ctx context.Context, urls []models.URL) ([]error, error) {
+	errs := make([]error, len(urls))
 	batch := &pgx.Batch{}
-	var queueQuery *pgx.QueuedQuery
-	for _, el := range urls {
-		queueQuery = batch.Queue(query, el.ShortURL, el.OriginalURL, el.CreatedBy)
+	for i, url := range urls {
+		if url.Dedupe {
+			batch.Queue(lockOriginalURLQuery, url.CreatedBy, url.OriginalURL)
+		}
+		batch.Queue(insertQuery, url.ShortURL, url.OriginalURL, url.CreatedBy, nullTime(url.ExpiresAt), url.Dedupe).QueryRow(func(row pgx.Row) error {
+			var duplicate string
+			var inserted bool
+			if err := row.Scan(&duplicate, &inserted); err != nil {
+				return err
+			}
+			errs[i] = insertResultError(duplicate, inserted)
+			return nil
+		})
 	}
 	tr, err := storage.pool.Begin(ctx)
 	if err != nil {
-		tr.Rollback(ctx)
-		return customerrors.NewCustomErrorInternal(err)
+		return nil, customerrors.NewCustomErrorInternal(err)
 	}
-	queueQuery.QueryRow(func(row pgx.Row) error {
-		var shortURL string
-		row.Scan(&shortURL)
-		if shortURL != "" {
-			tr.Rollback(ctx)
-			err := customerrors.NewCustomError(errors.New("original url already exists"))
-			err.Status = http.StatusConflict
-			err.ShortURL = shortURL
-			return err
-		}
-		return nil
-	})
-	res := tr.SendBatch(ctx, batch)
-	err = res.Close()
-	if err != nil {
-		tr.Rollback(ctx)
-		return customerrors.NewCustomErrorInternal(err)
+	defer tr.Rollback(ctx)
+	if err := tr.SendBatch(ctx, batch).Close(); err != nil {
+		return nil, wrapInsertError(err)
+	}
+	if err := tr.Commit(ctx); err != nil {
+		return nil, customerrors.NewCustomErrorInternal(err)
+	}
+	return errs, nil
+}
+
+// insertResultError возвращает ошибку сохранения URL по результату insertQuery.
+func insertResultError(duplicate string, inserted bool) error {
+	if duplicate != "" {
+		return customerrors.NewCustomErrorOriginalURLConflict(duplicate)
+	}
+	if !inserted {
+		return customerrors.NewCustomErrorConflict(customerrors.ErrShortURLAlreadyExists)
 	}
-	tr.Commit(ctx)
 	return nil
 }
 
-func getInsertQuery() string {
-	return `
-	with new_id as (
-		insert into urls(short_url, original_url, created_by) values($1, $2, $3)
-		on conflict(original_url) do nothing
-		returning id 
-	) select
-		case when (select id from new_id) is null
-			then (select short_url from urls where original_url = $2)
-			else ''
-		end as shortURL
-	`
+// lockOriginalURLQuery блокирует до конца транзакции сохранение URL пользователя $1 с исходным URL $2,
+// чтобы параллельные запросы с дедупликацией не создали две ссылки на один URL.
+const lockOriginalURLQuery = `select pg_advisory_xact_lock(hashtextextended($1::text || ':' || $2::text, 0))`
+
+// insertQuery сохраняет URL и возвращает существующий дубликат и признак сохранения.
+// Если требуется дедупликация ($5) и у пользователя уже есть действующий URL с тем же исходным URL,
+// новый URL не сохраняется, а запрос возвращает существующий сокращенный URL.
+// Занятый сокращенный URL не вызывает ошибку, которая прервала бы транзакцию: URL просто не сохраняется.
+const insertQuery = `
+	with duplicate as (
+		select short_url from urls
+		where $5::bool and created_by = $3::int and original_url = $2::varchar
+			and not is_deleted and not is_disabled and (expires_at is null or expires_at > now())
+		limit 1
+	), inserted as (
+		insert into urls(short_url, original_url, created_by, expires_at)
+		select $1::varchar, $2::varchar, $3::int, $4::timestamptz
+		where not exists (select 1 from duplicate)
+		on conflict (short_url) do nothing
+		returning id
+	) select coalesce((select short_url from duplicate), ''), exists(select 1 from inserted)
+`
+
+func nullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
 }
 
 // FindByShortURL находит оригинальный URL по сокращенному URL.
 func (storage *StoragePostgres) FindByShortURL(ctx context.Context, shortURL string) (*models.URL, error) {
-	query := "select id, short_url, original_url, is_deleted from urls where short_url = $1"
+	query := `
+	select id, short_url, original_url, coalesce(created_by, 0), created_ts, is_deleted, deleted_at, expires_at, is_disabled
+	from urls
+	where short_url = $1`
 	var url models.URL
-	err := storage.pool.QueryRow(ctx, query, shortURL).Scan(&url.ID, &url.ShortURL, &url.OriginalURL, &url.IsDeleted)
+	var deletedAt, expiresAt *time.Time
+	err := storage.pool.QueryRow(ctx, query, shortURL).Scan(
+		&url.ID,
+		&url.ShortURL,
+		&url.OriginalURL,
+		&url.CreatedBy,
+		&url.CreatedTS,
+		&url.IsDeleted,
+		&deletedAt,
+		&expiresAt,
+		&url.IsDisabled,
+	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, customerrors.NewCustomErrorBadRequest(errors.New("original url isn't found"))
+			return nil, customerrors.NewCustomErrorBadRequest(customerrors.ErrURLNotFound)
 		}
 		return nil, customerrors.NewCustomErrorInternal(err)
 	}
+	if deletedAt != nil {
+		url.DeletedAt = *deletedAt
+	}
+	if expiresAt != nil {
+		url.ExpiresAt = *expiresAt
+	}
 	return &url, nil
 }
 
@@ -166,50 +216,273 @@ func (storage *StoragePostgres) Ping(ctx context.Context) bool {
 	return storage.pool.Ping(ctx) == nil
 }
 
-// GetUserID возвращает идентификатор пользователя из контекста.
-func (storage *StoragePostgres) GetUserID(context.Context) int {
-	userID := storage.userIDSeq.Load()
-	storage.userIDSeq.Add(1)
-	return int(userID)
+// CreateUser регистрирует нового пользователя.
+// Идентификатор выдается последовательностью базы данных, поэтому он уникален для всех экземпляров сервиса.
+func (storage *StoragePostgres) CreateUser(ctx context.Context) (models.User, error) {
+	query := `
+		insert into users default values
+		returning id, created_at
+	`
+	var user models.User
+	if err := storage.pool.QueryRow(ctx, query).Scan(&user.ID, &user.CreatedAt); err != nil {
+		return models.User{}, customerrors.NewCustomErrorInternal(err)
+	}
+	return user, nil
 }
 
-// FindByUser находит URL, созданные конкретным пользователем.
-func (storage *StoragePostgres) FindByUser(ctx context.Context, userID int) ([]models.URL, error) {
-	query := "select id, short_url, original_url from urls where created_by = $1"
-	rows, err := storage.pool.Query(ctx, query, userID)
+// NextSequence возвращает следующий номер последовательности сокращенных URL.
+func (storage *StoragePostgres) NextSequence(ctx context.Context) (int64, error) {
+	var sequence int64
+	if err := storage.pool.QueryRow(ctx, "select nextval('short_code_seq')").Scan(&sequence); err != nil {
+		return 0, customerrors.NewCustomErrorInternal(err)
+	}
+	return sequence, nil
+}
+
+// sortColumns сопоставляет поля сортировки с колонками таблицы urls.
+var sortColumns = map[string]string{
+	models.SortByCreatedTS:   "created_ts",
+	models.SortByShortURL:    "short_url",
+	models.SortByOriginalURL: "original_url",
+}
+
+// FindByUser находит URL, созданные конкретным пользователем, с учетом фильтров, сортировки и курсора запроса.
+// Для постраничной выборки используется keyset-пагинация по паре (поле сортировки, short_url).
+func (storage *StoragePostgres) FindByUser(ctx context.Context, userID int, query models.URLQuery) ([]models.URL, error) {
+	sql, args, err := getFindByUserQuery(userID, query)
+	if err != nil {
+		return nil, customerrors.NewCustomErrorBadRequest(err)
+	}
+	rows, err := storage.pool.Query(ctx, sql, args...)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, customerrors.NewCustomErrorBadRequest(errors.New("original url isn't found"))
-		}
 		return nil, customerrors.NewCustomErrorInternal(err)
 	}
+	defer rows.Close()
 	urls := make([]models.URL, 0)
 	for rows.Next() {
 		url := models.URL{}
-		err := rows.Scan(&url.ID, &url.ShortURL, &url.OriginalURL)
+		var deletedAt, expiresAt *time.Time
+		err := rows.Scan(&url.ID, &url.ShortURL, &url.OriginalURL, &url.CreatedBy, &url.CreatedTS, &url.IsDeleted, &deletedAt, &expiresAt, &url.IsDisabled)
 		if err != nil {
 			return nil, customerrors.NewCustomErrorInternal(err)
 		}
+		if deletedAt != nil {
+			url.DeletedAt = *deletedAt
+		}
+		if expiresAt != nil {
+			url.ExpiresAt = *expiresAt
+		}
 		urls = append(urls, url)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, customerrors.NewCustomErrorInternal(err)
+	}
 	return urls, nil
 }
 
-// DeleteUrls удаляет список URL из хранилища.
-func (storage *StoragePostgres) DeleteUrls(ctx context.Context, urls []models.URLToDelete) error {
-	query := "update urls set is_deleted = true where short_url = $1 and created_by = $2"
+func getFindByUserQuery(userID int, query models.URLQuery) (string, []any, error) {
+	column, ok := sortColumns[query.SortBy]
+	if !ok {
+		column = sortColumns[models.SortByCreatedTS]
+	}
+	args := []any{userID}
+	conditions := []string{"created_by = $1"}
+	addCondition := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if !query.CreatedFrom.IsZero() {
+		addCondition("created_ts >= $%d", query.CreatedFrom.UTC())
+	}
+	if !query.CreatedTo.IsZero() {
+		addCondition("created_ts < $%d", query.CreatedTo.UTC())
+	}
+	if query.OriginalURLContains != "" {
+		addCondition("strpos(original_url, $%d) > 0", query.OriginalURLContains)
+	}
+	if query.Deleted != nil {
+		addCondition("is_deleted = $%d", *query.Deleted)
+	}
+	direction, operator := "asc", ">"
+	if query.SortDesc {
+		direction, operator = "desc", "<"
+	}
+	if query.Cursor != nil {
+		var value any = query.Cursor.Value
+		if column == sortColumns[models.SortByCreatedTS] {
+			ts, err := time.Parse(time.RFC3339Nano, query.Cursor.Value)
+			if err != nil {
+				return "", nil, err
+			}
+			value = ts.UTC()
+		}
+		args = append(args, value, query.Cursor.ShortURL)
+		conditions = append(conditions, fmt.Sprintf("(%s, short_url) %s ($%d, $%d)", column, operator, len(args)-1, len(args)))
+	}
+	sql := fmt.Sprintf(`
+	select id, short_url, original_url, coalesce(created_by, 0), created_ts, is_deleted, deleted_at, expires_at, is_disabled
+	from urls
+	where %s
+	order by %s %s, short_url %s`, strings.Join(conditions, " and "), column, direction, direction)
+	if query.Limit > 0 {
+		args = append(args, query.Limit)
+		sql += fmt.Sprintf("\n\tlimit $%d", len(args))
+	}
+	return sql, args, nil
+}
+
+// DeleteUrls помечает удаленными URL из списка, созданные указанными пользователями.
+func (storage *StoragePostgres) DeleteUrls(ctx context.Context, urls []models.URLToDelete) ([]string, error) {
+	query := `
+	update urls set is_deleted = true, deleted_at = case when is_deleted then deleted_at else now() end
+	where short_url = $1 and created_by = $2
+	returning short_url`
 	batch := &pgx.Batch{}
+	deleted := make([]string, 0, len(urls))
 	for _, url := range urls {
-		batch.Queue(query, url.ShortURL, url.UserID)
+		batch.Queue(query, url.ShortURL, url.UserID).QueryRow(func(row pgx.Row) error {
+			var shortURL string
+			err := row.Scan(&shortURL)
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			deleted = append(deleted, shortURL)
+			return nil
+		})
 	}
 	res := storage.pool.SendBatch(ctx, batch)
 	err := res.Close()
+	if err != nil {
+		return nil, customerrors.NewCustomErrorInternal(err)
+	}
+	return deleted, nil
+}
+
+// RestoreUrls снимает пометку удаления с URL из списка, созданных пользователем и удаленных не раньше deletedAfter.
+func (storage *StoragePostgres) RestoreUrls(ctx context.Context, userID int, shortURLs []string, deletedAfter time.Time) ([]string, error) {
+	query := `
+	update urls set is_deleted = false, deleted_at = null
+	where short_url = any($1) and created_by = $2 and is_deleted and deleted_at >= $3
+	returning short_url`
+	rows, err := storage.pool.Query(ctx, query, shortURLs, userID, deletedAfter)
+	if err != nil {
+		return nil, customerrors.NewCustomErrorInternal(err)
+	}
+	restored, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, customerrors.NewCustomErrorInternal(err)
+	}
+	return restored, nil
+}
+
+// SetURLDisabled отключает или включает URL.
+func (storage *StoragePostgres) SetURLDisabled(ctx context.Context, shortURL string, disabled bool) error {
+	tag, err := storage.pool.Exec(ctx, "update urls set is_disabled = $2 where short_url = $1", shortURL, disabled)
 	if err != nil {
 		return customerrors.NewCustomErrorInternal(err)
 	}
+	if tag.RowsAffected() == 0 {
+		return customerrors.NewCustomErrorBadRequest(customerrors.ErrURLNotFound)
+	}
 	return nil
 }
 
+// SetURLOwner передает URL зарегистрированному пользователю userID.
+// URL обновляется, только если пользователь существует, а флаги запроса показывают, чего не хватило.
+func (storage *StoragePostgres) SetURLOwner(ctx context.Context, shortURL string, userID int) error {
+	query := `
+	with updated as (
+		update urls set created_by = $2
+		where short_url = $1 and exists(select 1 from users where id = $2)
+		returning id
+	) select exists(select 1 from urls where short_url = $1), exists(select 1 from users where id = $2)`
+	var urlExists, userExists bool
+	if err := storage.pool.QueryRow(ctx, query, shortURL, userID).Scan(&urlExists, &userExists); err != nil {
+		return customerrors.NewCustomErrorInternal(err)
+	}
+	if !urlExists {
+		return customerrors.NewCustomErrorBadRequest(customerrors.ErrURLNotFound)
+	}
+	if !userExists {
+		return customerrors.NewCustomErrorBadRequest(customerrors.ErrUserNotFound)
+	}
+	return nil
+}
+
+// DeleteUrlsOfUser помечает удаленными все URL пользователя userID.
+func (storage *StoragePostgres) DeleteUrlsOfUser(ctx context.Context, userID int) ([]string, error) {
+	query := `
+	update urls set is_deleted = true, deleted_at = now()
+	where created_by = $1 and not is_deleted
+	returning short_url`
+	rows, err := storage.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, customerrors.NewCustomErrorInternal(err)
+	}
+	deleted, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, customerrors.NewCustomErrorInternal(err)
+	}
+	slices.Sort(deleted)
+	return deleted, nil
+}
+
+// DeleteUrlsOfDomain помечает удаленными все URL, исходный URL которых ведет на домен domain или его поддомен.
+// Кандидаты выбираются по вхождению домена в исходный URL и блокируются до конца транзакции,
+// а точное совпадение хоста проверяется разбором URL.
+func (storage *StoragePostgres) DeleteUrlsOfDomain(ctx context.Context, domain string) ([]string, error) {
+	tr, err := storage.pool.Begin(ctx)
+	if err != nil {
+		return nil, customerrors.NewCustomErrorInternal(err)
+	}
+	defer tr.Rollback(ctx)
+	query := `
+	select short_url, original_url from urls
+	where not is_deleted and strpos(lower(original_url), $1) > 0
+	for update`
+	rows, err := tr.Query(ctx, query, domain)
+	if err != nil {
+		return nil, customerrors.NewCustomErrorInternal(err)
+	}
+	candidates, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.URL, error) {
+		var url models.URL
+		err := row.Scan(&url.ShortURL, &url.OriginalURL)
+		return url, err
+	})
+	if err != nil {
+		return nil, customerrors.NewCustomErrorInternal(err)
+	}
+	shortURLs := make([]string, 0, len(candidates))
+	for _, url := range candidates {
+		if url.HasDomain(domain) {
+			shortURLs = append(shortURLs, url.ShortURL)
+		}
+	}
+	if len(shortURLs) == 0 {
+		return shortURLs, nil
+	}
+	query = `
+	update urls set is_deleted = true, deleted_at = now()
+	where short_url = any($1)
+	returning short_url`
+	rows, err = tr.Query(ctx, query, shortURLs)
+	if err != nil {
+		return nil, customerrors.NewCustomErrorInternal(err)
+	}
+	deleted, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, customerrors.NewCustomErrorInternal(err)
+	}
+	if err := tr.Commit(ctx); err != nil {
+		return nil, customerrors.NewCustomErrorInternal(err)
+	}
+	slices.Sort(deleted)
+	return deleted, nil
+}
+
 // IsShortURLExists проверяет, существует ли указанный сокращенный URL в хранилище.
 func (storage *StoragePostgres) IsShortURLExists(ctx context.Context, shortURL string) (bool, error) {
 	query := "select exists(select * from urls where short_url = $1)"
@@ -224,11 +497,9 @@ func (storage *StoragePostgres) IsShortURLExists(ctx context.Context, shortURL s
 // GetStats возвращает статистику по хранилищу.
 func (storage *StoragePostgres) GetStats(ctx context.Context) (models.Stats, error) {
 	query := `
-	select 
-		count(short_url) as "urls",
-		count(created_by) as "users"
-	from urls
-	where is_deleted = false`
+	select
+		(select count(*) from urls where is_deleted = false) as "urls",
+		(select count(*) from users) as "users"`
 	var stats models.Stats
 	err := storage.pool.QueryRow(ctx, query).Scan(&stats.URLS, &stats.Users)
 	if err != nil {
@@ -236,3 +507,191 @@ func (storage *StoragePostgres) GetStats(ctx context.Context) (models.Stats, err
 	}
 	return stats, nil
 }
+
+// DeleteExpired удаляет из хранилища URL, срок действия которых истек к моменту now.
+func (storage *StoragePostgres) DeleteExpired(ctx context.Context, now time.Time) (int, error) {
+	query := "delete from urls where expires_at <= $1"
+	tag, err := storage.pool.Exec(ctx, query, now)
+	if err != nil {
+		return 0, customerrors.NewCustomErrorInternal(err)
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// Close закрывает пул соединений с базой данных.
+func (storage *StoragePostgres) Close() error {
+	storage.pool.Close()
+	return nil
+}
+
+// SaveClicks сохраняет список событий перехода в хранилище.
+func (storage *StoragePostgres) SaveClicks(ctx context.Context, clicks []models.Click) error {
+	rows := make([][]any, len(clicks))
+	for i, click := range clicks {
+		rows[i] = []any{click.ShortURL, click.Timestamp, click.Referrer, click.UserAgent, click.ClientIP}
+	}
+	_, err := storage.pool.CopyFrom(
+		ctx,
+		pgx.Identifier{"clicks"},
+		[]string{"short_url", "ts", "referrer", "user_agent", "client_ip"},
+		pgx.CopyFromRows(rows),
+	)
+	if err != nil {
+		return customerrors.NewCustomErrorInternal(err)
+	}
+	return nil
+}
+
+// GetClickStats возвращает статистику переходов по сокращенному URL.
+func (storage *StoragePostgres) GetClickStats(ctx context.Context, shortURL string, top int) (models.LinkStats, error) {
+	stats := models.LinkStats{ShortURL: shortURL}
+	query := "select count(*), count(distinct client_ip) from clicks where short_url = $1"
+	err := storage.pool.QueryRow(ctx, query, shortURL).Scan(&stats.TotalClicks, &stats.UniqueVisitors)
+	if err != nil {
+		return stats, customerrors.NewCustomErrorInternal(err)
+	}
+	query = `
+	select to_char(ts at time zone 'UTC', 'YYYY-MM-DD') as day, count(*)
+	from clicks
+	where short_url = $1
+	group by day
+	order by day`
+	rows, err := storage.pool.Query(ctx, query, shortURL)
+	if err != nil {
+		return stats, customerrors.NewCustomErrorInternal(err)
+	}
+	stats.ClicksPerDay, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.ClicksPerDay, error) {
+		var day models.ClicksPerDay
+		err := row.Scan(&day.Date, &day.Clicks)
+		return day, err
+	})
+	if err != nil {
+		return stats, customerrors.NewCustomErrorInternal(err)
+	}
+	stats.TopReferrers, err = storage.getTopClickValues(ctx, "referrer", shortURL, top)
+	if err != nil {
+		return stats, err
+	}
+	stats.TopUserAgents, err = storage.getTopClickValues(ctx, "user_agent", shortURL, top)
+	if err != nil {
+		return stats, err
+	}
+	return stats, nil
+}
+
+// getTopClickValues возвращает самые частые значения колонки column таблицы clicks.
+// Значение column подставляется в запрос напрямую, поэтому оно не должно приходить от пользователя.
+func (storage *StoragePostgres) getTopClickValues(ctx context.Context, column string, shortURL string, top int) ([]models.CountItem, error) {
+	query := `
+	select ` + column + `, count(*) as cnt
+	from clicks
+	where short_url = $1 and ` + column + ` <> ''
+	group by ` + column + `
+	order by cnt desc, ` + column + `
+	limit $2`
+	rows, err := storage.pool.Query(ctx, query, shortURL, top)
+	if err != nil {
+		return nil, customerrors.NewCustomErrorInternal(err)
+	}
+	items, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.CountItem, error) {
+		var item models.CountItem
+		err := row.Scan(&item.Value, &item.Count)
+		return item, err
+	})
+	if err != nil {
+		return nil, customerrors.NewCustomErrorInternal(err)
+	}
+	return items, nil
+}
+
+// SaveDeletionJob сохраняет задание на удаление.
+func (storage *StoragePostgres) SaveDeletionJob(ctx context.Context, job models.DeletionJob) error {
+	query := `
+	insert into deletion_jobs(id, user_id, status, urls, attempts, last_error, created_at, updated_at, next_attempt_at)
+	values($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	on conflict(id) do update set
+		status = excluded.status,
+		urls = excluded.urls,
+		attempts = excluded.attempts,
+		last_error = excluded.last_error,
+		updated_at = excluded.updated_at,
+		next_attempt_at = excluded.next_attempt_at`
+	urls, err := json.Marshal(job.URLs)
+	if err != nil {
+		return customerrors.NewCustomErrorInternal(err)
+	}
+	_, err = storage.pool.Exec(ctx, query,
+		job.ID, job.UserID, job.Status, urls, job.Attempts, job.LastError, job.CreatedAt, job.UpdatedAt, job.NextAttemptAt)
+	if err != nil {
+		return customerrors.NewCustomErrorInternal(err)
+	}
+	return nil
+}
+
+const selectDeletionJobQuery = `
+	select id, user_id, status, urls, attempts, last_error, created_at, updated_at, next_attempt_at
+	from deletion_jobs`
+
+// FindDeletionJob находит задание на удаление по идентификатору.
+func (storage *StoragePostgres) FindDeletionJob(ctx context.Context, id string) (*models.DeletionJob, error) {
+	rows, err := storage.pool.Query(ctx, selectDeletionJobQuery+" where id = $1", id)
+	if err != nil {
+		return nil, customerrors.NewCustomErrorInternal(err)
+	}
+	job, err := pgx.CollectOneRow(rows, scanDeletionJob)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, customerrors.NewCustomErrorBadRequest(customerrors.ErrDeletionJobNotFound)
+	}
+	if err != nil {
+		return nil, customerrors.NewCustomErrorInternal(err)
+	}
+	return &job, nil
+}
+
+// FindPendingDeletionJobs возвращает незавершенные задания на удаление, очередная попытка которых наступила к моменту now.
+func (storage *StoragePostgres) FindPendingDeletionJobs(ctx context.Context, now time.Time, limit int) ([]models.DeletionJob, error) {
+	query := selectDeletionJobQuery + `
+	where status = $1 and next_attempt_at <= $2
+	order by next_attempt_at
+	limit $3`
+	rows, err := storage.pool.Query(ctx, query, models.DeletionJobPending, now, limit)
+	if err != nil {
+		return nil, customerrors.NewCustomErrorInternal(err)
+	}
+	jobs, err := pgx.CollectRows(rows, scanDeletionJob)
+	if err != nil {
+		return nil, customerrors.NewCustomErrorInternal(err)
+	}
+	return jobs, nil
+}
+
+// CountPendingDeletionJobs возвращает количество незавершенных заданий на удаление.
+func (storage *StoragePostgres) CountPendingDeletionJobs(ctx context.Context) (int, error) {
+	var count int
+	err := storage.pool.QueryRow(ctx, "select count(*) from deletion_jobs where status = $1", models.DeletionJobPending).Scan(&count)
+	if err != nil {
+		return 0, customerrors.NewCustomErrorInternal(err)
+	}
+	return count, nil
+}
+
+// DeleteFinishedDeletionJobs удаляет завершенные задания на удаление, последнее изменение которых было раньше before.
+func (storage *StoragePostgres) DeleteFinishedDeletionJobs(ctx context.Context, before time.Time) (int, error) {
+	query := "delete from deletion_jobs where status <> $1 and updated_at < $2"
+	tag, err := storage.pool.Exec(ctx, query, models.DeletionJobPending, before)
+	if err != nil {
+		return 0, customerrors.NewCustomErrorInternal(err)
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+func scanDeletionJob(row pgx.CollectableRow) (models.DeletionJob, error) {
+	var job models.DeletionJob
+	var urls []byte
+	err := row.Scan(&job.ID, &job.UserID, &job.Status, &urls, &job.Attempts, &job.LastError, &job.CreatedAt, &job.UpdatedAt, &job.NextAttemptAt)
+	if err != nil {
+		return job, err
+	}
+	err = json.Unmarshal(urls, &job.URLs)
+	return job, err
+}
@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/tracing"
+	"github.com/jackc/pgx/v5"
+)
+
+// queryTracer создает спаны запросов pgx: отдельных запросов, пакетов запросов и COPY.
+type queryTracer struct{}
+
+var (
+	_ pgx.QueryTracer    = queryTracer{}
+	_ pgx.BatchTracer    = queryTracer{}
+	_ pgx.CopyFromTracer = queryTracer{}
+)
+
+type querySpanKey struct{}
+
+// TraceQueryStart создает спан запроса.
+func (queryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return startQuerySpan(ctx, queryName(data.SQL), tracing.String("db.statement", data.SQL))
+}
+
+// TraceQueryEnd завершает спан запроса.
+func (queryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	endQuerySpan(ctx, data.Err, data.CommandTag.RowsAffected())
+}
+
+// TraceBatchStart создает спан пакета запросов.
+func (queryTracer) TraceBatchStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceBatchStartData) context.Context {
+	return startQuerySpan(ctx, "postgres.batch", tracing.Int("db.batch.size", data.Batch.Len()))
+}
+
+// TraceBatchQuery записывает ошибку запроса из пакета.
+func (queryTracer) TraceBatchQuery(ctx context.Context, _ *pgx.Conn, data pgx.TraceBatchQueryData) {
+	if span, ok := ctx.Value(querySpanKey{}).(*tracing.Span); ok && data.Err != nil && !errors.Is(data.Err, pgx.ErrNoRows) {
+		span.RecordError(data.Err)
+	}
+}
+
+// TraceBatchEnd завершает спан пакета запросов.
+func (queryTracer) TraceBatchEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceBatchEndData) {
+	endQuerySpan(ctx, data.Err, -1)
+}
+
+// TraceCopyFromStart создает спан COPY.
+func (queryTracer) TraceCopyFromStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceCopyFromStartData) context.Context {
+	return startQuerySpan(ctx, "postgres.copy", tracing.String("db.sql.table", data.TableName.Sanitize()))
+}
+
+// TraceCopyFromEnd завершает спан COPY.
+func (queryTracer) TraceCopyFromEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceCopyFromEndData) {
+	endQuerySpan(ctx, data.Err, data.CommandTag.RowsAffected())
+}
+
+func startQuerySpan(ctx context.Context, name string, attrs ...tracing.Attribute) context.Context {
+	attrs = append(attrs, tracing.String("db.system", "postgresql"))
+	ctx, span := tracing.StartKind(ctx, tracing.KindClient, name, attrs...)
+	if span == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, querySpanKey{}, span)
+}
+
+// endQuerySpan завершает спан из контекста. Отрицательное rows означает, что количество строк неизвестно.
+func endQuerySpan(ctx context.Context, err error, rows int64) {
+	span, ok := ctx.Value(querySpanKey{}).(*tracing.Span)
+	if !ok {
+		return
+	}
+	if rows >= 0 {
+		span.SetAttributes(tracing.Int("db.rows_affected", int(rows)))
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		span.RecordError(err)
+	}
+	span.End()
+}
+
+// queryName возвращает название спана запроса по первому слову SQL, например postgres.select.
+func queryName(sql string) string {
+	operation, _, _ := strings.Cut(strings.TrimSpace(sql), " ")
+	if operation == "" {
+		return "postgres.query"
+	}
+	return "postgres." + strings.ToLower(operation)
+}
@@ -3,6 +3,7 @@ package storage
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/config"
 	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/models"
@@ -23,26 +24,94 @@ const (
 	StorageTypePostgres StorageType = "postgres"
 )
 
+// ClickStorage определяет методы для сохранения событий перехода по сокращенным URL.
+type ClickStorage interface {
+	// SaveClicks сохраняет список событий перехода в хранилище.
+	SaveClicks(ctx context.Context, clicks []models.Click) error
+	// GetClickStats возвращает статистику переходов по сокращенному URL.
+	// В списки самых частых источников и клиентов попадает не более top значений.
+	GetClickStats(ctx context.Context, shortURL string, top int) (models.LinkStats, error)
+}
+
+// DeletionJobStorage определяет методы для хранения заданий на удаление URL.
+type DeletionJobStorage interface {
+	// SaveDeletionJob сохраняет задание на удаление, заменяя ранее сохраненное задание с тем же идентификатором.
+	SaveDeletionJob(ctx context.Context, job models.DeletionJob) error
+	// FindDeletionJob находит задание на удаление по идентификатору.
+	FindDeletionJob(ctx context.Context, id string) (*models.DeletionJob, error)
+	// FindPendingDeletionJobs возвращает не более limit незавершенных заданий, очередная попытка которых наступила к моменту now.
+	FindPendingDeletionJobs(ctx context.Context, now time.Time, limit int) ([]models.DeletionJob, error)
+	// CountPendingDeletionJobs возвращает количество незавершенных заданий, включая ожидающие повторной попытки.
+	CountPendingDeletionJobs(ctx context.Context) (int, error)
+	// DeleteFinishedDeletionJobs удаляет завершенные задания, последнее изменение которых было раньше before, и возвращает их количество.
+	DeleteFinishedDeletionJobs(ctx context.Context, before time.Time) (int, error)
+}
+
+// UserStorage определяет методы для работы с пользователями в хранилище.
+type UserStorage interface {
+	// CreateUser регистрирует нового пользователя с новым идентификатором.
+	// Идентификаторы не повторяются, в том числе после перезапуска и между экземплярами сервиса с общим хранилищем.
+	CreateUser(ctx context.Context) (models.User, error)
+}
+
+// SequenceStorage определяет методы для выдачи номеров последовательности сокращенных URL.
+type SequenceStorage interface {
+	// NextSequence возвращает следующий номер последовательности сокращенных URL, начиная с 1.
+	// Номера не повторяются, в том числе после перезапуска, но могут идти с пропусками.
+	NextSequence(ctx context.Context) (int64, error)
+}
+
+// AdminStorage определяет методы администрирования URL в хранилище.
+type AdminStorage interface {
+	// SetURLDisabled отключает или включает URL. Если URL не найден, возвращается ErrURLNotFound.
+	SetURLDisabled(ctx context.Context, shortURL string, disabled bool) error
+	// SetURLOwner передает URL пользователю userID. Если URL не найден, возвращается ErrURLNotFound,
+	// если пользователь не зарегистрирован — ErrUserNotFound.
+	SetURLOwner(ctx context.Context, shortURL string, userID int) error
+	// DeleteUrlsOfUser помечает удаленными все URL пользователя userID и возвращает сокращенные URL,
+	// которые были помечены этим вызовом. Удаленные ранее URL не возвращаются.
+	DeleteUrlsOfUser(ctx context.Context, userID int) ([]string, error)
+	// DeleteUrlsOfDomain помечает удаленными все URL, исходный URL которых ведет на домен domain или его поддомен,
+	// и возвращает сокращенные URL, которые были помечены этим вызовом. domain должен быть в нижнем регистре.
+	DeleteUrlsOfDomain(ctx context.Context, domain string) ([]string, error)
+}
+
 // ShortenerStorage определяет методы для взаимодействия с хранилищем URL-ов.
 type ShortenerStorage interface {
+	ClickStorage
+	DeletionJobStorage
+	UserStorage
+	SequenceStorage
+	AdminStorage
 	// FindByShortURL находит оригинальный URL по сокращенному URL.
 	FindByShortURL(ctx context.Context, shortURL string) (*models.URL, error)
-	// Save сохраняет URL в хранилище.
+	// Save сохраняет URL в хранилище. Если сокращенный URL занят, возвращается ErrShortURLAlreadyExists;
+	// проверка и сохранение выполняются атомарно.
 	Save(ctx context.Context, url models.URL) error
-	// SaveBatch сохраняет список URL в хранилище.
-	SaveBatch(ctx context.Context, urls []models.URL) error
+	// SaveBatchPartial сохраняет URL из списка независимо друг от друга.
+	// Возвращает ошибки сохранения отдельных URL по их индексам: nil — URL сохранен, конфликт с ShortURL — найден дубликат
+	// при дедупликации, ErrShortURLAlreadyExists — сокращенный URL занят. Вторая ошибка — ошибка хранилища, при которой ничего не сохранено.
+	SaveBatchPartial(ctx context.Context, urls []models.URL) ([]error, error)
 	// Ping проверяет доступность хранилища.
 	Ping(ctx context.Context) bool
-	// FindByUser находит URL, созданные конкретным пользователем.
-	FindByUser(ctx context.Context, userID int) ([]models.URL, error)
-	// GetUserID возвращает идентификатор пользователя из контекста.
-	GetUserID(ctx context.Context) int
-	// DeleteUrls удаляет список URL из хранилища.
-	DeleteUrls(ctx context.Context, urls []models.URLToDelete) error
+	// FindByUser находит URL, созданные конкретным пользователем, с учетом фильтров, сортировки и курсора запроса.
+	// Возвращает не более query.Limit URL.
+	FindByUser(ctx context.Context, userID int, query models.URLQuery) ([]models.URL, error)
+	// DeleteUrls помечает удаленными URL из списка, созданные указанными пользователями, и запоминает время удаления.
+	// Возвращает сокращенные URL, которые принадлежат пользователям и помечены удаленными, в том числе удаленные ранее,
+	// поэтому повторный вызов с тем же списком возвращает тот же результат.
+	DeleteUrls(ctx context.Context, urls []models.URLToDelete) ([]string, error)
+	// RestoreUrls снимает пометку удаления с URL из списка, созданных пользователем userID и удаленных не раньше deletedAfter.
+	// Возвращает сокращенные URL, которые были восстановлены.
+	RestoreUrls(ctx context.Context, userID int, shortURLs []string, deletedAfter time.Time) ([]string, error)
 	// IsShortURLExists проверяет, существует ли указанный сокращенный URL в хранилище.
 	IsShortURLExists(ctx context.Context, shortURL string) (bool, error)
 	// GetStats возвращает статистику по хранилищу.
 	GetStats(ctx context.Context) (models.Stats, error)
+	// DeleteExpired удаляет из хранилища URL, срок действия которых истек к моменту now, и возвращает их количество.
+	DeleteExpired(ctx context.Context, now time.Time) (int, error)
+	// Close освобождает ресурсы хранилища. После вызова Close хранилище использовать нельзя.
+	Close() error
 }
 
 // GetStorageTypeByConfig возвращает тип хранилища на основе конфигурации.
@@ -0,0 +1,82 @@
+// Package urlquery предоставляет функции для фильтрации, сортировки и постраничной выборки URL пользователя.
+// Используется хранилищами, которые не умеют выполнять запросы самостоятельно.
+package urlquery
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/models"
+)
+
+// SortValue возвращает значение поля сортировки URL в строковом виде для курсора.
+func SortValue(url models.URL, sortBy string) string {
+	switch sortBy {
+	case models.SortByShortURL:
+		return url.ShortURL
+	case models.SortByOriginalURL:
+		return url.OriginalURL
+	default:
+		return url.CreatedTS.UTC().Format(time.RFC3339Nano)
+	}
+}
+
+// Apply фильтрует, сортирует и ограничивает список URL в соответствии с запросом.
+func Apply(urls []models.URL, query models.URLQuery) []models.URL {
+	result := make([]models.URL, 0)
+	for _, url := range urls {
+		if match(url, query) {
+			result = append(result, url)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		cmp := compare(result[i], SortValue(result[j], query.SortBy), result[j].ShortURL, query.SortBy)
+		if query.SortDesc {
+			return cmp > 0
+		}
+		return cmp < 0
+	})
+	if query.Limit > 0 && len(result) > query.Limit {
+		result = result[:query.Limit]
+	}
+	return result
+}
+
+func match(url models.URL, query models.URLQuery) bool {
+	if !query.CreatedFrom.IsZero() && url.CreatedTS.Before(query.CreatedFrom) {
+		return false
+	}
+	if !query.CreatedTo.IsZero() && !url.CreatedTS.Before(query.CreatedTo) {
+		return false
+	}
+	if query.OriginalURLContains != "" && !strings.Contains(url.OriginalURL, query.OriginalURLContains) {
+		return false
+	}
+	if query.Deleted != nil && url.IsDeleted != *query.Deleted {
+		return false
+	}
+	if query.Cursor != nil {
+		cmp := compare(url, query.Cursor.Value, query.Cursor.ShortURL, query.SortBy)
+		if query.SortDesc {
+			return cmp < 0
+		}
+		return cmp > 0
+	}
+	return true
+}
+
+// compare сравнивает URL с позицией (value, shortURL) по полю сортировки, а при равенстве — по сокращенному URL.
+func compare(url models.URL, value string, shortURL string, sortBy string) int {
+	var cmp int
+	if sortBy == models.SortByShortURL || sortBy == models.SortByOriginalURL {
+		cmp = strings.Compare(SortValue(url, sortBy), value)
+	} else {
+		ts, _ := time.Parse(time.RFC3339Nano, value)
+		cmp = url.CreatedTS.Compare(ts)
+	}
+	if cmp != 0 {
+		return cmp
+	}
+	return strings.Compare(url.ShortURL, shortURL)
+}
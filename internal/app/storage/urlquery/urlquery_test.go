@@ -0,0 +1,66 @@
+package urlquery
+
+import (
+	"testing"
+	"time"
+
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApply(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	deleted := true
+	urls := []models.URL{
+		{ShortURL: "b", OriginalURL: "https://example.com/b", CreatedTS: now},
+		{ShortURL: "a", OriginalURL: "https://example.com/a", CreatedTS: now},
+		{ShortURL: "c", OriginalURL: "https://example.org/c", CreatedTS: now.Add(time.Hour), IsDeleted: true},
+		{ShortURL: "d", OriginalURL: "https://example.org/d", CreatedTS: now.Add(-time.Hour)},
+	}
+	shortURLs := func(urls []models.URL) []string {
+		result := make([]string, 0, len(urls))
+		for _, url := range urls {
+			result = append(result, url.ShortURL)
+		}
+		return result
+	}
+	tests := []struct {
+		name     string
+		query    models.URLQuery
+		expected []string
+	}{
+		{
+			name:     "created_ts with tie broken by short url",
+			query:    models.URLQuery{SortBy: models.SortByCreatedTS},
+			expected: []string{"d", "a", "b", "c"},
+		},
+		{
+			name:     "desc with limit",
+			query:    models.URLQuery{SortBy: models.SortByShortURL, SortDesc: true, Limit: 2},
+			expected: []string{"d", "c"},
+		},
+		{
+			name: "cursor",
+			query: models.URLQuery{
+				SortBy: models.SortByCreatedTS,
+				Cursor: &models.URLCursor{Value: SortValue(urls[1], models.SortByCreatedTS), ShortURL: "a"},
+			},
+			expected: []string{"b", "c"},
+		},
+		{
+			name:     "filters",
+			query:    models.URLQuery{OriginalURLContains: "example.org", Deleted: &deleted},
+			expected: []string{"c"},
+		},
+		{
+			name:     "created range",
+			query:    models.URLQuery{CreatedFrom: now, CreatedTo: now.Add(time.Hour), SortBy: models.SortByOriginalURL},
+			expected: []string{"a", "b"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, shortURLs(Apply(urls, test.query)))
+		})
+	}
+}
@@ -0,0 +1,155 @@
+// Package token предоставляет выпуск и проверку JWT, которыми аутентифицируются пользователи
+// HTTP и gRPC серверов.
+package token
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/config"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/logger"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// Claims определяет структуру для хранения JWT.
+type Claims struct {
+	jwt.RegisteredClaims
+	UserID int
+}
+
+// key ключ подписи JWT с идентификатором, который передается в заголовке kid.
+type key struct {
+	id     string
+	secret []byte
+}
+
+// ephemeralKeyID идентификатор случайного ключа, который используется, если ключи подписи не заданы в конфигурации.
+const ephemeralKeyID = "ephemeral"
+
+// ErrKeysRequired ошибка создания Manager без ключей подписи при постоянном хранилище.
+var ErrKeysRequired = errors.New("jwt keys are required with file or database storage: set JWT_KEYS or -j")
+
+const defaultTTL = config.DefaultJWTTTL
+
+// Manager выпускает и проверяет JWT.
+type Manager struct {
+	signKey       key
+	verifyKeys    map[string][]byte
+	ttl           time.Duration
+	refreshBefore time.Duration
+}
+
+// NewManager создает новый экземпляр Manager.
+// Ключи подписи берутся из конфигурации. Если они не заданы, случайный ключ генерируется только для хранилища в памяти,
+// которое, как и выданные токены, не переживает перезапуск. С файловым хранилищем и базой данных ключи обязательны,
+// иначе после перезапуска или на другой реплике пользователи потеряли бы доступ к своим URL.
+func NewManager(config config.Config) (*Manager, error) {
+	keys, err := parseKeys(config.JWTKeys)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		if config.DatabaseURL != "" || config.FileStoragePath != "" {
+			return nil, ErrKeysRequired
+		}
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key{id: ephemeralKeyID, secret: secret})
+		logger.Logger.Warn("jwt keys aren't configured, tokens will be invalidated on restart")
+	}
+	manager := &Manager{
+		signKey:       keys[0],
+		verifyKeys:    make(map[string][]byte, len(keys)),
+		ttl:           config.JWTTTL.Duration,
+		refreshBefore: config.JWTRefreshBefore.Duration,
+	}
+	for _, key := range keys {
+		manager.verifyKeys[key.id] = key.secret
+	}
+	if manager.ttl <= 0 {
+		manager.ttl = defaultTTL
+	}
+	return manager, nil
+}
+
+// parseKeys разбирает список ключей в формате "kid:secret,kid:secret".
+func parseKeys(value string) ([]key, error) {
+	keys := make([]key, 0)
+	ids := make(map[string]struct{})
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		id, secret, ok := strings.Cut(pair, ":")
+		if !ok || id == "" || secret == "" {
+			return nil, errors.New("invalid jwt key: expected kid:secret")
+		}
+		if _, ok := ids[id]; ok {
+			return nil, fmt.Errorf("duplicate jwt key id %q", id)
+		}
+		ids[id] = struct{}{}
+		keys = append(keys, key{id: id, secret: []byte(secret)})
+	}
+	return keys, nil
+}
+
+// Build выпускает новый токен для пользователя и возвращает его вместе со временем истечения.
+func (manager *Manager) Build(userID int) (string, time.Time, error) {
+	expiresAt := time.Now().Add(manager.ttl)
+	token, err := manager.build(userID, expiresAt)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return token, expiresAt, nil
+}
+
+func (manager *Manager) build(userID int, expiresAt time.Time) (string, error) {
+	token := jwt.NewWithClaims(
+		jwt.SigningMethodHS256, &Claims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				IssuedAt:  jwt.NewNumericDate(time.Now()),
+				ExpiresAt: jwt.NewNumericDate(expiresAt),
+			},
+			UserID: userID,
+		},
+	)
+	token.Header["kid"] = manager.signKey.id
+	return token.SignedString(manager.signKey.secret)
+}
+
+// Parse проверяет подпись и срок действия токена ключом, указанным в заголовке kid.
+func (manager *Manager) Parse(token string) (*Claims, error) {
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method %v", t.Header["alg"])
+		}
+		kid, _ := t.Header["kid"].(string)
+		secret, ok := manager.verifyKeys[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown jwt key id %q", kid)
+		}
+		return secret, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if claims.UserID == 0 {
+		return nil, errors.New("jwt doesn't contain user id")
+	}
+	return claims, nil
+}
+
+// ShouldRefresh проверяет, что до истечения токена осталось меньше JWTRefreshBefore и его нужно перевыпустить.
+func (manager *Manager) ShouldRefresh(claims *Claims) bool {
+	if claims.ExpiresAt == nil {
+		return true
+	}
+	return time.Until(claims.ExpiresAt.Time) < manager.refreshBefore
+}
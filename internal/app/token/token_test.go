@@ -0,0 +1,119 @@
+package token
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/config"
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestManager(t *testing.T, keys string) *Manager {
+	config := config.GetDefault()
+	config.JWTKeys = keys
+	manager, err := NewManager(config)
+	require.NoError(t, err)
+	return manager
+}
+
+func TestParse(t *testing.T) {
+	oldKey := newTestManager(t, "old:old-secret")
+	rotated := newTestManager(t, "new:new-secret,old:old-secret")
+	unknownKey := newTestManager(t, "other:other-secret")
+
+	tests := []struct {
+		name           string
+		token          func() (string, error)
+		expectedUserID int
+		wantErr        bool
+	}{
+		{
+			name:           "signed with sign key",
+			token:          func() (string, error) { return rotated.build(1, time.Now().Add(time.Hour)) },
+			expectedUserID: 1,
+		},
+		{
+			name:           "signed with rotated key",
+			token:          func() (string, error) { return oldKey.build(2, time.Now().Add(time.Hour)) },
+			expectedUserID: 2,
+		},
+		{
+			name:    "expired",
+			token:   func() (string, error) { return rotated.build(1, time.Now().Add(-time.Hour)) },
+			wantErr: true,
+		},
+		{
+			name:    "unknown key id",
+			token:   func() (string, error) { return unknownKey.build(1, time.Now().Add(time.Hour)) },
+			wantErr: true,
+		},
+		{
+			name:    "without user id",
+			token:   func() (string, error) { return rotated.build(0, time.Now().Add(time.Hour)) },
+			wantErr: true,
+		},
+		{
+			name:    "malformed",
+			token:   func() (string, error) { return "abc", nil },
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			token, err := test.token()
+			require.NoError(t, err)
+			claims, err := rotated.Parse(token)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedUserID, claims.UserID)
+		})
+	}
+}
+
+func TestShouldRefresh(t *testing.T) {
+	manager := newTestManager(t, "k1:secret")
+	token, expiresAt, err := manager.Build(1)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(config.DefaultJWTTTL), expiresAt, time.Minute)
+	claims, err := manager.Parse(token)
+	require.NoError(t, err)
+	assert.False(t, manager.ShouldRefresh(claims))
+
+	token, err = manager.build(1, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	claims, err = manager.Parse(token)
+	require.NoError(t, err)
+	assert.True(t, manager.ShouldRefresh(claims))
+}
+
+func TestParseKeys(t *testing.T) {
+	keys, err := parseKeys("k1:s1, k2:s2")
+	require.NoError(t, err)
+	assert.Equal(t, []key{{id: "k1", secret: []byte("s1")}, {id: "k2", secret: []byte("s2")}}, keys)
+
+	_, err = parseKeys("secret")
+	assert.Error(t, err)
+	_, err = parseKeys("k1:s1,k1:s2")
+	assert.Error(t, err)
+}
+
+func TestNewManagerRequiresKeys(t *testing.T) {
+	require.NoError(t, logger.Init(slog.LevelInfo))
+	memoryConfig := config.GetDefault()
+	fileConfig := config.GetDefault()
+	fileConfig.FileStoragePath = "/tmp/urls"
+	databaseConfig := config.GetDefaultWithTestDB()
+
+	_, err := NewManager(memoryConfig)
+	require.NoError(t, err, "in-memory storage may use an ephemeral key")
+	_, err = NewManager(fileConfig)
+	assert.ErrorIs(t, err, ErrKeysRequired)
+	_, err = NewManager(databaseConfig)
+	assert.ErrorIs(t, err, ErrKeysRequired)
+}
@@ -0,0 +1,449 @@
+package tracing
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/config"
+)
+
+// Параметры отправки спанов экспортеру.
+const (
+	queueSize      = 2048
+	batchSize      = 512
+	exportInterval = 5 * time.Second
+	exportTimeout  = 10 * time.Second
+)
+
+// StdoutFile значение пути файла трассировки, при котором спаны пишутся в стандартный вывод.
+const StdoutFile = "stdout"
+
+// otlpTracesPath путь приема трассировки коллектором OpenTelemetry по протоколу OTLP/HTTP.
+const otlpTracesPath = "/v1/traces"
+
+// Exporter отправляет завершенные спаны.
+type Exporter interface {
+	// Export отправляет пакет спанов.
+	Export(ctx context.Context, spans []SpanData) error
+	// Shutdown освобождает ресурсы экспортера.
+	Shutdown(ctx context.Context) error
+}
+
+// Tracer собирает завершенные спаны в пакеты и отправляет их экспортеру в фоне.
+// Если экспортер не успевает, новые спаны отбрасываются, чтобы трассировка не задерживала запросы.
+type Tracer struct {
+	exporter Exporter
+	onError  func(error)
+	queue    chan SpanData
+	flush    chan chan struct{}
+	stop     chan struct{}
+	done     chan struct{}
+	once     sync.Once
+}
+
+// NewTracer создает трассировщик с экспортером exporter. Ошибки экспорта передаются onError, если он задан.
+func NewTracer(exporter Exporter, onError func(error)) *Tracer {
+	t := &Tracer{
+		exporter: exporter,
+		onError:  onError,
+		queue:    make(chan SpanData, queueSize),
+		flush:    make(chan chan struct{}),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go t.run()
+	return t
+}
+
+// SetTracer включает трассировку через t. Значение nil выключает трассировку.
+func SetTracer(t *Tracer) {
+	tracer.Store(t)
+}
+
+// Flush отправляет экспортеру все завершенные к моменту вызова спаны.
+func (t *Tracer) Flush(ctx context.Context) error {
+	done := make(chan struct{})
+	select {
+	case t.flush <- done:
+	case <-t.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Shutdown отправляет накопленные спаны и закрывает экспортер. После вызова новые спаны не принимаются.
+func (t *Tracer) Shutdown(ctx context.Context) error {
+	t.once.Do(func() { close(t.stop) })
+	select {
+	case <-t.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return t.exporter.Shutdown(ctx)
+}
+
+// enqueue ставит завершенный спан в очередь на отправку.
+func (t *Tracer) enqueue(data SpanData) {
+	select {
+	case <-t.stop:
+		return
+	default:
+	}
+	select {
+	case t.queue <- data:
+	default:
+	}
+}
+
+func (t *Tracer) run() {
+	defer close(t.done)
+	ticker := time.NewTicker(exportInterval)
+	defer ticker.Stop()
+	batch := make([]SpanData, 0, batchSize)
+	export := func() {
+		if len(batch) == 0 {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+		defer cancel()
+		if err := t.exporter.Export(ctx, batch); err != nil && t.onError != nil {
+			t.onError(err)
+		}
+		batch = batch[:0]
+	}
+	drain := func() {
+		for {
+			select {
+			case data := <-t.queue:
+				batch = append(batch, data)
+				if len(batch) >= batchSize {
+					export()
+				}
+			default:
+				export()
+				return
+			}
+		}
+	}
+	for {
+		select {
+		case data := <-t.queue:
+			batch = append(batch, data)
+			if len(batch) >= batchSize {
+				export()
+			}
+		case <-ticker.C:
+			export()
+		case done := <-t.flush:
+			drain()
+			close(done)
+		case <-t.stop:
+			drain()
+			return
+		}
+	}
+}
+
+// Init включает трассировку по конфигурации: экспорт в коллектор OpenTelemetry, если задан TracingOTLPEndpoint,
+// и в файл или стандартный вывод, если задан TracingFile. Если не задано ни то, ни другое, трассировка не включается.
+// Возвращает функцию, которая выключает трассировку и отправляет накопленные спаны.
+func Init(config config.Config, onError func(error)) (func(context.Context) error, error) {
+	var exporters multiExporter
+	if config.TracingOTLPEndpoint != "" {
+		exporter, err := NewOTLPExporter(config.TracingOTLPEndpoint, config.TracingServiceName)
+		if err != nil {
+			return nil, err
+		}
+		exporters = append(exporters, exporter)
+	}
+	if config.TracingFile != "" {
+		exporter, err := OpenFileExporter(config.TracingFile)
+		if err != nil {
+			return nil, err
+		}
+		exporters = append(exporters, exporter)
+	}
+	if len(exporters) == 0 {
+		return func(context.Context) error { return nil }, nil
+	}
+	t := NewTracer(exporters, onError)
+	SetTracer(t)
+	return func(ctx context.Context) error {
+		tracer.CompareAndSwap(t, nil)
+		return t.Shutdown(ctx)
+	}, nil
+}
+
+// multiExporter отправляет спаны нескольким экспортерам.
+type multiExporter []Exporter
+
+// Export отправляет пакет спанов каждому экспортеру.
+func (exporters multiExporter) Export(ctx context.Context, spans []SpanData) error {
+	var errs []error
+	for _, exporter := range exporters {
+		errs = append(errs, exporter.Export(ctx, spans))
+	}
+	return errors.Join(errs...)
+}
+
+// Shutdown закрывает каждый экспортер.
+func (exporters multiExporter) Shutdown(ctx context.Context) error {
+	var errs []error
+	for _, exporter := range exporters {
+		errs = append(errs, exporter.Shutdown(ctx))
+	}
+	return errors.Join(errs...)
+}
+
+// OTLPExporter отправляет спаны в коллектор OpenTelemetry по протоколу OTLP/HTTP в формате JSON.
+type OTLPExporter struct {
+	endpoint    string
+	serviceName string
+	client      *http.Client
+}
+
+// NewOTLPExporter создает экспортер в коллектор по адресу endpoint, например http://localhost:4318.
+// Если в адресе не указан путь, используется стандартный путь /v1/traces.
+func NewOTLPExporter(endpoint, serviceName string) (*OTLPExporter, error) {
+	parsed, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("tracing otlp endpoint %q must be an http or https url", endpoint)
+	}
+	if parsed.Path == "" || parsed.Path == "/" {
+		parsed.Path = otlpTracesPath
+	}
+	return &OTLPExporter{
+		endpoint:    parsed.String(),
+		serviceName: serviceName,
+		client:      &http.Client{Timeout: exportTimeout},
+	}, nil
+}
+
+// Export отправляет пакет спанов в коллектор.
+func (exporter *OTLPExporter) Export(ctx context.Context, spans []SpanData) error {
+	body, err := json.Marshal(exporter.request(spans))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, exporter.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := exporter.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	io.Copy(io.Discard, res.Body)
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("otlp collector responded with status %d", res.StatusCode)
+	}
+	return nil
+}
+
+// Shutdown закрывает неиспользуемые соединения с коллектором.
+func (exporter *OTLPExporter) Shutdown(context.Context) error {
+	exporter.client.CloseIdleConnections()
+	return nil
+}
+
+// Структуры запроса ExportTraceServiceRequest в JSON-кодировке OTLP.
+type (
+	otlpRequest struct {
+		ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+	}
+	otlpResourceSpans struct {
+		Resource   otlpResource     `json:"resource"`
+		ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+	}
+	otlpResource struct {
+		Attributes []otlpAttribute `json:"attributes"`
+	}
+	otlpScopeSpans struct {
+		Scope otlpScope  `json:"scope"`
+		Spans []otlpSpan `json:"spans"`
+	}
+	otlpScope struct {
+		Name string `json:"name"`
+	}
+	otlpSpan struct {
+		TraceID           string          `json:"traceId"`
+		SpanID            string          `json:"spanId"`
+		ParentSpanID      string          `json:"parentSpanId,omitempty"`
+		Name              string          `json:"name"`
+		Kind              SpanKind        `json:"kind"`
+		StartTimeUnixNano string          `json:"startTimeUnixNano"`
+		EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+		Attributes        []otlpAttribute `json:"attributes,omitempty"`
+		Status            otlpStatus      `json:"status"`
+	}
+	otlpStatus struct {
+		Code    int    `json:"code,omitempty"`
+		Message string `json:"message,omitempty"`
+	}
+	otlpAttribute struct {
+		Key   string         `json:"key"`
+		Value map[string]any `json:"value"`
+	}
+)
+
+// Коды статуса спана OTLP.
+const (
+	otlpStatusOK    = 1
+	otlpStatusError = 2
+)
+
+// instrumentationScope название библиотеки трассировки в данных OTLP.
+const instrumentationScope = "github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/tracing"
+
+func (exporter *OTLPExporter) request(spans []SpanData) otlpRequest {
+	otlpSpans := make([]otlpSpan, len(spans))
+	for i, span := range spans {
+		otlpSpans[i] = otlpSpan{
+			TraceID:           span.TraceID.String(),
+			SpanID:            span.SpanID.String(),
+			Name:              span.Name,
+			Kind:              span.Kind,
+			StartTimeUnixNano: strconv.FormatInt(span.Start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(span.End.UnixNano(), 10),
+			Attributes:        otlpAttributes(span.Attributes),
+			Status:            otlpStatus{Code: otlpStatusOK},
+		}
+		if span.ParentSpanID.IsValid() {
+			otlpSpans[i].ParentSpanID = span.ParentSpanID.String()
+		}
+		if span.Error != "" {
+			otlpSpans[i].Status = otlpStatus{Code: otlpStatusError, Message: span.Error}
+		}
+	}
+	return otlpRequest{ResourceSpans: []otlpResourceSpans{{
+		Resource:   otlpResource{Attributes: otlpAttributes([]Attribute{String("service.name", exporter.serviceName)})},
+		ScopeSpans: []otlpScopeSpans{{Scope: otlpScope{Name: instrumentationScope}, Spans: otlpSpans}},
+	}}}
+}
+
+func otlpAttributes(attrs []Attribute) []otlpAttribute {
+	result := make([]otlpAttribute, 0, len(attrs))
+	for _, attr := range attrs {
+		var value map[string]any
+		switch v := attr.Value.(type) {
+		case string:
+			value = map[string]any{"stringValue": v}
+		case int64:
+			value = map[string]any{"intValue": strconv.FormatInt(v, 10)}
+		case bool:
+			value = map[string]any{"boolValue": v}
+		case float64:
+			value = map[string]any{"doubleValue": v}
+		default:
+			value = map[string]any{"stringValue": fmt.Sprint(v)}
+		}
+		result = append(result, otlpAttribute{Key: attr.Key, Value: value})
+	}
+	return result
+}
+
+// FileExporter пишет спаны построчно в формате JSON, чтобы их можно было читать при локальном запуске.
+type FileExporter struct {
+	mu     sync.Mutex
+	writer *bufio.Writer
+	closer io.Closer
+}
+
+// fileSpan представление спана в файле трассировки.
+type fileSpan struct {
+	Name         string         `json:"name"`
+	Kind         string         `json:"kind"`
+	TraceID      string         `json:"trace_id"`
+	SpanID       string         `json:"span_id"`
+	ParentSpanID string         `json:"parent_span_id,omitempty"`
+	Start        time.Time      `json:"start"`
+	DurationMS   float64        `json:"duration_ms"`
+	Attributes   map[string]any `json:"attributes,omitempty"`
+	Error        string         `json:"error,omitempty"`
+}
+
+// NewFileExporter создает экспортер, который пишет спаны в writer. Если writer реализует io.Closer, он закрывается в Shutdown.
+func NewFileExporter(writer io.Writer) *FileExporter {
+	exporter := &FileExporter{writer: bufio.NewWriter(writer)}
+	if closer, ok := writer.(io.Closer); ok {
+		exporter.closer = closer
+	}
+	return exporter
+}
+
+// OpenFileExporter создает экспортер, который дописывает спаны в файл path, или в стандартный вывод, если path равен "stdout".
+func OpenFileExporter(path string) (*FileExporter, error) {
+	if path == StdoutFile {
+		return &FileExporter{writer: bufio.NewWriter(os.Stdout)}, nil
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return NewFileExporter(file), nil
+}
+
+// Export записывает пакет спанов.
+func (exporter *FileExporter) Export(_ context.Context, spans []SpanData) error {
+	exporter.mu.Lock()
+	defer exporter.mu.Unlock()
+	encoder := json.NewEncoder(exporter.writer)
+	for _, span := range spans {
+		record := fileSpan{
+			Name:       span.Name,
+			Kind:       span.Kind.String(),
+			TraceID:    span.TraceID.String(),
+			SpanID:     span.SpanID.String(),
+			Start:      span.Start,
+			DurationMS: float64(span.End.Sub(span.Start).Microseconds()) / 1000,
+			Error:      span.Error,
+		}
+		if span.ParentSpanID.IsValid() {
+			record.ParentSpanID = span.ParentSpanID.String()
+		}
+		if len(span.Attributes) > 0 {
+			record.Attributes = make(map[string]any, len(span.Attributes))
+			for _, attr := range span.Attributes {
+				record.Attributes[attr.Key] = attr.Value
+			}
+		}
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+	return exporter.writer.Flush()
+}
+
+// Shutdown закрывает файл трассировки.
+func (exporter *FileExporter) Shutdown(context.Context) error {
+	exporter.mu.Lock()
+	defer exporter.mu.Unlock()
+	err := exporter.writer.Flush()
+	if exporter.closer != nil {
+		err = errors.Join(err, exporter.closer.Close())
+	}
+	return err
+}
@@ -0,0 +1,302 @@
+// Package tracing предоставляет распределенную трассировку запросов, совместимую с OpenTelemetry.
+//
+// Контекст трассировки передается между сервисами заголовком W3C traceparent, а завершенные спаны
+// отправляются экспортерами: по протоколу OTLP/HTTP в формате JSON в коллектор OpenTelemetry
+// или построчно в формате JSON в файл или стандартный вывод для локального запуска.
+//
+// Трассировка включается функцией Init. Пока она не вызвана, Start не создает спаны и почти ничего не стоит,
+// а методы Span безопасно вызывать у nil.
+//
+// Пример использования:
+//
+//	ctx, span := tracing.Start(ctx, "service.GetByShortURL", tracing.String("short_url", shortURL))
+//	defer span.End()
+//	url, err := storage.FindByShortURL(ctx, shortURL)
+//	span.RecordError(err)
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// TraceID идентификатор трассировки.
+type TraceID [16]byte
+
+// String возвращает идентификатор в шестнадцатеричном виде.
+func (id TraceID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// IsValid проверяет, что идентификатор не нулевой.
+func (id TraceID) IsValid() bool {
+	return id != TraceID{}
+}
+
+// SpanID идентификатор спана.
+type SpanID [8]byte
+
+// String возвращает идентификатор в шестнадцатеричном виде.
+func (id SpanID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// IsValid проверяет, что идентификатор не нулевой.
+func (id SpanID) IsValid() bool {
+	return id != SpanID{}
+}
+
+// SpanContext контекст спана, который передается между сервисами.
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	// Sampled флаг записи трассировки: спаны трассировки без флага не создаются.
+	Sampled bool
+	// Remote признак контекста, полученного от другого сервиса.
+	Remote bool
+}
+
+// IsValid проверяет, что идентификаторы трассировки и спана заданы.
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+// Traceparent возвращает значение заголовка W3C traceparent.
+func (sc SpanContext) Traceparent() string {
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+	return "00-" + sc.TraceID.String() + "-" + sc.SpanID.String() + "-" + flags
+}
+
+// ParseTraceparent разбирает значение заголовка W3C traceparent.
+// Возвращает false, если значение не соответствует формату или содержит нулевые идентификаторы.
+func ParseTraceparent(value string) (SpanContext, bool) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || (parts[0] == "00" && len(parts) != 4) {
+		return SpanContext{}, false
+	}
+	var sc SpanContext
+	var version, flags [1]byte
+	if !decodeHex(version[:], parts[0]) || !decodeHex(sc.TraceID[:], parts[1]) ||
+		!decodeHex(sc.SpanID[:], parts[2]) || !decodeHex(flags[:], parts[3]) || !sc.IsValid() {
+		return SpanContext{}, false
+	}
+	sc.Sampled = flags[0]&1 == 1
+	sc.Remote = true
+	return sc, true
+}
+
+// decodeHex декодирует строку из строчных шестнадцатеричных цифр ровно в dst.
+func decodeHex(dst []byte, s string) bool {
+	if len(s) != hex.EncodedLen(len(dst)) || strings.ToLower(s) != s {
+		return false
+	}
+	_, err := hex.Decode(dst, []byte(s))
+	return err == nil
+}
+
+type spanContextKey struct{}
+
+// ContextWithSpanContext возвращает контекст с контекстом спана sc, например полученным от другого сервиса.
+func ContextWithSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, spanContextKey{}, sc)
+}
+
+// SpanContextFromContext возвращает контекст текущего спана. Если спана нет, возвращается пустой контекст.
+func SpanContextFromContext(ctx context.Context) SpanContext {
+	sc, _ := ctx.Value(spanContextKey{}).(SpanContext)
+	return sc
+}
+
+// SpanKind вид спана. Значения совпадают с SpanKind протокола OTLP.
+type SpanKind int
+
+// Виды спанов.
+const (
+	KindInternal SpanKind = 1
+	KindServer   SpanKind = 2
+	KindClient   SpanKind = 3
+)
+
+// String возвращает название вида спана.
+func (kind SpanKind) String() string {
+	switch kind {
+	case KindServer:
+		return "server"
+	case KindClient:
+		return "client"
+	default:
+		return "internal"
+	}
+}
+
+// Attribute атрибут спана. Значение может быть строкой, целым числом, числом с плавающей точкой или логическим.
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// String создает строковый атрибут.
+func String(key, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Int создает целочисленный атрибут.
+func Int(key string, value int) Attribute {
+	return Attribute{Key: key, Value: int64(value)}
+}
+
+// Bool создает логический атрибут.
+func Bool(key string, value bool) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// SpanData данные завершенного спана, которые получает экспортер.
+type SpanData struct {
+	Name         string
+	Kind         SpanKind
+	TraceID      TraceID
+	SpanID       SpanID
+	ParentSpanID SpanID
+	Start        time.Time
+	End          time.Time
+	Attributes   []Attribute
+	// Error описание ошибки, пустое значение означает успешное завершение.
+	Error string
+}
+
+// Span операция в трассировке.
+type Span struct {
+	tracer *Tracer
+	mu     sync.Mutex
+	data   SpanData
+	ended  bool
+}
+
+// SpanContext возвращает контекст спана.
+func (span *Span) SpanContext() SpanContext {
+	if span == nil {
+		return SpanContext{}
+	}
+	return SpanContext{TraceID: span.data.TraceID, SpanID: span.data.SpanID, Sampled: true}
+}
+
+// SetName изменяет название спана, например когда шаблон маршрута становится известен после маршрутизации.
+func (span *Span) SetName(name string) {
+	if span == nil {
+		return
+	}
+	span.mu.Lock()
+	defer span.mu.Unlock()
+	span.data.Name = name
+}
+
+// SetAttributes добавляет атрибуты спана.
+func (span *Span) SetAttributes(attrs ...Attribute) {
+	if span == nil {
+		return
+	}
+	span.mu.Lock()
+	defer span.mu.Unlock()
+	span.data.Attributes = append(span.data.Attributes, attrs...)
+}
+
+// RecordError отмечает спан как завершившийся ошибкой err. Вызов с nil ничего не делает.
+func (span *Span) RecordError(err error) {
+	if span == nil || err == nil {
+		return
+	}
+	span.mu.Lock()
+	defer span.mu.Unlock()
+	span.data.Error = err.Error()
+}
+
+// End завершает спан и передает его экспортеру. Повторные вызовы игнорируются.
+func (span *Span) End() {
+	if span == nil {
+		return
+	}
+	span.mu.Lock()
+	if span.ended {
+		span.mu.Unlock()
+		return
+	}
+	span.ended = true
+	span.data.End = time.Now()
+	data := span.data
+	span.mu.Unlock()
+	span.tracer.enqueue(data)
+}
+
+// EndWithError отмечает спан ошибкой *err, если она есть, и завершает его.
+// Предназначен для вызова в defer с именованным результатом-ошибкой.
+func (span *Span) EndWithError(err *error) {
+	if span == nil {
+		return
+	}
+	span.RecordError(*err)
+	span.End()
+}
+
+// tracer трассировщик, через который создаются спаны, nil если трассировка не включена.
+var tracer atomic.Pointer[Tracer]
+
+// Start создает внутренний спан name, дочерний к спану из ctx, и возвращает контекст с новым спаном.
+// Если трассировка не включена или родительский спан не записывается, возвращается nil-спан.
+func Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, *Span) {
+	return StartKind(ctx, KindInternal, name, attrs...)
+}
+
+// StartKind создает спан вида kind, дочерний к спану из ctx, и возвращает контекст с новым спаном.
+func StartKind(ctx context.Context, kind SpanKind, name string, attrs ...Attribute) (context.Context, *Span) {
+	t := tracer.Load()
+	if t == nil {
+		return ctx, nil
+	}
+	parent := SpanContextFromContext(ctx)
+	if parent.IsValid() && !parent.Sampled {
+		return ctx, nil
+	}
+	span := &Span{tracer: t, data: SpanData{
+		Name:         name,
+		Kind:         kind,
+		TraceID:      parent.TraceID,
+		ParentSpanID: parent.SpanID,
+		Start:        time.Now(),
+		Attributes:   attrs,
+	}}
+	if !parent.IsValid() {
+		span.data.TraceID = newTraceID()
+		span.data.ParentSpanID = SpanID{}
+	}
+	span.data.SpanID = newSpanID()
+	return ContextWithSpanContext(ctx, span.SpanContext()), span
+}
+
+func newTraceID() TraceID {
+	var id TraceID
+	for !id.IsValid() {
+		if _, err := rand.Read(id[:]); err != nil {
+			panic(fmt.Sprintf("tracing: generate trace id: %v", err))
+		}
+	}
+	return id
+}
+
+func newSpanID() SpanID {
+	var id SpanID
+	for !id.IsValid() {
+		if _, err := rand.Read(id[:]); err != nil {
+			panic(fmt.Sprintf("tracing: generate span id: %v", err))
+		}
+	}
+	return id
+}
@@ -0,0 +1,197 @@
+package tracing
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	sc, ok := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	require.True(t, ok)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", sc.TraceID.String())
+	assert.Equal(t, "00f067aa0ba902b7", sc.SpanID.String())
+	assert.True(t, sc.Sampled)
+	assert.True(t, sc.Remote)
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", sc.Traceparent())
+
+	sc, ok = ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+	require.True(t, ok)
+	assert.False(t, sc.Sampled)
+
+	for _, value := range []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		"00-4bf92f3577b34da6a3ce929d0e0e47-00f067aa0ba902b7-01",
+	} {
+		_, ok := ParseTraceparent(value)
+		assert.False(t, ok, value)
+	}
+}
+
+// recordingExporter запоминает отправленные спаны.
+type recordingExporter struct {
+	mu    sync.Mutex
+	spans []SpanData
+}
+
+func (exporter *recordingExporter) Export(_ context.Context, spans []SpanData) error {
+	exporter.mu.Lock()
+	defer exporter.mu.Unlock()
+	exporter.spans = append(exporter.spans, spans...)
+	return nil
+}
+
+func (exporter *recordingExporter) Shutdown(context.Context) error {
+	return nil
+}
+
+func TestStart(t *testing.T) {
+	ctx := context.Background()
+	_, span := Start(ctx, "disabled")
+	assert.Nil(t, span, "span must not be created while tracing is disabled")
+	span.SetAttributes(String("key", "value"))
+	span.End()
+
+	exporter := &recordingExporter{}
+	tracer := NewTracer(exporter, nil)
+	SetTracer(tracer)
+	defer SetTracer(nil)
+
+	remote, _ := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	serverCtx, server := StartKind(ContextWithSpanContext(ctx, remote), KindServer, "GET /{shorturl}")
+	_, child := Start(serverCtx, "service.GetByShortURL", String("short_url", "abc"))
+	child.RecordError(errors.New("not found"))
+	child.End()
+	child.End()
+	server.End()
+
+	unsampled, _ := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+	_, span = Start(ContextWithSpanContext(ctx, unsampled), "unsampled")
+	assert.Nil(t, span, "span must not be created for unsampled parent")
+
+	_, root := Start(ctx, "root")
+	root.End()
+
+	require.NoError(t, tracer.Flush(ctx))
+	require.Len(t, exporter.spans, 3)
+	childData, serverData, rootData := exporter.spans[0], exporter.spans[1], exporter.spans[2]
+	assert.Equal(t, remote.TraceID, serverData.TraceID)
+	assert.Equal(t, remote.SpanID, serverData.ParentSpanID)
+	assert.Equal(t, KindServer, serverData.Kind)
+	assert.Equal(t, remote.TraceID, childData.TraceID)
+	assert.Equal(t, serverData.SpanID, childData.ParentSpanID)
+	assert.Equal(t, "not found", childData.Error)
+	assert.Equal(t, []Attribute{String("short_url", "abc")}, childData.Attributes)
+	assert.NotEqual(t, remote.TraceID, rootData.TraceID)
+	assert.False(t, rootData.ParentSpanID.IsValid())
+	require.NoError(t, tracer.Shutdown(ctx))
+}
+
+func TestOTLPExporter(t *testing.T) {
+	var body map[string]any
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/traces", r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		data, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.NoError(t, json.Unmarshal(data, &body))
+	}))
+	defer collector.Close()
+
+	exporter, err := NewOTLPExporter(collector.URL, "shortener")
+	require.NoError(t, err)
+	start := time.Unix(1, 0)
+	err = exporter.Export(context.Background(), []SpanData{{
+		Name:         "GET /{shorturl}",
+		Kind:         KindServer,
+		TraceID:      TraceID{1},
+		SpanID:       SpanID{2},
+		ParentSpanID: SpanID{3},
+		Start:        start,
+		End:          start.Add(time.Millisecond),
+		Attributes:   []Attribute{String("http.route", "/{shorturl}"), Int("http.response.status_code", 307), Bool("ok", true)},
+		Error:        "failed",
+	}})
+	require.NoError(t, err)
+
+	resource := body["resourceSpans"].([]any)[0].(map[string]any)
+	assert.Equal(t, []any{map[string]any{"key": "service.name", "value": map[string]any{"stringValue": "shortener"}}},
+		resource["resource"].(map[string]any)["attributes"])
+	span := resource["scopeSpans"].([]any)[0].(map[string]any)["spans"].([]any)[0].(map[string]any)
+	assert.Equal(t, "01000000000000000000000000000000", span["traceId"])
+	assert.Equal(t, "0200000000000000", span["spanId"])
+	assert.Equal(t, "0300000000000000", span["parentSpanId"])
+	assert.Equal(t, float64(KindServer), span["kind"])
+	assert.Equal(t, "1000000000", span["startTimeUnixNano"])
+	assert.Equal(t, "1001000000", span["endTimeUnixNano"])
+	assert.Equal(t, map[string]any{"code": float64(otlpStatusError), "message": "failed"}, span["status"])
+	assert.Contains(t, span["attributes"], map[string]any{"key": "http.response.status_code", "value": map[string]any{"intValue": "307"}})
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+	exporter, err = NewOTLPExporter(failing.URL+"/custom/traces", "shortener")
+	require.NoError(t, err)
+	assert.Error(t, exporter.Export(context.Background(), []SpanData{{Name: "span"}}))
+
+	_, err = NewOTLPExporter("localhost:4318", "shortener")
+	assert.Error(t, err)
+}
+
+func TestInitFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traces.jsonl")
+	cfg := config.GetDefault()
+	cfg.TracingFile = path
+	shutdown, err := Init(cfg, nil)
+	require.NoError(t, err)
+
+	ctx, parent := Start(context.Background(), "parent")
+	_, child := Start(ctx, "child", String("key", "value"))
+	child.End()
+	parent.End()
+	require.NoError(t, shutdown(context.Background()))
+	_, span := Start(context.Background(), "after shutdown")
+	assert.Nil(t, span)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var records []map[string]any
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		var record map[string]any
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		records = append(records, record)
+	}
+	require.Len(t, records, 2)
+	assert.Equal(t, "child", records[0]["name"])
+	assert.Equal(t, map[string]any{"key": "value"}, records[0]["attributes"])
+	assert.Equal(t, records[1]["span_id"], records[0]["parent_span_id"])
+	assert.Equal(t, records[1]["trace_id"], records[0]["trace_id"])
+
+	shutdown, err = Init(config.GetDefault(), nil)
+	require.NoError(t, err)
+	_, span = Start(context.Background(), "disabled")
+	assert.Nil(t, span, "tracing must stay disabled without exporters")
+	require.NoError(t, shutdown(context.Background()))
+}
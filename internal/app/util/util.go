@@ -1,15 +1,36 @@
 // Package util предоставляет утилиты для работы с URL.
 package util
 
-import "math/rand"
-
-// GenerateShortURL генерирует случайную короткую строку(размера 5) для использования в качестве сокращенного URL.
-func GenerateShortURL() string {
-	const letterBytes = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
-	const shortURLLength = 5
-	shortURL := make([]byte, shortURLLength)
-	for i := range shortURL {
-		shortURL[i] = letterBytes[rand.Intn(len(letterBytes))]
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+
+	"github.com/GusevGrishaEm1/url-shortener-app.git/internal/app/models"
+)
+
+// ErrInvalidCursor возвращается, если курсор постраничной выборки поврежден.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// EncodeURLCursor кодирует курсор постраничной выборки в непрозрачную строку.
+func EncodeURLCursor(cursor models.URLCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeURLCursor декодирует курсор постраничной выборки, полученный от клиента.
+// Пустая строка означает начало выборки.
+func DecodeURLCursor(value string) (*models.URLCursor, error) {
+	if value == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var cursor models.URLCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ShortURL == "" {
+		return nil, ErrInvalidCursor
 	}
-	return string(shortURL)
+	return &cursor, nil
 }